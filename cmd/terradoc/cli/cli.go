@@ -4,4 +4,8 @@ var Cli struct {
 	Generate GenerateCmd `cmd:"" help:"Generate a markdown file from .tfdoc.hcl input."`
 	Format   FormatCmd   `name:"fmt" cmd:"" help:"Format .tfdoc.hcl file."`
 	Validate ValidateCmd `name:"validate" cmd:"" help:"Check if .tfdoc.hcl file is synchronized with Terraform variables and/or outputs. Checks all .tf files in the current directory but not in its sub-directories."`
+	Sync     SyncCmd     `name:"sync" cmd:"" help:"Update .tfdoc.hcl file with the variables and/or outputs defined in the .tf files of its directory."`
+	Init     InitCmd     `name:"init" cmd:"" help:"Create a starter .tfdoc.hcl file from the variables and outputs of the .tf files in a module directory."`
+	Watch    WatchCmd    `name:"watch" cmd:"" help:"Regenerate the output file and validate whenever the .tfdoc.hcl file, the .tf files of its directory or the templates change."`
+	Lsp      LspCmd      `name:"lsp" cmd:"" help:"Run a language server for .tfdoc.hcl files speaking the Language Server Protocol over stdio."`
 }
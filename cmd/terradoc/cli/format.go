@@ -1,7 +1,10 @@
 package cli
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 
@@ -9,11 +12,16 @@ import (
 )
 
 type FormatCmd struct {
-	InputFile string `arg:"" help:"Input file." type:"existingfile"`
+	InputFile string `arg:"" help:"Input file or, with --recursive, the root directory of the modules."`
 	Write     bool   `name:"write" short:"w" help:"Overwrite file with formatted version."`
+	Recursive bool   `name:"recursive" short:"r" help:"Format the .tfdoc.hcl files of all modules in the directory tree of the input directory in parallel. Lists the files that are not formatted and fails unless --write is given."`
 }
 
 func (f FormatCmd) Run() error {
+	if f.Recursive {
+		return f.runRecursive()
+	}
+
 	inSrc, err := ioutil.ReadFile(f.InputFile)
 	if err != nil {
 		return fmt.Errorf("reading input: %s", err)
@@ -33,3 +41,38 @@ func (f FormatCmd) Run() error {
 
 	return nil
 }
+
+// runRecursive formats all terradoc files found in the directory tree of the input and lists the
+// files that were not formatted
+func (f FormatCmd) runRecursive() error {
+	docFiles, err := findDocFiles(f.InputFile)
+	if err != nil {
+		return err
+	}
+
+	results := processModules(docFiles, func(i int, stdout, stderr io.Writer) error {
+		inSrc, err := ioutil.ReadFile(docFiles[i])
+		if err != nil {
+			return fmt.Errorf("reading input: %s", err)
+		}
+
+		outSrc := hclwrite.Format(inSrc)
+		if bytes.Equal(inSrc, outSrc) {
+			return nil
+		}
+
+		fmt.Fprintln(stdout, docFiles[i])
+
+		if !f.Write {
+			return errors.New("file is not formatted")
+		}
+
+		if err := ioutil.WriteFile(docFiles[i], outSrc, 0644); err != nil {
+			return fmt.Errorf("writing result: %s", err)
+		}
+
+		return nil
+	})
+
+	return writeModuleReport(results)
+}
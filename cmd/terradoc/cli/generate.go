@@ -1,38 +1,185 @@
 package cli
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 
+	"github.com/mineiros-io/terradoc/internal/detect"
+	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/parsers/docparser"
+	"github.com/mineiros-io/terradoc/internal/renderers/html"
+	"github.com/mineiros-io/terradoc/internal/renderers/json"
 	"github.com/mineiros-io/terradoc/internal/renderers/markdown"
+	"github.com/mineiros-io/terradoc/internal/textdiff"
+)
+
+const (
+	markdownFormat = "markdown"
+	jsonFormat     = "json"
+	htmlFormat     = "html"
 )
 
 type GenerateCmd struct {
-	InputFile  string `arg:"" required:"" help:"Input file." type:"existingfile"`
-	OutputFile string `name:"output" short:"o" optional:"" default:"-" help:"Output file to write resulting markdown to" type:"path"`
+	InputFile     string `arg:"" required:"" help:"Input file or, with --recursive, the root directory of the modules."`
+	OutputFile    string `name:"output" short:"o" optional:"" default:"-" help:"Output file to write resulting markdown to. With --recursive, the name of the output file in each module directory, which defaults to README with the extension of the format."`
+	Format        string `name:"format" short:"f" enum:"markdown,json,html" default:"markdown" help:"Output format (markdown, json, html)."`
+	Inject        bool   `name:"inject" short:"i" help:"Replace only the content between the begin and end markers of the existing output file."`
+	BeginMarker   string `name:"begin-marker" default:"${default_begin_marker}" help:"Marker after which the generated content is injected."`
+	EndMarker     string `name:"end-marker" default:"${default_end_marker}" help:"Marker before which the generated content is injected."`
+	Check         bool   `name:"check" short:"c" help:"Check if the output file is up to date without writing it. Prints a diff and fails if it is not."`
+	Templates     string `name:"templates" env:"TERRADOC_TEMPLATES" type:"existingdir" help:"Directory with markdown templates overriding the embedded templates of the same name. Only used for the markdown format."`
+	ModuleVersion string `name:"module-version" env:"TERRADOC_MODULE_VERSION" help:"Value of module.version in the document. Defaults to the latest git tag."`
+	ModuleSource  string `name:"module-source" env:"TERRADOC_MODULE_SOURCE" help:"Value of module.source in the document. Defaults to the origin remote of the git repository and the path of the module in it."`
+	Recursive     bool   `name:"recursive" short:"r" help:"Generate the output files of all modules with a .tfdoc.hcl file in the directory tree of the input directory in parallel."`
+}
+
+// outputExtensions are the extensions of the default output files written in recursive mode
+var outputExtensions = map[string]string{
+	markdownFormat: ".md",
+	jsonFormat:     ".json",
+	htmlFormat:     ".html",
 }
 
 func (g GenerateCmd) Run() error {
-	r, rCloser, err := openInput(g.InputFile)
+	if g.Recursive {
+		return g.runRecursive()
+	}
+
+	return g.generate(g.InputFile, g.OutputFile, os.Stdout)
+}
+
+// runRecursive generates the output file next to each terradoc file found in the directory tree of the input
+func (g GenerateCmd) runRecursive() error {
+	outputName := g.OutputFile
+	if outputName == "-" {
+		outputName = "README" + outputExtensions[g.Format]
+	}
+
+	if filepath.IsAbs(outputName) || filepath.Base(outputName) != outputName {
+		return fmt.Errorf("--output must be a file name when used with --recursive, got %q", g.OutputFile)
+	}
+
+	docFiles, err := findDocFiles(g.InputFile)
+	if err != nil {
+		return err
+	}
+
+	outputFiles := make([]string, len(docFiles))
+	generatedBy := map[string]string{}
+
+	// modules are processed in parallel, so two documents of a module would write the same file
+	for i, docFile := range docFiles {
+		outputFiles[i] = filepath.Join(filepath.Dir(docFile), outputName)
+
+		if other, ok := generatedBy[outputFiles[i]]; ok {
+			return fmt.Errorf("%q and %q both generate %q, include one of them in the other", other, docFile, outputFiles[i])
+		}
+
+		generatedBy[outputFiles[i]] = docFile
+	}
+
+	results := processModules(docFiles, func(i int, stdout, stderr io.Writer) error {
+		return g.generate(docFiles[i], outputFiles[i], stdout)
+	})
+
+	return writeModuleReport(results)
+}
+
+// generate renders the input file to the output file. Diffs found in check mode are written to stdout.
+func (g GenerateCmd) generate(inputFile, outputFile string, stdout io.Writer) error {
+	r, rCloser, err := openInput(inputFile)
 	if err != nil {
 		return err
 	}
 	defer rCloser()
 
-	w, wCloser, err := getOutputWriter(g.OutputFile)
+	def, err := docparser.ParseWithOptions(r, r.Name(), g.parseOptions(inputFile))
+	if err != nil {
+		return fmt.Errorf("parsing input: %w", err)
+	}
+
+	if !g.Inject && !g.Check {
+		w, wCloser, err := getOutputWriter(outputFile)
+		if err != nil {
+			return err
+		}
+		defer wCloser()
+
+		return g.render(w, def)
+	}
+
+	if g.Inject && g.Format != markdownFormat {
+		return fmt.Errorf("--inject is only supported for the %s format", markdownFormat)
+	}
+
+	if outputFile == "-" {
+		return errors.New("an output file is required for --inject and --check")
+	}
+
+	existing, err := ioutil.ReadFile(outputFile)
+	switch {
+	case err == nil:
+	case g.Check && !g.Inject && errors.Is(err, os.ErrNotExist):
+		// a missing output file is reported as drift
+	default:
+		return fmt.Errorf("reading output file: %v", err)
+	}
+
+	result, err := g.renderDocument(def, existing, outputFile)
 	if err != nil {
 		return err
 	}
-	defer wCloser()
 
-	def, err := docparser.Parse(r, r.Name())
+	if g.Check {
+		return checkOutput(stdout, outputFile, existing, result)
+	}
+
+	if bytes.Equal(existing, result) {
+		return nil
+	}
+
+	return ioutil.WriteFile(outputFile, result, 0644)
+}
+
+// renderDocument renders the document into memory. If injection is enabled the rendered document
+// is injected into the existing content of the output file.
+func (g GenerateCmd) renderDocument(def entities.Doc, existing []byte, outputFile string) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	err := g.render(buf, def)
 	if err != nil {
-		return fmt.Errorf("parsing input: %v", err)
+		return nil, err
+	}
+
+	if !g.Inject {
+		return buf.Bytes(), nil
+	}
+
+	result, err := markdown.Inject(existing, buf.Bytes(), g.BeginMarker, g.EndMarker)
+	if err != nil {
+		return nil, fmt.Errorf("injecting into %q: %v", outputFile, err)
+	}
+
+	return result, nil
+}
+
+func (g GenerateCmd) render(w io.Writer, def entities.Doc) error {
+	var err error
+
+	switch g.Format {
+	case jsonFormat:
+		err = json.Render(w, def)
+	case htmlFormat:
+		err = html.Render(w, def)
+	default:
+		err = markdown.RenderWithTemplates(w, def, g.Templates)
 	}
 
-	err = markdown.Render(w, def)
 	if err != nil {
 		return fmt.Errorf("rendering document: %v", err)
 	}
@@ -40,6 +187,38 @@ func (g GenerateCmd) Run() error {
 	return nil
 }
 
+// parseOptions returns the values of the module expressions of a document set by the flags or
+// detected from its module
+func (g GenerateCmd) parseOptions(docFile string) docparser.Options {
+	return detectOptions(docFile, docparser.Options{
+		ModuleVersion: g.ModuleVersion,
+		ModuleSource:  g.ModuleSource,
+	})
+}
+
+// detectOptions returns opts with the values that are not set detected from the module directory
+// of docFile
+func detectOptions(docFile string, opts docparser.Options) docparser.Options {
+	abs, err := filepath.Abs(docFile)
+	if err != nil {
+		abs = docFile
+	}
+
+	return detect.Options(filepath.Dir(abs), opts)
+}
+
+// checkOutput prints a diff and returns an error if the output file content differs from the expected content
+func checkOutput(w io.Writer, filename string, existing, expected []byte) error {
+	diff := textdiff.Unified(filename, filename+" (generated)", existing, expected)
+	if diff == "" {
+		return nil
+	}
+
+	fmt.Fprint(w, diff)
+
+	return fmt.Errorf("%q is out of date, run generate to update it", filename)
+}
+
 func openInput(path string) (*os.File, func(), error) {
 	if path == "-" {
 		return os.Stdin, noopClose, nil
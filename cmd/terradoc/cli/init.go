@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mineiros-io/terradoc/internal/parsers/validationparser"
+	"github.com/mineiros-io/terradoc/internal/renderers/tfdoc"
+	"github.com/mineiros-io/terradoc/internal/scaffold"
+)
+
+const defaultDocFileName = "README.tfdoc.hcl"
+
+type InitCmd struct {
+	Dir        string `arg:"" optional:"" default:"." help:"Module directory containing the .tf files." type:"existingdir"`
+	OutputFile string `name:"output" short:"o" optional:"" help:"Output file to write the .tfdoc.hcl document to. Defaults to README.tfdoc.hcl in the module directory. Use - for stdout." type:"path"`
+	Title      string `name:"title" short:"t" optional:"" help:"Title of the root section. Defaults to the name of the module directory."`
+	Force      bool   `name:"force" short:"f" help:"Overwrite the output file if it already exists."`
+}
+
+func (i InitCmd) Run() error {
+	dir, err := filepath.Abs(i.Dir)
+	if err != nil {
+		return err
+	}
+
+	outputFile := i.OutputFile
+	if outputFile == "" {
+		outputFile = filepath.Join(dir, defaultDocFileName)
+	}
+
+	if outputFile != "-" && !i.Force {
+		_, err := os.Stat(outputFile)
+		if err == nil {
+			return fmt.Errorf("%q already exists, use --force to overwrite it", outputFile)
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	definitions, err := parseTerraformDir(dir, validationparser.Options{Variables: true, Outputs: true})
+	if err != nil {
+		return fmt.Errorf("parsing terraform files: %w", err)
+	}
+
+	title := i.Title
+	if title == "" {
+		title = filepath.Base(dir)
+	}
+
+	w, wCloser, err := getOutputWriter(outputFile)
+	if err != nil {
+		return err
+	}
+	defer wCloser()
+
+	err = tfdoc.Render(w, scaffold.Doc(title, definitions))
+	if err != nil {
+		return fmt.Errorf("rendering document: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/mineiros-io/terradoc/internal/lsp"
+	"github.com/mineiros-io/terradoc/internal/validators"
+)
+
+type LspCmd struct {
+	CheckRequired     bool `name:"check-required" help:"Report variables and attributes whose required flag does not match the .tf files, like validate --check-required."`
+	CheckDefaults     bool `name:"check-defaults" help:"Report defaults that differ from the defaults in the .tf files, like validate --check-defaults."`
+	CheckDescriptions bool `name:"check-descriptions" help:"Report descriptions that differ from the descriptions in the .tf files, like validate --check-descriptions."`
+	CheckRequirements bool `name:"check-requirements" help:"Report Terraform and provider requirements that differ from the terraform blocks in the .tf files, like validate --check-requirements."`
+	CheckSensitive    bool `name:"check-sensitive" help:"Report variables and outputs that are sensitive in the .tf files but not documented as sensitive, like validate --check-sensitive."`
+}
+
+func (l LspCmd) Run() error {
+	opts := validators.Options{
+		RequiredDefault: l.CheckRequired,
+		Defaults:        l.CheckDefaults,
+		Descriptions:    l.CheckDescriptions,
+		Requirements:    l.CheckRequirements,
+		Sensitive:       l.CheckSensitive,
+	}
+
+	return lsp.NewServer(os.Stdin, os.Stdout, opts).Run()
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+	"github.com/mineiros-io/terradoc/internal/parsers/docparser"
+)
+
+// docFilePattern matches the terradoc files processed in recursive mode
+const docFilePattern = "*.tfdoc.hcl"
+
+// findDocFiles returns the terradoc files of root and all its sub-directories sorted by path.
+// Hidden directories, e.g. .git and .terraform, are skipped. Files included by other terradoc
+// files are part of the including document and are not returned.
+func findDocFiles(root string) ([]string, error) {
+	var docFiles []string
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if path != root && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if matched, _ := filepath.Match(docFilePattern, info.Name()); matched {
+			docFiles = append(docFiles, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("searching %s files in %q: %v", docFilePattern, root, err)
+	}
+
+	if len(docFiles) == 0 {
+		return nil, fmt.Errorf("no %s files found in %q", docFilePattern, root)
+	}
+
+	return withoutIncluded(docFiles)
+}
+
+// withoutIncluded removes the files included by other files of the list
+func withoutIncluded(docFiles []string) ([]string, error) {
+	included := map[string]bool{}
+
+	for _, docFile := range docFiles {
+		includes, err := docparser.Includes(docFile)
+		if err != nil {
+			return nil, fmt.Errorf("searching files included by %q: %v", docFile, err)
+		}
+
+		for _, include := range includes {
+			abs, err := filepath.Abs(include)
+			if err != nil {
+				return nil, err
+			}
+
+			included[abs] = true
+		}
+	}
+
+	var result []string
+
+	for _, docFile := range docFiles {
+		abs, err := filepath.Abs(docFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if !included[abs] {
+			result = append(result, docFile)
+		}
+	}
+
+	return result, nil
+}
+
+// moduleResult is the result of processing the terradoc file of a module in recursive mode
+type moduleResult struct {
+	docFile string
+	// stdout and stderr buffer the output of the module so the outputs of modules
+	// processed in parallel are not interleaved
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+	err    error
+}
+
+// processModules calls process for each terradoc file in parallel. Process gets the index of the
+// file and writers which are written to the standard output and error after all files are processed.
+func processModules(docFiles []string, process func(i int, stdout, stderr io.Writer) error) []*moduleResult {
+	results := make([]*moduleResult, len(docFiles))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+
+	workers := runtime.NumCPU()
+	if workers > len(docFiles) {
+		workers = len(docFiles)
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				r := results[i]
+				r.err = process(i, &r.stdout, &r.stderr)
+			}
+		}()
+	}
+
+	for i, docFile := range docFiles {
+		results[i] = &moduleResult{docFile: docFile}
+	}
+
+	for i := range docFiles {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// writeModuleReport writes the buffered output of all modules in order followed by the status of
+// each module. It returns an error if any module failed.
+func writeModuleReport(results []*moduleResult) error {
+	diagWriter := diagnostics.NewWriter(os.Stderr)
+
+	var failed int
+
+	for _, r := range results {
+		os.Stdout.Write(r.stdout.Bytes())
+		os.Stderr.Write(r.stderr.Bytes())
+
+		if r.err == nil {
+			continue
+		}
+
+		failed++
+
+		if diags, ok := diagnostics.FromError(r.err); ok {
+			if err := diagWriter.Write(diags); err != nil {
+				fmt.Fprintf(os.Stderr, "writing diagnostics: %v\n", err)
+			}
+		}
+	}
+
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(os.Stderr, "FAIL\t%s: %v\n", r.docFile, moduleError(r.err))
+		} else {
+			fmt.Fprintf(os.Stderr, "ok\t%s\n", r.docFile)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d modules failed", failed, len(results))
+	}
+
+	return nil
+}
+
+// moduleError returns a short description of err for the status line of a module.
+// Diagnostics are written in full before the status lines and only counted here.
+func moduleError(err error) string {
+	diags, ok := diagnostics.FromError(err)
+	if !ok {
+		return err.Error()
+	}
+
+	if len(diags) == 1 {
+		return "1 problem"
+	}
+
+	return fmt.Sprintf("%d problems", len(diags))
+}
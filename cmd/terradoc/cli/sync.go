@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mineiros-io/terradoc/internal/docsync"
+	"github.com/mineiros-io/terradoc/internal/parsers/validationparser"
+)
+
+type SyncCmd struct {
+	DocFile          string `arg:"" help:"Input file." type:"existingfile"`
+	Write            bool   `name:"write" short:"w" help:"Overwrite file with synchronized version."`
+	VariablesEnabled bool   `name:"variables" optional:"" short:"v" help:"Whether to synchronize variables."`
+	OutputsEnabled   bool   `name:"outputs" short:"o" optional:"" help:"Whether to synchronize outputs."`
+	VariablesSection string `name:"variables-section" optional:"" help:"Title of the section new variables are added to. Defaults to the section of the last documented variable."`
+	OutputsSection   string `name:"outputs-section" optional:"" help:"Title of the section new outputs are added to. Defaults to the section of the last documented output."`
+	Prune            bool   `name:"prune" help:"Remove documented variables and outputs that are not defined in any .tf files instead of marking them as stale."`
+}
+
+func (s SyncCmd) Run() error {
+	src, err := ioutil.ReadFile(s.DocFile)
+	if err != nil {
+		return fmt.Errorf("reading input: %s", err)
+	}
+
+	abs, err := filepath.Abs(s.DocFile)
+	if err != nil {
+		return err
+	}
+
+	// validate both variables and outputs if none is specified
+	varsEnabled := s.VariablesEnabled || !s.OutputsEnabled
+	outputsEnabled := s.OutputsEnabled || !s.VariablesEnabled
+
+	definitions, err := parseTerraformDir(filepath.Dir(abs), validationparser.Options{Variables: varsEnabled, Outputs: outputsEnabled})
+	if err != nil {
+		return err
+	}
+
+	outSrc, changes, err := docsync.Sync(src, s.DocFile, definitions, docsync.Options{
+		VariablesSection: s.VariablesSection,
+		OutputsSection:   s.OutputsSection,
+		Prune:            s.Prune,
+		Variables:        varsEnabled,
+		Outputs:          outputsEnabled,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		fmt.Fprintln(os.Stderr, change)
+	}
+
+	if s.Write {
+		err = ioutil.WriteFile(s.DocFile, outSrc, 0644)
+	} else {
+		_, err = os.Stdout.Write(outSrc)
+	}
+
+	if err != nil {
+		return fmt.Errorf("writing result: %s", err)
+	}
+
+	return nil
+}
@@ -3,134 +3,283 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/parsers/docparser"
 	"github.com/mineiros-io/terradoc/internal/parsers/validationparser"
 	"github.com/mineiros-io/terradoc/internal/validators"
 	"github.com/mineiros-io/terradoc/internal/validators/outputsvalidator"
+	"github.com/mineiros-io/terradoc/internal/validators/refsvalidator"
+	"github.com/mineiros-io/terradoc/internal/validators/report"
+	"github.com/mineiros-io/terradoc/internal/validators/requirementsvalidator"
 	"github.com/mineiros-io/terradoc/internal/validators/varsvalidator"
 )
 
 type ValidateCmd struct {
-	DocFile          string `arg:"" help:"Input file." default:""`
-	VariablesEnabled bool   `name:"variables" optional:"" short:"v" help:"Whether to validate variables."`
-	OutputsEnabled   bool   `name:"outputs" short:"o" optional:"" help:"Whether to validate outputs."`
+	DocFile           string `arg:"" help:"Input file or, with --recursive, the root directory of the modules." default:""`
+	VariablesEnabled  bool   `name:"variables" optional:"" short:"v" help:"Whether to validate variables."`
+	OutputsEnabled    bool   `name:"outputs" short:"o" optional:"" help:"Whether to validate outputs."`
+	Format            string `name:"format" short:"f" enum:"text,json,sarif,junit" default:"text" help:"Output format of the validation results (text, json, sarif, junit)."`
+	CheckRequired     bool   `name:"check-required" help:"Fail if a variable documented as required has a default in the .tf files or if an attribute is documented as required but declared as optional() or vice versa."`
+	CheckDefaults     bool   `name:"check-defaults" help:"Fail if a documented default of a variable or attribute differs from the default in the .tf files. Defaults are compared as JSON."`
+	CheckDescriptions bool   `name:"check-descriptions" help:"Fail if a description differs from the description in the .tf files."`
+	CheckRequirements bool   `name:"check-requirements" help:"Fail if the Terraform and provider requirements listed in requirements blocks differ from the terraform blocks in the .tf files."`
+	CheckSensitive    bool   `name:"check-sensitive" help:"Fail if a variable or output is sensitive in the .tf files but not documented as sensitive."`
+	ModuleVersion     string `name:"module-version" env:"TERRADOC_MODULE_VERSION" help:"Value of module.version in the document. Defaults to the latest git tag."`
+	ModuleSource      string `name:"module-source" env:"TERRADOC_MODULE_SOURCE" help:"Value of module.source in the document. Defaults to the origin remote of the git repository and the path of the module in it."`
+	Recursive         bool   `name:"recursive" short:"r" help:"Validate the .tfdoc.hcl files of all modules in the directory tree of the input directory in parallel."`
 }
 
+const textFormat = "text"
+
 func (vcm ValidateCmd) Run() error {
-	var hasVarsErrors, hasOutputsErrors bool
-	var docFileName, tfFilesDir string
+	varsEnabled := false
+	if vcm.VariablesEnabled {
+		varsEnabled = true
+	} else {
+		varsEnabled = !vcm.VariablesEnabled && !vcm.OutputsEnabled
+	}
+
+	outputsEnabled := false
+	if vcm.OutputsEnabled {
+		outputsEnabled = true
+	} else {
+		outputsEnabled = !vcm.VariablesEnabled && !vcm.OutputsEnabled
+	}
+
+	if vcm.Recursive {
+		return vcm.runRecursive(varsEnabled, outputsEnabled)
+	}
 
 	// DOC
 	if vcm.DocFile == "" {
 		return errors.New("No input file provided")
 	}
 
-	t, tCloser, err := openInput(vcm.DocFile)
+	summaries, err := validateDocFile(vcm.DocFile, varsEnabled, outputsEnabled, vcm.parseOptions(vcm.DocFile), vcm.options())
 	if err != nil {
 		return err
 	}
-	defer tCloser()
 
-	doc, err := docparser.Parse(t, t.Name())
-	if err != nil {
+	if vcm.Format == textFormat {
+		diagWriter := diagnostics.NewWriter(os.Stderr)
+
+		for _, summary := range summaries {
+			printValidationSummary(diagWriter, summary)
+		}
+	} else if err := writeValidationReport(vcm.Format, summaries); err != nil {
 		return err
 	}
 
-	abs, err := filepath.Abs(t.Name())
-	if err != nil {
-		return err
+	if !validationSucceeded(summaries) {
+		return errors.New("Found validation errors")
 	}
 
-	tfFilesDir = filepath.Dir(abs)
+	return nil
+}
+
+// runRecursive validates the terradoc files of all modules found in the directory tree of the
+// input in parallel. Reports of all modules are merged into a single report.
+func (vcm ValidateCmd) runRecursive(varsEnabled, outputsEnabled bool) error {
+	root := vcm.DocFile
+	if root == "" {
+		root = "."
+	}
 
-	files, err := WalkMatch(tfFilesDir, "*.tf")
+	docFiles, err := findDocFiles(root)
 	if err != nil {
 		return err
 	}
 
-	varsEnabled := false
-	if vcm.VariablesEnabled {
-		varsEnabled = true
-	} else {
-		varsEnabled = !vcm.VariablesEnabled && !vcm.OutputsEnabled
-	}
+	moduleSummaries := make([][]validators.Summary, len(docFiles))
 
-	outputsEnabled := false
-	if vcm.OutputsEnabled {
-		outputsEnabled = true
-	} else {
-		outputsEnabled = !vcm.VariablesEnabled && !vcm.OutputsEnabled
-	}
+	results := processModules(docFiles, func(i int, stdout, stderr io.Writer) error {
+		summaries, err := validateDocFile(docFiles[i], varsEnabled, outputsEnabled, vcm.parseOptions(docFiles[i]), vcm.options())
+		if err != nil {
+			return err
+		}
 
-	hasVarsErrors = false
-	hasOutputsErrors = false
+		for j := range summaries {
+			summaries[j].Module = filepath.Dir(docFiles[i])
+		}
 
-	tfContent := entities.ValidationContents{}
+		moduleSummaries[i] = summaries
 
-	for _, file := range files {
-		f, vCloser, err := openInput(file)
-		if err != nil {
-			return err
+		if vcm.Format == textFormat {
+			diagWriter := diagnostics.NewWriter(stderr)
+
+			for _, summary := range summaries {
+				printValidationSummary(diagWriter, summary)
+			}
 		}
-		defer vCloser()
 
-		content, err := validationparser.Parse(f, f.Name(), varsEnabled, outputsEnabled)
-		if err != nil {
+		if !validationSucceeded(summaries) {
+			return errors.New("Found validation errors")
+		}
+
+		return nil
+	})
+
+	if vcm.Format != textFormat {
+		var summaries []validators.Summary
+		for _, s := range moduleSummaries {
+			summaries = append(summaries, s...)
+		}
+
+		if err := writeValidationReport(vcm.Format, summaries); err != nil {
 			return err
 		}
+	}
 
-		tfContent.Variables = append(tfContent.Variables, content.Variables...)
-		tfContent.Outputs = append(tfContent.Outputs, content.Outputs...)
+	return writeModuleReport(results)
+}
+
+// options returns the optional checks enabled by the flags
+func (vcm ValidateCmd) options() validators.Options {
+	return validators.Options{
+		RequiredDefault: vcm.CheckRequired,
+		Defaults:        vcm.CheckDefaults,
+		Descriptions:    vcm.CheckDescriptions,
+		Requirements:    vcm.CheckRequirements,
+		Sensitive:       vcm.CheckSensitive,
 	}
+}
 
-	docFileName = t.Name()
+// parseOptions returns the values of the module expressions of a document set by the flags or
+// detected from its module
+func (vcm ValidateCmd) parseOptions(docFile string) docparser.Options {
+	return detectOptions(docFile, docparser.Options{
+		ModuleVersion: vcm.ModuleVersion,
+		ModuleSource:  vcm.ModuleSource,
+	})
+}
 
-	// VARIABLES
-	if varsEnabled {
-		varsSummary := varsvalidator.Validate(doc, tfContent)
+// validateDocFile validates the terradoc file against the .tf files in its directory
+func validateDocFile(docFile string, varsEnabled, outputsEnabled bool, parseOpts docparser.Options, opts validators.Options) ([]validators.Summary, error) {
+	t, tCloser, err := openInput(docFile)
+	if err != nil {
+		return nil, err
+	}
+	defer tCloser()
 
-		printValidationSummary(varsSummary, docFileName)
+	doc, err := docparser.ParseWithOptions(t, t.Name(), parseOpts)
+	if err != nil {
+		return nil, err
+	}
 
-		if !varsSummary.Success() {
-			hasVarsErrors = !varsSummary.Success()
-		}
+	abs, err := filepath.Abs(t.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	tfContent, err := parseTerraformDir(filepath.Dir(abs), validationparser.Options{
+		Variables:    varsEnabled,
+		Outputs:      outputsEnabled,
+		Requirements: opts.Requirements,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []validators.Summary
+
+	// VARIABLES
+	if varsEnabled {
+		summaries = append(summaries, varsvalidator.ValidateWithOptions(doc, tfContent, opts))
 	}
 
 	// OUTPUTS
 	if outputsEnabled {
-		outputsSummary := outputsvalidator.Validate(doc, tfContent)
+		summaries = append(summaries, outputsvalidator.ValidateWithOptions(doc, tfContent, opts))
+	}
+
+	// REFERENCES
+	summaries = append(summaries, refsvalidator.Validate(doc))
+
+	// REQUIREMENTS
+	if opts.Requirements {
+		summaries = append(summaries, requirementsvalidator.Validate(doc, tfContent))
+	}
 
-		printValidationSummary(outputsSummary, docFileName)
+	return summaries, nil
+}
 
-		if !outputsSummary.Success() {
-			hasOutputsErrors = !outputsSummary.Success()
+func validationSucceeded(summaries []validators.Summary) bool {
+	for _, summary := range summaries {
+		if !summary.Success() {
+			return false
 		}
 	}
 
-	if hasVarsErrors || hasOutputsErrors {
-		return errors.New("Found validation errors")
+	return true
+}
+
+func writeValidationReport(format string, summaries []validators.Summary) error {
+	relativeResultPaths(summaries)
+
+	if err := report.Write(os.Stdout, format, summaries); err != nil {
+		return fmt.Errorf("writing validation report: %v", err)
 	}
 
 	return nil
 }
 
-func printValidationSummary(summary validators.Summary, docFilename string) {
-	for _, missingDef := range summary.MissingDefinition {
-		fmt.Fprintf(os.Stderr, "Unknown %s documented: %q is not defined in any .tf files\n", summary.Type, missingDef)
+func printValidationSummary(w *diagnostics.Writer, summary validators.Summary) {
+	if err := w.Write(summary.Diagnostics()); err != nil {
+		fmt.Fprintf(os.Stderr, "writing validation results: %v\n", err)
 	}
+}
 
-	for _, missingDoc := range summary.MissingDocumentation {
-		fmt.Fprintf(os.Stderr, "Missing %s documentation: %q is not documented in %q\n", summary.Type, missingDoc, docFilename)
+// relativeResultPaths makes the file names of the results relative to the working directory
+// so reports refer to the files as they are checked in
+func relativeResultPaths(summaries []validators.Summary) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return
 	}
 
-	for _, tMismatch := range summary.TypeMismatch {
-		fmt.Fprintf(os.Stderr, "Type mismatch for %s: %q is documented as %q in %q but defined as %q in .tf files\n", summary.Type, tMismatch.Name, tMismatch.DocumentedType, docFilename, tMismatch.DefinedType)
+	for _, summary := range summaries {
+		for i, result := range summary.Results {
+			// results without a position, e.g. of the whole document, have no file
+			if result.Range.Filename == "" {
+				continue
+			}
+
+			abs, err := filepath.Abs(result.Range.Filename)
+			if err != nil {
+				continue
+			}
+
+			if rel, err := filepath.Rel(wd, abs); err == nil {
+				summary.Results[i].Range.Filename = rel
+			}
+		}
+	}
+}
+
+// parseTerraformDir parses the blocks selected by opts of all .tf files in dir but not in its sub-directories
+func parseTerraformDir(dir string, opts validationparser.Options) (entities.ValidationContents, error) {
+	files, err := WalkMatch(dir, "*.tf")
+	if err != nil {
+		return entities.ValidationContents{}, err
+	}
+
+	tfContent := entities.ValidationContents{}
+
+	for _, file := range files {
+		content, err := validationparser.ParseFile(file, opts)
+		if err != nil {
+			return entities.ValidationContents{}, err
+		}
+
+		tfContent = tfContent.Merge(content)
 	}
 
+	return tfContent, nil
 }
 
 func WalkMatch(root, pattern string) ([]string, error) {
@@ -0,0 +1,327 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+	"github.com/mineiros-io/terradoc/internal/parsers/docparser"
+	"github.com/mineiros-io/terradoc/internal/validators"
+	"github.com/mineiros-io/terradoc/internal/watcher"
+)
+
+type WatchCmd struct {
+	InputFile         string        `arg:"" required:"" help:"Input file to watch."`
+	OutputFile        string        `name:"output" short:"o" optional:"" help:"Output file to write the generated document to. Defaults to README with the extension of the format next to the input file."`
+	Format            string        `name:"format" short:"f" enum:"markdown,json,html" default:"markdown" help:"Output format (markdown, json, html)."`
+	Inject            bool          `name:"inject" short:"i" help:"Replace only the content between the begin and end markers of the existing output file."`
+	BeginMarker       string        `name:"begin-marker" default:"${default_begin_marker}" help:"Marker after which the generated content is injected."`
+	EndMarker         string        `name:"end-marker" default:"${default_end_marker}" help:"Marker before which the generated content is injected."`
+	Templates         string        `name:"templates" env:"TERRADOC_TEMPLATES" type:"existingdir" help:"Directory with markdown templates overriding the embedded templates of the same name. Changes of the templates are watched, too."`
+	NoValidate        bool          `name:"no-validate" help:"Do not validate the input file against the .tf files of its directory."`
+	CheckRequired     bool          `name:"check-required" help:"Validate required flags like validate --check-required."`
+	CheckDefaults     bool          `name:"check-defaults" help:"Validate defaults like validate --check-defaults."`
+	CheckDescriptions bool          `name:"check-descriptions" help:"Validate descriptions like validate --check-descriptions."`
+	CheckRequirements bool          `name:"check-requirements" help:"Validate requirements like validate --check-requirements."`
+	CheckSensitive    bool          `name:"check-sensitive" help:"Validate sensitive flags like validate --check-sensitive."`
+	ModuleVersion     string        `name:"module-version" env:"TERRADOC_MODULE_VERSION" help:"Value of module.version in the document. Defaults to the latest git tag."`
+	ModuleSource      string        `name:"module-source" env:"TERRADOC_MODULE_SOURCE" help:"Value of module.source in the document. Defaults to the origin remote of the git repository and the path of the module in it."`
+	Debounce          time.Duration `name:"debounce" default:"200ms" help:"Time to wait for further changes before regenerating."`
+}
+
+func (w WatchCmd) Run() error {
+	docFile := w.InputFile
+
+	absDocFile, err := filepath.Abs(docFile)
+	if err != nil {
+		return err
+	}
+
+	outputFile := w.OutputFile
+	if outputFile == "" {
+		outputFile = filepath.Join(filepath.Dir(docFile), "README"+outputExtensions[w.Format])
+	}
+
+	absOutputFile, err := filepath.Abs(outputFile)
+	if err != nil {
+		return err
+	}
+
+	if w.Inject && w.Format != markdownFormat {
+		return fmt.Errorf("--inject is only supported for the %s format", markdownFormat)
+	}
+
+	dirs := []string{filepath.Dir(absDocFile)}
+	if w.Templates != "" {
+		dirs = append(dirs, w.Templates)
+	}
+
+	fileWatcher, err := watcher.New(dirs...)
+	if err != nil {
+		return err
+	}
+	defer fileWatcher.Close()
+
+	includes := &includeDirs{}
+
+	changes := make(chan string)
+
+	go func() {
+		defer close(changes)
+
+		for name := range fileWatcher.Events() {
+			if w.watched(absDocFile, absOutputFile, name, includes) {
+				changes <- name
+			}
+		}
+	}()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	// git is run once as the module values do not change while watching, like in the language
+	// server
+	parseOpts := w.parseOptions(docFile)
+
+	// the includes are watched before the status line is printed, so changes made after it are
+	// not missed
+	watchIncludes(os.Stderr, fileWatcher, absDocFile, includes)
+	w.update(os.Stdout, os.Stderr, docFile, outputFile, parseOpts)
+
+	batches := watcher.Debounce(changes, w.Debounce)
+
+	for {
+		select {
+		case <-interrupt:
+			return nil
+		case err := <-fileWatcher.Errors():
+			return err
+		case _, ok := <-batches:
+			if !ok {
+				return nil
+			}
+
+			watchIncludes(os.Stderr, fileWatcher, absDocFile, includes)
+			w.update(os.Stdout, os.Stderr, docFile, outputFile, parseOpts)
+		}
+	}
+}
+
+// watched reports whether a changed file affects the output: the input file, the .tf files and
+// the included .tfdoc.hcl files of the module, the .tfdoc.hcl files in the directories of
+// included files and the templates. The output file does not, even if it is in the templates
+// directory.
+func (w WatchCmd) watched(docFile, outputFile, name string, includes *includeDirs) bool {
+	switch {
+	case name == docFile:
+		return true
+	case name == outputFile:
+		return false
+	case w.isTemplate(name):
+		return true
+	case filepath.Dir(name) == filepath.Dir(docFile):
+		return strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tfdoc.hcl")
+	case includes.contains(filepath.Dir(name)):
+		return strings.HasSuffix(name, ".tfdoc.hcl")
+	}
+
+	return false
+}
+
+// isTemplate reports whether name is a template in the templates directory, which may be the
+// directory of the input file
+func (w WatchCmd) isTemplate(name string) bool {
+	if w.Templates == "" || !strings.HasSuffix(name, ".md") || strings.HasPrefix(filepath.Base(name), ".") {
+		return false
+	}
+
+	templatesDir, err := filepath.Abs(w.Templates)
+
+	return err == nil && filepath.Dir(name) == templatesDir
+}
+
+// includeDirs are the directories of the files included by the watched document. They are
+// updated after each update of the document and read while filtering the changes.
+type includeDirs struct {
+	mu   sync.Mutex
+	dirs map[string]bool
+}
+
+func (d *includeDirs) contains(dir string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.dirs[dir]
+}
+
+func (d *includeDirs) set(dirs map[string]bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.dirs = dirs
+}
+
+// watchIncludes watches the directories of the files included by docFile and the files included
+// by them, so changes of included files outside of the module directory are watched, too
+func watchIncludes(stderr io.Writer, fileWatcher *watcher.Watcher, docFile string, includes *includeDirs) {
+	dirs := map[string]bool{}
+	seen := map[string]bool{docFile: true}
+	pending := []string{docFile}
+
+	for len(pending) > 0 {
+		file := pending[0]
+		pending = pending[1:]
+
+		included, err := docparser.Includes(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "watching includes of %s: %v\n", file, err)
+			continue
+		}
+
+		for _, name := range included {
+			abs, err := filepath.Abs(name)
+			if err != nil || seen[abs] {
+				continue
+			}
+
+			seen[abs] = true
+			pending = append(pending, abs)
+
+			if dir := filepath.Dir(abs); !dirs[dir] {
+				if err := fileWatcher.Add(dir); err != nil {
+					fmt.Fprintf(stderr, "watching includes of %s: %v\n", file, err)
+					continue
+				}
+
+				dirs[dir] = true
+			}
+		}
+	}
+
+	includes.set(dirs)
+}
+
+// update regenerates and validates the document and prints one status line. Problems are
+// printed to stderr before the status line.
+func (w WatchCmd) update(stdout, stderr io.Writer, docFile, outputFile string, parseOpts docparser.Options) {
+	result, err := w.generate(docFile, outputFile, parseOpts)
+	if err != nil {
+		if diags, ok := diagnostics.FromError(err); ok {
+			if werr := diagnostics.NewWriter(stderr).Write(diags); werr == nil {
+				fmt.Fprintf(stdout, "%s %s not generated, %s\n", timestamp(), filepath.Base(outputFile), plural(len(diags), "problem"))
+				return
+			}
+		}
+
+		fmt.Fprintf(stdout, "%s %s not generated: %v\n", timestamp(), filepath.Base(outputFile), err)
+		return
+	}
+
+	line := fmt.Sprintf("%s %s %s", timestamp(), filepath.Base(outputFile), result)
+
+	if !w.NoValidate {
+		summaries, err := validateDocFile(docFile, true, true, parseOpts, w.options())
+		switch {
+		case err != nil:
+			line += fmt.Sprintf(", validation failed: %v", err)
+		case validationSucceeded(summaries):
+			line += ", valid"
+		default:
+			problems := 0
+			diagWriter := diagnostics.NewWriter(stderr)
+
+			for _, summary := range summaries {
+				problems += len(summary.Results)
+				printValidationSummary(diagWriter, summary)
+			}
+
+			line += ", " + plural(problems, "validation problem")
+		}
+	}
+
+	fmt.Fprintln(stdout, line)
+}
+
+// generate renders the input file and writes the output file if its content changed. It returns
+// whether the output file was updated or unchanged.
+func (w WatchCmd) generate(docFile, outputFile string, parseOpts docparser.Options) (string, error) {
+	g := GenerateCmd{
+		Format:      w.Format,
+		Inject:      w.Inject,
+		BeginMarker: w.BeginMarker,
+		EndMarker:   w.EndMarker,
+		Templates:   w.Templates,
+	}
+
+	src, err := ioutil.ReadFile(docFile)
+	if err != nil {
+		return "", fmt.Errorf("reading input: %v", err)
+	}
+
+	def, err := docparser.ParseWithOptions(bytes.NewReader(src), docFile, parseOpts)
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := ioutil.ReadFile(outputFile)
+	if err != nil && (w.Inject || !os.IsNotExist(err)) {
+		return "", fmt.Errorf("reading output file: %v", err)
+	}
+
+	result, err := g.renderDocument(def, existing, outputFile)
+	if err != nil {
+		return "", err
+	}
+
+	if bytes.Equal(existing, result) {
+		return "unchanged", nil
+	}
+
+	if err := ioutil.WriteFile(outputFile, result, 0644); err != nil {
+		return "", fmt.Errorf("writing output file: %v", err)
+	}
+
+	return "updated", nil
+}
+
+// options returns the optional checks enabled by the flags
+func (w WatchCmd) options() validators.Options {
+	return validators.Options{
+		RequiredDefault: w.CheckRequired,
+		Defaults:        w.CheckDefaults,
+		Descriptions:    w.CheckDescriptions,
+		Requirements:    w.CheckRequirements,
+		Sensitive:       w.CheckSensitive,
+	}
+}
+
+// parseOptions returns the values of the module expressions of a document set by the flags or
+// detected from its module
+func (w WatchCmd) parseOptions(docFile string) docparser.Options {
+	return detectOptions(docFile, docparser.Options{
+		ModuleVersion: w.ModuleVersion,
+		ModuleSource:  w.ModuleSource,
+	})
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func timestamp() string {
+	return time.Now().Format("15:04:05")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// configFileName is the name of the configuration file with default flag values.
+// It is looked up in the working directory and then in the home directory.
+const configFileName = ".terradoc.json"
+
+// configCommand is the command whose flags are set by the configuration files
+const configCommand = "generate"
+
+// pathTypes are the types of the flags whose relative paths are resolved against the directory of
+// the configuration file
+var pathTypes = map[string]bool{
+	"path":         true,
+	"existingdir":  true,
+	"existingfile": true,
+}
+
+// configuration sets the default values of the flags of the generate command from the
+// configuration files in the working and the home directory. Values of the working directory take
+// precedence.
+func configuration() kong.Option {
+	paths := []string{configFileName}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, configFileName))
+	}
+
+	return kong.OptionFunc(func(k *kong.Kong) error {
+		var resolvers []kong.Resolver
+
+		// the last resolver that sets a flag wins, so the files are added in reverse
+		for i := len(paths) - 1; i >= 0; i-- {
+			resolver, err := loadConfig(paths[i])
+			if err != nil {
+				return err
+			}
+
+			if resolver != nil {
+				resolvers = append(resolvers, resolver)
+			}
+		}
+
+		return kong.Resolvers(resolvers...).Apply(k)
+	})
+}
+
+// loadConfig returns a resolver for the flags of the generate command set in the configuration file
+// or nil if the file does not exist
+func loadConfig(path string) (kong.Resolver, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) || os.IsPermission(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("reading %s: %v", path, err)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", path, err)
+	}
+
+	var resolver kong.ResolverFunc = func(ctx *kong.Context, parent *kong.Path, flag *kong.Flag) (interface{}, error) {
+		if parent.Command == nil || parent.Command.Name != configCommand {
+			return nil, nil
+		}
+
+		value, ok := values[strings.ReplaceAll(flag.Name, "-", "_")]
+		if !ok {
+			return nil, nil
+		}
+
+		if str, ok := value.(string); ok && pathTypes[flag.Tag.Type] && !filepath.IsAbs(str) && !strings.HasPrefix(str, "~/") {
+			return filepath.Join(filepath.Dir(abs), str), nil
+		}
+
+		return value, nil
+	}
+
+	return resolver, nil
+}
@@ -3,6 +3,8 @@ package main_test
 import (
 	"io/ioutil"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -60,3 +62,48 @@ func TestFormat(t *testing.T) {
 		}
 	})
 }
+
+func TestFormatRecursive(t *testing.T) {
+	expectedFormattedOutput := test.ReadFixture(t, expectedFormatOutput)
+
+	newTree := func(t *testing.T) string {
+		dir := t.TempDir()
+
+		writeFixtures(t, dir, map[string]string{
+			"README.tfdoc.hcl":                     expectedFormatOutput,
+			"modules/unformatted/README.tfdoc.hcl": formatInput,
+		})
+
+		return dir
+	}
+
+	t.Run("List", func(t *testing.T) {
+		dir := newTree(t)
+
+		cmd := exec.Command(terradocBinPath, "fmt", "-r", ".")
+		cmd.Dir = dir
+
+		var stdout strings.Builder
+		cmd.Stdout = &stdout
+
+		assert.Error(t, cmd.Run())
+		assert.EqualStrings(t, "modules/unformatted/README.tfdoc.hcl\n", stdout.String())
+	})
+
+	t.Run("Write", func(t *testing.T) {
+		dir := newTree(t)
+
+		cmd := exec.Command(terradocBinPath, "fmt", "-r", "-w", ".")
+		cmd.Dir = dir
+
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(output))
+
+		got, err := ioutil.ReadFile(filepath.Join(dir, "modules/unformatted/README.tfdoc.hcl"))
+		assert.NoError(t, err)
+
+		if diff := cmp.Diff(expectedFormattedOutput, got); diff != "" {
+			t.Errorf("Result is not expected (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -1,9 +1,14 @@
 package main_test
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -82,4 +87,473 @@ func TestGenerate(t *testing.T) {
 			t.Errorf("Result is not expected (-want +got):\n%s", diff)
 		}
 	})
+
+	t.Run("InjectIntoFile", func(t *testing.T) {
+		const (
+			intro = "# Hand-written intro\n\n<!-- BEGIN_TFDOC -->\n"
+			outro = "<!-- END_TFDOC -->\n\nHand-written outro\n"
+		)
+
+		f, err := ioutil.TempFile(t.TempDir(), "terradoc-output-")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		_, err = f.WriteString(intro + "outdated content\n" + outro)
+		assert.NoError(t, err)
+
+		// run twice to make sure the injection is idempotent
+		for i := 0; i < 2; i++ {
+			cmd := exec.Command(terradocBinPath, "generate", "--inject", "-o", f.Name(), inputFile.Name())
+
+			output, err := cmd.CombinedOutput()
+			assert.NoError(t, err, string(output))
+
+			result, err := ioutil.ReadFile(f.Name())
+			assert.NoError(t, err)
+
+			want := intro + strings.Trim(string(expectedOutput), "\n") + "\n" + outro
+
+			if diff := cmp.Diff(want, string(result)); diff != "" {
+				t.Errorf("Result is not expected (-want +got):\n%s", diff)
+			}
+		}
+	})
+
+	t.Run("InjectWithoutMarkers", func(t *testing.T) {
+		f, err := ioutil.TempFile(t.TempDir(), "terradoc-output-")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		_, err = f.WriteString("# README without markers\n")
+		assert.NoError(t, err)
+
+		cmd := exec.Command(terradocBinPath, "generate", "--inject", "-o", f.Name(), inputFile.Name())
+
+		output, err := cmd.CombinedOutput()
+		assert.Error(t, err)
+
+		if !strings.Contains(string(output), "not found") {
+			t.Errorf("Expected output to report missing markers but got %q", output)
+		}
+
+		result, err := ioutil.ReadFile(f.Name())
+		assert.NoError(t, err)
+		assert.EqualStrings(t, "# README without markers\n", string(result))
+	})
+
+	t.Run("CheckUpToDate", func(t *testing.T) {
+		f, err := ioutil.TempFile(t.TempDir(), "terradoc-output-")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		_, err = f.Write(expectedOutput)
+		assert.NoError(t, err)
+
+		cmd := exec.Command(terradocBinPath, "generate", "--check", "-o", f.Name(), inputFile.Name())
+
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(output))
+		assert.EqualStrings(t, "", string(output))
+	})
+
+	t.Run("CheckOutdated", func(t *testing.T) {
+		f, err := ioutil.TempFile(t.TempDir(), "terradoc-output-")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		outdated := strings.Replace(string(expectedOutput), "# terraform-google-secret-manager-iam", "# terraform-google-outdated-name-iam", 1)
+
+		_, err = f.WriteString(outdated)
+		assert.NoError(t, err)
+
+		cmd := exec.Command(terradocBinPath, "generate", "--check", "-o", f.Name(), inputFile.Name())
+
+		output, err := cmd.CombinedOutput()
+		assert.Error(t, err)
+
+		for _, want := range []string{
+			"--- " + f.Name() + "\n",
+			"+++ " + f.Name() + " (generated)\n",
+			"\n-# terraform-google-outdated-name-iam\n+# terraform-google-secret-manager-iam\n",
+			"is out of date",
+		} {
+			if !strings.Contains(string(output), want) {
+				t.Errorf("Expected output to contain %q but got:\n%s", want, output)
+			}
+		}
+
+		// check mode must never write the output file
+		result, err := ioutil.ReadFile(f.Name())
+		assert.NoError(t, err)
+		assert.EqualStrings(t, outdated, string(result))
+	})
+
+	t.Run("CheckInjected", func(t *testing.T) {
+		f, err := ioutil.TempFile(t.TempDir(), "terradoc-output-")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		_, err = f.WriteString("intro\n<!-- BEGIN_TFDOC -->\n" + string(expectedOutput) + "<!-- END_TFDOC -->\n")
+		assert.NoError(t, err)
+
+		cmd := exec.Command(terradocBinPath, "generate", "--check", "--inject", "-o", f.Name(), inputFile.Name())
+
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(output))
+	})
+
+	t.Run("FormatJSON", func(t *testing.T) {
+		cmd := exec.Command(terradocBinPath, "generate", "--format", "json", inputFile.Name())
+
+		output, err := cmd.Output()
+		assert.NoError(t, err)
+
+		var doc struct {
+			SchemaVersion string `json:"schema_version"`
+			Sections      []struct {
+				Title string `json:"title"`
+			} `json:"sections"`
+		}
+
+		err = json.Unmarshal(output, &doc)
+		assert.NoError(t, err)
+
+		assert.EqualStrings(t, "1", doc.SchemaVersion)
+		assert.EqualStrings(t, "terraform-google-secret-manager-iam", doc.Sections[0].Title)
+	})
+
+	t.Run("FormatHTML", func(t *testing.T) {
+		cmd := exec.Command(terradocBinPath, "generate", "--format", "html", inputFile.Name())
+
+		output, err := cmd.Output()
+		assert.NoError(t, err)
+
+		want := test.ReadFixture(t, "golden-readme.html")
+
+		if diff := cmp.Diff(string(want), string(output)); diff != "" {
+			t.Errorf("Result is not expected (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Templates", func(t *testing.T) {
+		dir := t.TempDir()
+
+		err := ioutil.WriteFile(filepath.Join(dir, "output.md"), []byte(`{{define "output"}}* output {{.Name}}{{newline}}{{end}}`), 0644)
+		assert.NoError(t, err)
+
+		cmd := exec.Command(terradocBinPath, "generate", "--templates", dir, inputFile.Name())
+
+		output, err := cmd.Output()
+		assert.NoError(t, err)
+
+		for _, want := range []string{"* output module_enabled\n", "[**`module_enabled`**](#var-module_enabled)"} {
+			if !strings.Contains(string(output), want) {
+				t.Errorf("wanted %q in output:\n%s", want, output)
+			}
+		}
+	})
+
+	t.Run("TemplatesFromConfig", func(t *testing.T) {
+		dir := t.TempDir()
+		templatesDir := filepath.Join(dir, "templates")
+
+		err := os.Mkdir(templatesDir, 0755)
+		assert.NoError(t, err)
+
+		err = ioutil.WriteFile(filepath.Join(templatesDir, "output.md"), []byte(`{{define "output"}}* output {{.Name}}{{newline}}{{end}}`), 0644)
+		assert.NoError(t, err)
+
+		err = ioutil.WriteFile(filepath.Join(dir, ".terradoc.json"), []byte(`{"templates": "templates"}`), 0644)
+		assert.NoError(t, err)
+
+		cmd := exec.Command(terradocBinPath, "generate", inputFile.Name())
+		cmd.Dir = dir
+
+		output, err := cmd.Output()
+		assert.NoError(t, err)
+
+		if !strings.Contains(string(output), "* output module_enabled\n") {
+			t.Errorf("wanted overridden output template in output:\n%s", output)
+		}
+	})
+
+	t.Run("TemplatesFromHomeConfig", func(t *testing.T) {
+		home := t.TempDir()
+		templatesDir := filepath.Join(home, "templates")
+
+		err := os.Mkdir(templatesDir, 0755)
+		assert.NoError(t, err)
+
+		err = ioutil.WriteFile(filepath.Join(templatesDir, "output.md"), []byte(`{{define "output"}}* output {{.Name}}{{newline}}{{end}}`), 0644)
+		assert.NoError(t, err)
+
+		// relative paths are resolved against the directory of the configuration file
+		err = ioutil.WriteFile(filepath.Join(home, ".terradoc.json"), []byte(`{"templates": "templates"}`), 0644)
+		assert.NoError(t, err)
+
+		cmd := exec.Command(terradocBinPath, "generate", inputFile.Name())
+		cmd.Dir = t.TempDir()
+		cmd.Env = append(os.Environ(), "HOME="+home)
+
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(output))
+
+		if !strings.Contains(string(output), "* output module_enabled\n") {
+			t.Errorf("wanted overridden output template in output:\n%s", output)
+		}
+	})
+
+	t.Run("ConfigOnlyForGenerate", func(t *testing.T) {
+		dir := t.TempDir()
+
+		writeFixtures(t, dir, map[string]string{
+			"README.tfdoc.hcl": "validate/variables/complete.tfdoc.hcl",
+			"variables.tf":     "validate/variables/complete-variables.tf",
+		})
+
+		err := ioutil.WriteFile(filepath.Join(dir, ".terradoc.json"), []byte(`{"format": "html"}`), 0644)
+		assert.NoError(t, err)
+
+		cmd := exec.Command(terradocBinPath, "validate", "-v", "README.tfdoc.hcl")
+		cmd.Dir = dir
+
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(output))
+
+		cmd = exec.Command(terradocBinPath, "generate", "README.tfdoc.hcl")
+		cmd.Dir = dir
+
+		output, err = cmd.CombinedOutput()
+		assert.NoError(t, err, string(output))
+
+		if !strings.HasPrefix(string(output), "<!DOCTYPE html>") {
+			t.Errorf("wanted the format of the configuration file in output:\n%s", output)
+		}
+	})
+
+	t.Run("TemplatesMissingDefine", func(t *testing.T) {
+		dir := t.TempDir()
+
+		err := ioutil.WriteFile(filepath.Join(dir, "variable.md"), []byte(`* {{.Name}}`), 0644)
+		assert.NoError(t, err)
+
+		cmd := exec.Command(terradocBinPath, "generate", "--templates", dir, inputFile.Name())
+
+		output, err := cmd.CombinedOutput()
+		assert.Error(t, err)
+
+		if !strings.Contains(string(output), `must define "variable"`) {
+			t.Errorf("wanted missing define error in output:\n%s", output)
+		}
+	})
+}
+
+func TestGenerateModuleValues(t *testing.T) {
+	dir := t.TempDir()
+
+	const doc = `locals {
+  source = "${module.source}?ref=${module.version}"
+}
+
+section {
+  title   = "Usage"
+  content = "source = \"${local.source}\""
+}
+`
+
+	err := ioutil.WriteFile(filepath.Join(dir, "README.tfdoc.hcl"), []byte(doc), 0644)
+	assert.NoError(t, err)
+
+	cmd := exec.Command(terradocBinPath, "generate", "--module-version", "v2.0.0", "README.tfdoc.hcl")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "TERRADOC_MODULE_SOURCE=github.com/acme/bucket")
+
+	output, err := cmd.CombinedOutput()
+	assert.NoError(t, err)
+
+	want := `source = "github.com/acme/bucket?ref=v2.0.0"`
+	if !strings.Contains(string(output), want+"\n") {
+		t.Errorf("wanted output to contain %q but got:\n%s", want, output)
+	}
+}
+
+func TestGenerateRequirements(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFixtures(t, dir, map[string]string{
+		"versions.tf": "validate/requirements/versions.tf",
+	})
+
+	const doc = `section {
+  title = "Requirements"
+
+  requirements {}
+}
+`
+
+	err := ioutil.WriteFile(filepath.Join(dir, "README.tfdoc.hcl"), []byte(doc), 0644)
+	assert.NoError(t, err)
+
+	cmd := exec.Command(terradocBinPath, "generate", "README.tfdoc.hcl")
+	cmd.Dir = dir
+
+	output, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(output))
+
+	want := "# Requirements\n\n" +
+		"- Terraform: `>= 1.0`\n" +
+		"- Provider `aws` (`hashicorp/aws`): `~> 4.0`\n" +
+		"- Provider `random` (`hashicorp/random`)\n\n"
+	if !strings.Contains(string(output), want) {
+		t.Errorf("wanted output to contain %q but got:\n%s", want, output)
+	}
+}
+
+func TestGenerateResources(t *testing.T) {
+	dir := t.TempDir()
+
+	const doc = `section {
+  title     = "Resources"
+  generated = "resources"
+}
+`
+
+	const main = `resource "aws_s3_bucket" "this" {
+  for_each = var.buckets
+}
+
+module "queue" {
+  source = "./modules/queue"
+}
+`
+
+	for name, content := range map[string]string{"README.tfdoc.hcl": doc, "main.tf": main} {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		assert.NoError(t, err)
+	}
+
+	cmd := exec.Command(terradocBinPath, "generate", "README.tfdoc.hcl")
+	cmd.Dir = dir
+
+	output, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(output))
+
+	want := "# Resources\n\n" +
+		"- Resource [`aws_s3_bucket.this`](https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/s3_bucket) *(for_each)*\n" +
+		"- Module `module.queue` (`./modules/queue`)\n\n"
+	if !strings.Contains(string(output), want) {
+		t.Errorf("wanted output to contain %q but got:\n%s", want, output)
+	}
+}
+
+func TestGenerateRecursive(t *testing.T) {
+	expectedOutput := test.ReadFixture(t, expectedGenerateOutput)
+
+	modules := []string{"modules/bucket", "modules/queue", "examples/basic"}
+
+	newTree := func(t *testing.T) string {
+		dir := t.TempDir()
+
+		files := map[string]string{}
+		for _, m := range modules {
+			files[filepath.Join(m, "README.tfdoc.hcl")] = generateInput
+		}
+
+		writeFixtures(t, dir, files)
+
+		return dir
+	}
+
+	t.Run("WriteReadmes", func(t *testing.T) {
+		dir := newTree(t)
+
+		cmd := exec.Command(terradocBinPath, "generate", "--recursive", dir)
+
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(output))
+
+		for _, m := range modules {
+			got, err := ioutil.ReadFile(filepath.Join(dir, m, "README.md"))
+			assert.NoError(t, err)
+
+			if diff := cmp.Diff(string(expectedOutput), string(got)); diff != "" {
+				t.Errorf("Result of %q is not expected (-want +got):\n%s", m, diff)
+			}
+		}
+	})
+
+	t.Run("OutputName", func(t *testing.T) {
+		dir := newTree(t)
+
+		cmd := exec.Command(terradocBinPath, "generate", "-r", "-f", "json", "-o", "doc.json", dir)
+
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(output))
+
+		for _, m := range modules {
+			_, err := os.Stat(filepath.Join(dir, m, "doc.json"))
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("OutputPath", func(t *testing.T) {
+		cmd := exec.Command(terradocBinPath, "generate", "-r", "-o", "docs/README.md", newTree(t))
+
+		output, err := cmd.CombinedOutput()
+		assert.Error(t, err)
+
+		if !strings.Contains(string(output), "--output must be a file name") {
+			t.Errorf("wanted output path error but got:\n%s", output)
+		}
+	})
+
+	t.Run("SameOutput", func(t *testing.T) {
+		dir := newTree(t)
+		writeFixtures(t, dir, map[string]string{"modules/queue/USAGE.tfdoc.hcl": generateInput})
+
+		cmd := exec.Command(terradocBinPath, "generate", "-r", dir)
+
+		output, err := cmd.CombinedOutput()
+		assert.Error(t, err)
+
+		want := fmt.Sprintf("%q and %q both generate %q",
+			filepath.Join(dir, "modules/queue/README.tfdoc.hcl"),
+			filepath.Join(dir, "modules/queue/USAGE.tfdoc.hcl"),
+			filepath.Join(dir, "modules/queue/README.md"))
+		if !strings.Contains(string(output), want) {
+			t.Errorf("wanted output to contain %q but got:\n%s", want, output)
+		}
+	})
+
+	t.Run("Check", func(t *testing.T) {
+		dir := newTree(t)
+
+		cmd := exec.Command(terradocBinPath, "generate", "-r", dir)
+		assert.NoError(t, cmd.Run())
+
+		stale := filepath.Join(dir, "modules/queue/README.md")
+		assert.NoError(t, ioutil.WriteFile(stale, []byte("stale\n"), 0644))
+
+		cmd = exec.Command(terradocBinPath, "generate", "-r", "--check", dir)
+
+		var stdout, stderr strings.Builder
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		assert.Error(t, cmd.Run())
+
+		if !strings.HasPrefix(stdout.String(), "--- "+stale+"\n") {
+			t.Errorf("wanted a diff of %q but got:\n%s", stale, stdout.String())
+		}
+
+		for _, want := range []string{
+			"ok\t" + filepath.Join(dir, "examples/basic/README.tfdoc.hcl"),
+			"FAIL\t" + filepath.Join(dir, "modules/queue/README.tfdoc.hcl"),
+			"1 of 3 modules failed",
+		} {
+			if !strings.Contains(stderr.String(), want) {
+				t.Errorf("wanted output to contain %q but got:\n%s", want, stderr.String())
+			}
+		}
+	})
 }
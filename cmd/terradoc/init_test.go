@@ -0,0 +1,79 @@
+package main_test
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/test"
+)
+
+func TestInit(t *testing.T) {
+	expectedOutput := test.ReadFixture(t, "init/expected.tfdoc.hcl")
+
+	newModuleDir := func(t *testing.T) string {
+		dir := t.TempDir()
+
+		for _, name := range []string{"variables.tf", "outputs.tf"} {
+			err := ioutil.WriteFile(filepath.Join(dir, name), test.ReadFixture(t, "init/"+name), 0644)
+			assert.NoError(t, err)
+		}
+
+		return dir
+	}
+
+	t.Run("WriteToStdout", func(t *testing.T) {
+		dir := newModuleDir(t)
+
+		cmd := exec.Command(terradocBinPath, "init", dir, "-t", "terraform-aws-s3-bucket", "-o", "-")
+
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err)
+
+		if diff := cmp.Diff(expectedOutput, output); diff != "" {
+			t.Errorf("Result is not expected (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("WriteToModuleDir", func(t *testing.T) {
+		dir := newModuleDir(t)
+
+		cmd := exec.Command(terradocBinPath, "init", dir, "-t", "terraform-aws-s3-bucket")
+
+		err := cmd.Run()
+		assert.NoError(t, err)
+
+		result, err := ioutil.ReadFile(filepath.Join(dir, "README.tfdoc.hcl"))
+		assert.NoError(t, err)
+
+		if diff := cmp.Diff(expectedOutput, result); diff != "" {
+			t.Errorf("Result is not expected (-want +got):\n%s", diff)
+		}
+
+		// the generated document must be usable right away
+		cmd = exec.Command(terradocBinPath, "generate", filepath.Join(dir, "README.tfdoc.hcl"))
+
+		_, err = cmd.CombinedOutput()
+		assert.NoError(t, err)
+	})
+
+	t.Run("DoesNotOverwriteExistingFile", func(t *testing.T) {
+		dir := newModuleDir(t)
+		docFile := filepath.Join(dir, "README.tfdoc.hcl")
+
+		err := ioutil.WriteFile(docFile, []byte("# hand-written"), 0644)
+		assert.NoError(t, err)
+
+		cmd := exec.Command(terradocBinPath, "init", dir)
+
+		err = cmd.Run()
+		assert.Error(t, err)
+
+		result, err := ioutil.ReadFile(docFile)
+		assert.NoError(t, err)
+		assert.EqualStrings(t, "# hand-written", string(result))
+	})
+}
@@ -0,0 +1,38 @@
+package main_test
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/madlambda/spells/assert"
+)
+
+func TestLsp(t *testing.T) {
+	var input strings.Builder
+
+	for _, msg := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"initialized","params":{}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	} {
+		fmt.Fprintf(&input, "Content-Length: %d\r\n\r\n%s", len(msg), msg)
+	}
+
+	cmd := exec.Command(terradocBinPath, "lsp")
+	cmd.Stdin = strings.NewReader(input.String())
+
+	output, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(output))
+
+	for _, want := range []string{
+		`"documentFormattingProvider":true`,
+		`{"jsonrpc":"2.0","id":2,"result":null}`,
+	} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("wanted output to contain %q but got:\n%s", want, output)
+		}
+	}
+}
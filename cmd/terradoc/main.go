@@ -1,12 +1,31 @@
 package main
 
 import (
+	"os"
+
 	"github.com/alecthomas/kong"
 	"github.com/mineiros-io/terradoc/cmd/terradoc/cli"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+	"github.com/mineiros-io/terradoc/internal/renderers/markdown"
 )
 
 func main() {
-	ctx := kong.Parse(&cli.Cli)
+	ctx := kong.Parse(&cli.Cli,
+		configuration(),
+		kong.Vars{
+			"default_begin_marker": markdown.DefaultBeginMarker,
+			"default_end_marker":   markdown.DefaultEndMarker,
+		},
+	)
 	err := ctx.Run()
+
+	if diags, ok := diagnostics.FromError(err); ok {
+		// print the source positions of the problems instead of the flattened error
+		diagWriter := diagnostics.NewWriter(os.Stderr)
+		if werr := diagWriter.Write(diags); werr == nil {
+			ctx.Exit(1)
+		}
+	}
+
 	ctx.FatalIfErrorf(err)
 }
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"runtime"
 	"testing"
+
+	"github.com/mineiros-io/terradoc/test"
 )
 
 var (
@@ -53,3 +55,20 @@ func TestMain(m *testing.M) {
 
 	os.Exit(result)
 }
+
+// writeFixtures copies fixtures into dir. Files maps the paths relative to dir to the fixture names.
+func writeFixtures(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, fixture := range files {
+		path := filepath.Join(dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("creating directory of %q: %v", name, err)
+		}
+
+		if err := os.WriteFile(path, test.ReadFixture(t, fixture), 0644); err != nil {
+			t.Fatalf("writing %q: %v", name, err)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package main_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -104,13 +107,13 @@ func TestValidateVariables(t *testing.T) {
 
 			output, err := cmd.CombinedOutput()
 
-			gotResult := splitOutputMessages(t, output, "variable")
+			gotResult := splitOutputMessages(output, "variable")
 
 			if tt.wantError {
 				assert.Error(t, err)
 
-				assertHasMissingDocumentation(t, docFile.Name(), gotResult.missingDocumentation, tt.wantMissingDocumentation, "variable")
-				assertHasMissingDefinition(t, gotResult.missingDefinition, tt.wantMissingDefinition, "variable")
+				assertHasMissingDocumentation(t, gotResult.missingDocumentation, tt.wantMissingDocumentation, "variable")
+				assertHasMissingDefinition(t, docFile.Name(), gotResult.missingDefinition, tt.wantMissingDefinition, "variable")
 				assertHasTypeMismatch(t, docFile.Name(), tt.wantTypeMismatch, gotResult.typeMismatch, "variable")
 			} else {
 				assert.NoError(t, err)
@@ -179,13 +182,13 @@ func TestValidateOutputs(t *testing.T) {
 
 			output, err := cmd.CombinedOutput()
 
-			gotResult := splitOutputMessages(t, output, "output")
+			gotResult := splitOutputMessages(output, "output")
 
 			if tt.wantError {
 				assert.Error(t, err)
 
-				assertHasMissingDocumentation(t, docFile.Name(), gotResult.missingDocumentation, tt.wantMissingDocumentation, "output")
-				assertHasMissingDefinition(t, gotResult.missingDefinition, tt.wantMissingDefinition, "output")
+				assertHasMissingDocumentation(t, gotResult.missingDocumentation, tt.wantMissingDocumentation, "output")
+				assertHasMissingDefinition(t, docFile.Name(), gotResult.missingDefinition, tt.wantMissingDefinition, "output")
 				assertHasTypeMismatch(t, docFile.Name(), tt.wantTypeMismatch, gotResult.typeMismatch, "output")
 			} else {
 				assert.NoError(t, err)
@@ -194,31 +197,147 @@ func TestValidateOutputs(t *testing.T) {
 	}
 }
 
+func TestValidateInferredOutputs(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFixtures(t, dir, map[string]string{
+		"README.tfdoc.hcl": "validate/outputs/inferred.tfdoc.hcl",
+		"outputs.tf":       "validate/outputs/inferred-outputs.tf",
+	})
+
+	cmd := exec.Command(terradocBinPath, "validate", "README.tfdoc.hcl", "-o", "--check-sensitive")
+	cmd.Dir = dir
+
+	output, err := cmd.CombinedOutput()
+	assert.Error(t, err)
+
+	for _, want := range []string{
+		`README.tfdoc.hcl:7:3: error: Type mismatch for output: "policy" is documented as "resource(aws_s3_bucket)" but defined as "resource(aws_s3_bucket_policy)" in .tf files [type-mismatch]`,
+		`README.tfdoc.hcl:17:3: error: Sensitive output not documented as sensitive: "password" is sensitive in .tf files but not documented as sensitive [sensitive-mismatch]`,
+	} {
+		if !strings.Contains(string(output), want+"\n") {
+			t.Errorf("wanted output to contain %q but got:\n%s", want, output)
+		}
+	}
+
+	for _, name := range []string{`"bucket"`, `"id"`} {
+		if strings.Contains(string(output), name) {
+			t.Errorf("wanted output %s to match, got:\n%s", name, output)
+		}
+	}
+}
+
+func TestValidateIncludes(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFixtures(t, dir, map[string]string{
+		"README.tfdoc.hcl":  "validate/include/README.tfdoc.hcl",
+		"inputs.tfdoc.hcl":  "validate/include/inputs.tfdoc.hcl",
+		"outputs.tfdoc.hcl": "validate/include/outputs.tfdoc.hcl",
+		"main.tf":           "validate/include/main.tf",
+	})
+
+	want := `inputs.tfdoc.hcl:4:3: error: Type mismatch for variable: "name" is documented as "number" but defined as "string" in .tf files [type-mismatch]`
+
+	cmd := exec.Command(terradocBinPath, "validate", "README.tfdoc.hcl")
+	cmd.Dir = dir
+
+	output, err := cmd.CombinedOutput()
+	assert.Error(t, err)
+
+	if !strings.HasPrefix(string(output), want+"\n") {
+		t.Errorf("wanted output to start with %q but got:\n%s", want, output)
+	}
+
+	// included files are validated as part of the including file only
+	cmd = exec.Command(terradocBinPath, "validate", "--recursive", ".")
+	cmd.Dir = dir
+
+	output, err = cmd.CombinedOutput()
+	assert.Error(t, err)
+
+	if got := strings.Count(string(output), "[type-mismatch]"); got != 1 {
+		t.Errorf("wanted 1 type mismatch but got %d:\n%s", got, output)
+	}
+
+	if strings.Contains(string(output), "[missing-documentation]") {
+		t.Errorf("wanted included files not to be validated on their own, got:\n%s", output)
+	}
+}
+
+func TestValidateReferences(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFixtures(t, dir, map[string]string{
+		"README.tfdoc.hcl": "validate/references/README.tfdoc.hcl",
+		"main.tf":          "validate/references/main.tf",
+	})
+
+	cmd := exec.Command(terradocBinPath, "validate", "README.tfdoc.hcl")
+	cmd.Dir = dir
+
+	output, err := cmd.CombinedOutput()
+	assert.Error(t, err)
+
+	for _, want := range []string{
+		`README.tfdoc.hcl:4:44: error: Undefined reference: "changelog" is not defined in the ` + "`references`" + ` block [undefined-reference]`,
+		`README.tfdoc.hcl:10:34: error: Undefined reference: "naming" is not defined in the ` + "`references`" + ` block [undefined-reference]`,
+		`README.tfdoc.hcl:23:3: warning: Unused reference: "slack" is not used in any content or description [unused-reference]`,
+	} {
+		if !strings.Contains(string(output), want+"\n") {
+			t.Errorf("wanted output to contain %q but got:\n%s", want, output)
+		}
+	}
+
+	if got := strings.Count(string(output), "reference]"); got != 3 {
+		t.Errorf("wanted 3 reference problems but got %d:\n%s", got, output)
+	}
+}
+
 type validationResult struct {
-	missingDocumentation []string
-	missingDefinition    []string
-	typeMismatch         []string
+	missingDocumentation []diagnosticLine
+	missingDefinition    []diagnosticLine
+	typeMismatch         []diagnosticLine
 }
 
-func splitOutputMessages(t *testing.T, output []byte, validationType string) validationResult {
+// diagnosticLine is a diagnostic printed as `file:line:col: error: message [code]`
+type diagnosticLine struct {
+	file    string
+	message string
+}
+
+func splitOutputMessages(output []byte, validationType string) validationResult {
 	result := validationResult{}
 	outputStrings := strings.Split(string(output), "\n")
 
 	for _, oo := range outputStrings {
+		parts := strings.SplitN(oo, ": error: ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		// skip errors that are not diagnostics, e.g. "terradoc: error: Found validation errors"
+		pos := strings.Split(parts[0], ":")
+		if len(pos) != 3 {
+			continue
+		}
+
+		diag := diagnosticLine{file: pos[0], message: parts[1]}
+
 		switch {
-		case strings.HasPrefix(oo, fmt.Sprintf("Unknown %s documented:", validationType)):
-			result.missingDefinition = append(result.missingDefinition, oo)
-		case strings.HasPrefix(oo, fmt.Sprintf("Missing %s documentation:", validationType)):
-			result.missingDocumentation = append(result.missingDocumentation, oo)
-		case strings.HasPrefix(oo, fmt.Sprintf("Type mismatch for %s:", validationType)):
-			result.typeMismatch = append(result.typeMismatch, oo)
+		case strings.HasPrefix(diag.message, fmt.Sprintf("Unknown %s documented:", validationType)):
+			result.missingDefinition = append(result.missingDefinition, diag)
+		case strings.HasPrefix(diag.message, fmt.Sprintf("Missing %s documentation:", validationType)):
+			result.missingDocumentation = append(result.missingDocumentation, diag)
+		case strings.HasPrefix(diag.message, fmt.Sprintf("Type mismatch for %s:", validationType)):
+			result.typeMismatch = append(result.typeMismatch, diag)
 		}
 	}
 
 	return result
 }
 
-func assertHasMissingDocumentation(t *testing.T, filename string, got, want []string, validationType string) {
+func assertHasMissingDocumentation(t *testing.T, got []diagnosticLine, want []string, validationType string) {
 	t.Helper()
 
 	if len(got) != len(want) {
@@ -228,10 +347,11 @@ func assertHasMissingDocumentation(t *testing.T, filename string, got, want []st
 	for _, wantStr := range want {
 		found := false
 
-		completeWantString := fmt.Sprintf("Missing %s documentation: %q is not documented in %q", validationType, wantStr, filename)
+		completeWantString := fmt.Sprintf("Missing %s documentation: %q is not documented [missing-documentation]", validationType, wantStr)
 
-		for _, msg := range got {
-			if msg == completeWantString {
+		for _, diag := range got {
+			// missing documentation is reported at the definition in the .tf files
+			if diag.message == completeWantString && strings.HasSuffix(diag.file, ".tf") {
 				found = true
 
 				break
@@ -244,7 +364,7 @@ func assertHasMissingDocumentation(t *testing.T, filename string, got, want []st
 	}
 }
 
-func assertHasMissingDefinition(t *testing.T, got, want []string, validationType string) {
+func assertHasMissingDefinition(t *testing.T, docFilename string, got []diagnosticLine, want []string, validationType string) {
 	t.Helper()
 
 	if len(got) != len(want) {
@@ -254,9 +374,9 @@ func assertHasMissingDefinition(t *testing.T, got, want []string, validationType
 	for _, wantStr := range want {
 		found := false
 
-		completeWantString := fmt.Sprintf("Unknown %s documented: %q is not defined in any .tf files", validationType, wantStr)
-		for _, msg := range got {
-			if msg == completeWantString {
+		completeWantString := fmt.Sprintf("Unknown %s documented: %q is not defined in any .tf files [missing-definition]", validationType, wantStr)
+		for _, diag := range got {
+			if diag.message == completeWantString && diag.file == docFilename {
 				found = true
 
 				break
@@ -269,7 +389,7 @@ func assertHasMissingDefinition(t *testing.T, got, want []string, validationType
 	}
 }
 
-func assertHasTypeMismatch(t *testing.T, docFilename string, want []validators.TypeMismatchResult, got []string, validationType string) {
+func assertHasTypeMismatch(t *testing.T, docFilename string, want []validators.TypeMismatchResult, got []diagnosticLine, validationType string) {
 	t.Helper()
 
 	if len(got) != len(want) {
@@ -279,10 +399,10 @@ func assertHasTypeMismatch(t *testing.T, docFilename string, want []validators.T
 	for _, tm := range want {
 		found := false
 
-		completeWantString := fmt.Sprintf("Type mismatch for %s: %q is documented as %q in %q but defined as %q in .tf files", validationType, tm.Name, tm.DocumentedType, docFilename, tm.DefinedType)
+		completeWantString := fmt.Sprintf("Type mismatch for %s: %q is documented as %q but defined as %q in .tf files [type-mismatch]", validationType, tm.Name, tm.DocumentedType, tm.DefinedType)
 
-		for _, msg := range got {
-			if msg == completeWantString {
+		for _, diag := range got {
+			if diag.message == completeWantString && diag.file == docFilename {
 				found = true
 
 				break
@@ -294,3 +414,326 @@ func assertHasTypeMismatch(t *testing.T, docFilename string, want []validators.T
 		}
 	}
 }
+
+func TestValidateReportFormats(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile := func(name, fixture string) {
+		t.Helper()
+
+		err := ioutil.WriteFile(filepath.Join(dir, name), test.ReadFixture(t, fixture), 0644)
+		assert.NoError(t, err)
+	}
+
+	writeFile("README.tfdoc.hcl", "validate/variables/complete.tfdoc.hcl")
+	writeFile("variables.tf", "validate/variables/type-mismatch-with-missing.tf")
+
+	validate := func(t *testing.T, format string) []byte {
+		t.Helper()
+
+		cmd := exec.Command(terradocBinPath, "validate", "README.tfdoc.hcl", "-v", "--format", format)
+		cmd.Dir = dir
+
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		// the module has validation errors
+		assert.Error(t, cmd.Run())
+
+		if strings.Contains(stderr.String(), "[missing-definition]") {
+			t.Errorf("wanted results only in the report but got diagnostics on stderr:\n%s", stderr.String())
+		}
+
+		return stdout.Bytes()
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		var got struct {
+			Success bool
+			Results []struct {
+				Code           string
+				Name           string
+				File           string
+				DocumentedType string `json:"documented_type"`
+			}
+		}
+
+		assert.NoError(t, json.Unmarshal(validate(t, "json"), &got))
+
+		if got.Success {
+			t.Error("wanted report to be unsuccessful")
+		}
+
+		assert.EqualInts(t, 2, len(got.Results))
+		// results are ordered by their position in the doc file
+		assert.EqualStrings(t, validators.CodeTypeMismatch, got.Results[0].Code)
+		assert.EqualStrings(t, "person", got.Results[0].Name)
+		assert.EqualStrings(t, "README.tfdoc.hcl", got.Results[0].File)
+		assert.EqualStrings(t, "object(person)", got.Results[0].DocumentedType)
+		assert.EqualStrings(t, validators.CodeMissingDefinition, got.Results[1].Code)
+		assert.EqualStrings(t, "beer", got.Results[1].Name)
+	})
+
+	t.Run("SARIF", func(t *testing.T) {
+		var got struct {
+			Runs []struct {
+				Results []struct {
+					RuleID    string
+					Locations []struct {
+						PhysicalLocation struct {
+							ArtifactLocation struct {
+								URI string
+							}
+						}
+					}
+				}
+			}
+		}
+
+		assert.NoError(t, json.Unmarshal(validate(t, "sarif"), &got))
+
+		assert.EqualInts(t, 1, len(got.Runs))
+		assert.EqualInts(t, 2, len(got.Runs[0].Results))
+
+		for _, r := range got.Runs[0].Results {
+			assert.EqualStrings(t, "README.tfdoc.hcl", r.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		}
+	})
+
+	t.Run("JUnit", func(t *testing.T) {
+		var got struct {
+			Tests    int `xml:"tests,attr"`
+			Failures int `xml:"failures,attr"`
+		}
+
+		assert.NoError(t, xml.Unmarshal(validate(t, "junit"), &got))
+
+		// person, number, cars and the documented beer variable are checked
+		assert.EqualInts(t, 4, got.Tests)
+		assert.EqualInts(t, 2, got.Failures)
+	})
+}
+
+func TestValidateRecursive(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFixtures(t, dir, map[string]string{
+		"README.tfdoc.hcl":                           "validate/variables/complete.tfdoc.hcl",
+		"variables.tf":                               "validate/variables/complete-variables.tf",
+		"modules/complete/README.tfdoc.hcl":          "validate/variables/complete.tfdoc.hcl",
+		"modules/complete/variables.tf":              "validate/variables/complete-variables.tf",
+		"modules/missing/README.tfdoc.hcl":           "validate/variables/complete.tfdoc.hcl",
+		"modules/missing/variables.tf":               "validate/variables/missing-variables.tf",
+		".terraform/modules/cached/README.tfdoc.hcl": "validate/variables/complete.tfdoc.hcl",
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		cmd := exec.Command(terradocBinPath, "validate", "--recursive", "-v")
+		cmd.Dir = dir
+
+		output, err := cmd.CombinedOutput()
+		assert.Error(t, err)
+
+		gotResult := splitOutputMessages(output, "variable")
+		assertHasMissingDefinition(t, "modules/missing/README.tfdoc.hcl", gotResult.missingDefinition, []string{"beer"}, "variable")
+
+		for _, want := range []string{
+			"ok\tREADME.tfdoc.hcl\n",
+			"ok\tmodules/complete/README.tfdoc.hcl\n",
+			"FAIL\tmodules/missing/README.tfdoc.hcl: Found validation errors\n",
+			"1 of 3 modules failed",
+		} {
+			if !strings.Contains(string(output), want) {
+				t.Errorf("wanted output to contain %q but got:\n%s", want, output)
+			}
+		}
+
+		if strings.Contains(string(output), ".terraform") {
+			t.Errorf("wanted hidden directories to be skipped but got:\n%s", output)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		cmd := exec.Command(terradocBinPath, "validate", "--recursive", "-v", "--format", "json", dir)
+
+		var stdout bytes.Buffer
+		cmd.Stdout = &stdout
+
+		assert.Error(t, cmd.Run())
+
+		var got struct {
+			Results []struct {
+				Module string
+				Name   string
+			}
+		}
+
+		assert.NoError(t, json.Unmarshal(stdout.Bytes(), &got))
+
+		assert.EqualInts(t, 1, len(got.Results))
+		assert.EqualStrings(t, filepath.Join(dir, "modules/missing"), got.Results[0].Module)
+		assert.EqualStrings(t, "beer", got.Results[0].Name)
+	})
+}
+
+func TestValidateOptionalChecks(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFixtures(t, dir, map[string]string{
+		"README.tfdoc.hcl": "validate/variables/optional-checks.tfdoc.hcl",
+		"variables.tf":     "validate/variables/optional-checks-variables.tf",
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		cmd := exec.Command(terradocBinPath, "validate", "README.tfdoc.hcl", "-v")
+		cmd.Dir = dir
+
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(output))
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		cmd := exec.Command(terradocBinPath, "validate", "README.tfdoc.hcl", "-v",
+			"--check-required", "--check-defaults", "--check-descriptions")
+		cmd.Dir = dir
+
+		output, err := cmd.CombinedOutput()
+		assert.Error(t, err)
+
+		for _, want := range []string{
+			`README.tfdoc.hcl:2:3: error: Required variable has a default: "name" is documented as required but has a default in .tf files [required-with-default]`,
+			`README.tfdoc.hcl:2:3: error: Description mismatch for variable: "name" is documented with description "The name of the bucket." but defined with description "The bucket name." in .tf files [description-mismatch]`,
+			`README.tfdoc.hcl:8:3: error: Default mismatch for variable: "retries" is documented with default 5 but defined with default 3 in .tf files [default-mismatch]`,
+		} {
+			if !strings.Contains(string(output), want+"\n") {
+				t.Errorf("wanted output to contain %q but got:\n%s", want, output)
+			}
+		}
+	})
+}
+
+func TestValidateUncheckedBlocks(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFixtures(t, dir, map[string]string{
+		"README.tfdoc.hcl": "validate/unchecked/README.tfdoc.hcl",
+		"main.tf":          "validate/unchecked/main.tf",
+	})
+
+	// invalid requirements and resources are only reported if they are checked
+	cmd := exec.Command(terradocBinPath, "validate", "README.tfdoc.hcl", "-v")
+	cmd.Dir = dir
+
+	output, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(output))
+
+	cmd = exec.Command(terradocBinPath, "validate", "README.tfdoc.hcl", "-v", "--check-requirements")
+	cmd.Dir = dir
+
+	output, err = cmd.CombinedOutput()
+	assert.Error(t, err)
+
+	if !strings.Contains(string(output), "main.tf:3:22: error") {
+		t.Errorf("wanted output to report the invalid requirements but got:\n%s", output)
+	}
+}
+
+func TestValidateRequirements(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFixtures(t, dir, map[string]string{
+		"README.tfdoc.hcl": "validate/requirements/README.tfdoc.hcl",
+		"versions.tf":      "validate/requirements/versions.tf",
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		cmd := exec.Command(terradocBinPath, "validate", "README.tfdoc.hcl")
+		cmd.Dir = dir
+
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(output))
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		cmd := exec.Command(terradocBinPath, "validate", "README.tfdoc.hcl", "--check-requirements")
+		cmd.Dir = dir
+
+		output, err := cmd.CombinedOutput()
+		assert.Error(t, err)
+
+		for _, want := range []string{
+			`README.tfdoc.hcl:4:3: error: Requirement mismatch: "terraform.required_version" is documented as ">= 0.15" but declared as ">= 1.0" in .tf files [requirement-mismatch]`,
+			`README.tfdoc.hcl:7:5: error: Requirement mismatch: "required_providers.aws.version" is documented as ">= 4.0" but declared as "~> 4.0" in .tf files [requirement-mismatch]`,
+			`README.tfdoc.hcl:12:5: error: Unknown requirement documented: "required_providers.google" is not defined in any .tf files [missing-definition]`,
+			`versions.tf:9:5: error: Missing requirement documentation: "required_providers.random" is not documented [missing-documentation]`,
+		} {
+			if !strings.Contains(string(output), want+"\n") {
+				t.Errorf("wanted output to contain %q but got:\n%s", want, output)
+			}
+		}
+	})
+}
+
+func TestValidateAttributes(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFixtures(t, dir, map[string]string{
+		"README.tfdoc.hcl": "validate/variables/attributes.tfdoc.hcl",
+		"variables.tf":     "validate/variables/attributes-variables.tf",
+	})
+
+	cmd := exec.Command(terradocBinPath, "validate", "README.tfdoc.hcl", "-v")
+	cmd.Dir = dir
+
+	output, err := cmd.CombinedOutput()
+	assert.Error(t, err)
+
+	for _, want := range []string{
+		`README.tfdoc.hcl:9:5: error: Type mismatch for attribute: "var.rules[*].ports" is documented as "list(string)" but declared as "list(number)" in .tf files [attribute-type-mismatch]`,
+		`README.tfdoc.hcl:13:5: error: Unknown attribute documented: "var.rules[*].priority" is not declared in the variable type in .tf files [missing-attribute-definition]`,
+		`variables.tf:5:5: error: Missing attribute documentation: "var.rules[*].protocol" is not documented [missing-attribute-documentation]`,
+	} {
+		if !strings.Contains(string(output), want+"\n") {
+			t.Errorf("wanted output to contain %q but got:\n%s", want, output)
+		}
+	}
+}
+
+func TestValidateOptionalAttributes(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFixtures(t, dir, map[string]string{
+		"README.tfdoc.hcl": "validate/variables/optional-attributes.tfdoc.hcl",
+		"variables.tf":     "validate/variables/optional-attributes-variables.tf",
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		cmd := exec.Command(terradocBinPath, "validate", "README.tfdoc.hcl", "-v")
+		cmd.Dir = dir
+
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(output))
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		cmd := exec.Command(terradocBinPath, "validate", "README.tfdoc.hcl", "-v", "--check-required", "--check-defaults")
+		cmd.Dir = dir
+
+		output, err := cmd.CombinedOutput()
+		assert.Error(t, err)
+
+		for _, want := range []string{
+			`README.tfdoc.hcl:10:5: error: Required mismatch for attribute: "var.rules[*].port" is documented as required but declared as optional in .tf files [attribute-required-mismatch]`,
+			`README.tfdoc.hcl:15:5: error: Default mismatch for attribute: "var.rules[*].protocol" is documented with default "udp" but declared with default "tcp" in .tf files [attribute-default-mismatch]`,
+		} {
+			if !strings.Contains(string(output), want+"\n") {
+				t.Errorf("wanted output to contain %q but got:\n%s", want, output)
+			}
+		}
+
+		if strings.Contains(string(output), `"var.rules[*].name"`) {
+			t.Errorf("wanted required attribute %q to match, got:\n%s", "name", output)
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package main_test
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/madlambda/spells/assert"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFile := func(name, content string) {
+		t.Helper()
+		writeAtomically(t, filepath.Join(dir, name), content)
+	}
+
+	writeFile("variables.tf", "variable \"name\" {\n  type = string\n}\n")
+	writeFile("README.tfdoc.hcl", "section {\n  title = \"Example\"\n\n  variable \"name\" {\n    type = number\n  }\n}\n")
+
+	cmd, nextStatus := startWatch(t, dir, "README.tfdoc.hcl")
+
+	nextStatus(" README.md updated, 1 validation problem")
+
+	writeFile("README.tfdoc.hcl", "section {\n  title = \"Example\"\n\n  variable \"name\" {\n    type = string\n  }\n}\n")
+	nextStatus(" README.md updated, valid")
+
+	readme, err := ioutil.ReadFile(filepath.Join(dir, "README.md"))
+	assert.NoError(t, err)
+
+	if !strings.Contains(string(readme), "`string`") {
+		t.Errorf("wanted README.md to be regenerated, got:\n%s", readme)
+	}
+
+	writeFile("variables.tf", "variable \"name\" {\n  type = string\n}\n\nvariable \"age\" {\n  type = number\n}\n")
+	nextStatus(" README.md unchanged, 1 validation problem")
+
+	writeFile("README.tfdoc.hcl", "section {\n")
+	nextStatus(" README.md not generated, 1 problem")
+
+	assert.NoError(t, cmd.Process.Signal(os.Interrupt))
+	assert.NoError(t, cmd.Wait())
+}
+
+func TestWatchIncludes(t *testing.T) {
+	dir := t.TempDir()
+	moduleDir := filepath.Join(dir, "module")
+	sharedDir := filepath.Join(dir, "shared")
+
+	assert.NoError(t, os.Mkdir(moduleDir, 0755))
+	assert.NoError(t, os.Mkdir(sharedDir, 0755))
+
+	writeAtomically(t, filepath.Join(moduleDir, "README.tfdoc.hcl"), "include \"../shared/*.tfdoc.hcl\" {}\n")
+	writeAtomically(t, filepath.Join(sharedDir, "usage.tfdoc.hcl"), "section {\n  title = \"Usage\"\n}\n")
+
+	_, nextStatus := startWatch(t, moduleDir, "README.tfdoc.hcl", "--no-validate")
+
+	nextStatus(" README.md updated")
+
+	writeAtomically(t, filepath.Join(sharedDir, "usage.tfdoc.hcl"), "section {\n  title = \"Getting Started\"\n}\n")
+	nextStatus(" README.md updated")
+
+	readme, err := ioutil.ReadFile(filepath.Join(moduleDir, "README.md"))
+	assert.NoError(t, err)
+
+	if !strings.Contains(string(readme), "Getting Started") {
+		t.Errorf("wanted README.md to be regenerated, got:\n%s", readme)
+	}
+
+	// files matching the include pattern later are watched, too
+	writeAtomically(t, filepath.Join(sharedDir, "variables.tfdoc.hcl"), "section {\n  title = \"Inputs\"\n}\n")
+	nextStatus(" README.md updated")
+}
+
+func TestWatchTemplatesInModuleDir(t *testing.T) {
+	dir := t.TempDir()
+
+	writeAtomically(t, filepath.Join(dir, "README.tfdoc.hcl"), "section {\n  title = \"Example\"\n\n  output \"id\" {\n    type = string\n  }\n}\n")
+	writeAtomically(t, filepath.Join(dir, "output.md"), `{{define "output"}}* output {{.Name}}{{newline}}{{end}}`)
+
+	_, nextStatus := startWatch(t, dir, "README.tfdoc.hcl", "--no-validate", "--templates", ".")
+
+	nextStatus(" README.md updated")
+
+	writeAtomically(t, filepath.Join(dir, "output.md"), `{{define "output"}}* changed output {{.Name}}{{newline}}{{end}}`)
+	nextStatus(" README.md updated")
+
+	readme, err := ioutil.ReadFile(filepath.Join(dir, "README.md"))
+	assert.NoError(t, err)
+
+	if !strings.Contains(string(readme), "* changed output id") {
+		t.Errorf("wanted README.md to be regenerated with the changed template, got:\n%s", readme)
+	}
+}
+
+// writeAtomically writes and renames a file like editors that save atomically
+func writeAtomically(t *testing.T, name, content string) {
+	t.Helper()
+
+	tmp := filepath.Join(filepath.Dir(name), "."+filepath.Base(name)+".tmp")
+	assert.NoError(t, ioutil.WriteFile(tmp, []byte(content), 0644))
+	assert.NoError(t, os.Rename(tmp, name))
+}
+
+// startWatch runs the watch command in dir and returns it with a function asserting the next
+// status line printed by it. The command is killed when the test ends.
+func startWatch(t *testing.T, dir string, args ...string) (*exec.Cmd, func(want string)) {
+	t.Helper()
+
+	cmd := exec.Command(terradocBinPath, append([]string{"watch", "--debounce", "50ms"}, args...)...)
+	cmd.Dir = dir
+
+	stdout, err := cmd.StdoutPipe()
+	assert.NoError(t, err)
+	assert.NoError(t, cmd.Start())
+
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	lines := make(chan string)
+
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	nextStatus := func(want string) {
+		t.Helper()
+
+		select {
+		case line := <-lines:
+			if !strings.HasSuffix(line, want) {
+				t.Fatalf("wanted status %q but got %q", want, line)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timeout waiting for status %q", want)
+		}
+	}
+
+	return cmd, nextStatus
+}
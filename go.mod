@@ -3,16 +3,16 @@ module github.com/mineiros-io/terradoc
 go 1.17
 
 require (
+	github.com/alecthomas/kong v0.3.0
 	github.com/google/go-cmp v0.5.6
 	github.com/hashicorp/hcl/v2 v2.10.1
+	github.com/madlambda/spells v0.2.0
 	github.com/zclconf/go-cty v1.9.1
 )
 
 require (
 	github.com/agext/levenshtein v1.2.1 // indirect
-	github.com/alecthomas/kong v0.3.0 // indirect
 	github.com/apparentlymart/go-textseg/v13 v13.0.0 // indirect
-	github.com/madlambda/spells v0.2.0 // indirect
 	github.com/mitchellh/go-wordwrap v0.0.0-20150314170334-ad45545899c7 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	golang.org/x/text v0.3.6 // indirect
@@ -0,0 +1,114 @@
+// Package detect detects the values of the expressions of a document from its module directory,
+// the git repository of the module and the module's .tf files
+package detect
+
+import (
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/parsers/docparser"
+	"github.com/mineiros-io/terradoc/internal/parsers/validationparser"
+)
+
+// Options returns opts with the values that are not set detected for the documents in the module
+// directory dir. `module.version` defaults to the latest git tag, `module.source` to the `origin`
+// remote of the git repository and the path of the module in it, e.g.
+// `github.com/org/repo//modules/bucket`, and the .tf files are read from dir.
+func Options(dir string, opts docparser.Options) docparser.Options {
+	if opts.ModuleVersion == "" {
+		opts.ModuleVersion = git(dir, "describe", "--tags", "--abbrev=0")
+	}
+
+	if opts.ModuleSource == "" {
+		opts.ModuleSource = gitSource(dir)
+	}
+
+	if opts.Code == nil {
+		opts.Code = Code(dir)
+	}
+
+	return opts
+}
+
+// Code returns a loader parsing the .tf files of dir. Files that can not be parsed are ignored,
+// they are reported by validation.
+func Code(dir string) docparser.CodeLoader {
+	return func(opts validationparser.Options) entities.ValidationContents {
+		code := entities.ValidationContents{}
+
+		files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+		if err != nil {
+			return code
+		}
+
+		for _, file := range files {
+			content, err := validationparser.ParseFile(file, opts)
+			if err != nil {
+				continue
+			}
+
+			code = code.Merge(content)
+		}
+
+		return code
+	}
+}
+
+// git returns the trimmed output of a git command run in dir or an empty string if it fails
+func git(dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// gitSource returns the module source of the directory in the repository of the `origin` remote
+func gitSource(dir string) string {
+	remote := git(dir, "remote", "get-url", "origin")
+	if remote == "" {
+		return ""
+	}
+
+	source := moduleSource(remote)
+
+	if prefix := strings.TrimSuffix(git(dir, "rev-parse", "--show-prefix"), "/"); prefix != "" {
+		source += "//" + prefix
+	}
+
+	return source
+}
+
+// moduleSource returns the Terraform module source of a git remote URL. GitHub and Bitbucket
+// repositories are written as `github.com/org/repo`, other repositories as `git::https://...`.
+func moduleSource(remote string) string {
+	var host, path string
+
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Host != "" {
+		host, path = u.Hostname(), strings.TrimPrefix(u.Path, "/")
+	} else if i := strings.Index(remote, ":"); i >= 0 {
+		// scp-like syntax, e.g. `git@github.com:org/repo.git`
+		host, path = remote[:i], remote[i+1:]
+		if j := strings.LastIndex(host, "@"); j >= 0 {
+			host = host[j+1:]
+		}
+	} else {
+		return remote
+	}
+
+	path = strings.TrimSuffix(path, ".git")
+
+	switch host {
+	case "github.com", "bitbucket.org":
+		return host + "/" + path
+	}
+
+	return "git::https://" + host + "/" + path + ".git"
+}
@@ -0,0 +1,52 @@
+package detect_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/internal/detect"
+	"github.com/mineiros-io/terradoc/internal/parsers/docparser"
+	"github.com/mineiros-io/terradoc/internal/parsers/validationparser"
+)
+
+func TestOptions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	moduleDir := filepath.Join(dir, "modules", "bucket")
+
+	assert.NoError(t, os.MkdirAll(moduleDir, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte("terraform {\n  required_version = \">= 1.0\"\n}\n"), 0644))
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"remote", "add", "origin", "git@github.com:acme/terraform-aws-bucket.git"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+		{"tag", "v0.3.1"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("running git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	opts := detect.Options(moduleDir, docparser.Options{})
+	assert.EqualStrings(t, "v0.3.1", opts.ModuleVersion)
+	assert.EqualStrings(t, "github.com/acme/terraform-aws-bucket//modules/bucket", opts.ModuleSource)
+
+	code := opts.Code(validationparser.Options{Requirements: true})
+	assert.EqualStrings(t, ">= 1.0", code.Requirements.TerraformVersion)
+
+	// values that are set are not detected
+	opts = detect.Options(moduleDir, docparser.Options{ModuleVersion: "v1.0.0"})
+	assert.EqualStrings(t, "v1.0.0", opts.ModuleVersion)
+}
@@ -0,0 +1,174 @@
+package diagnostics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Severity is the severity of a diagnostic
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+)
+
+// Codes of the diagnostics reported by the parsers
+const (
+	// CodeSyntax is reported for files that are not valid HCL
+	CodeSyntax = "syntax"
+	// CodeSchema is reported for blocks and attributes that do not match the expected schema
+	CodeSchema = "schema"
+	// CodeInvalidValue is reported for attribute values that can not be used
+	CodeInvalidValue = "invalid-value"
+	// CodeInvalidType is reported for invalid type expressions
+	CodeInvalidType = "invalid-type"
+	// CodeUndefinedReference is reported for references that are used but not defined
+	CodeUndefinedReference = "undefined-reference"
+)
+
+// Diagnostic is a problem found in a source file
+type Diagnostic struct {
+	Severity Severity
+	// Code identifies the kind of problem, e.g. "syntax" or "missing-documentation"
+	Code string
+	// Summary is a short description of the problem
+	Summary string
+	// Detail is an optional longer description of the problem
+	Detail string
+	// Range is the source range the problem refers to. It may be empty if the
+	// problem does not refer to a source position.
+	Range hcl.Range
+}
+
+// New returns an error diagnostic for the given source range
+func New(code string, rng hcl.Range, summary, detail string) Diagnostic {
+	return Diagnostic{
+		Severity: Error,
+		Code:     code,
+		Summary:  summary,
+		Detail:   detail,
+		Range:    rng,
+	}
+}
+
+// HasPos reports whether the diagnostic refers to a source position
+func (d Diagnostic) HasPos() bool {
+	return d.Range.Filename != "" && d.Range.Start.Line > 0
+}
+
+// Pos returns the position of the diagnostic as `file:line:col`
+func (d Diagnostic) Pos() string {
+	if !d.HasPos() {
+		return d.Range.Filename
+	}
+
+	return fmt.Sprintf("%s:%d:%d", d.Range.Filename, d.Range.Start.Line, d.Range.Start.Column)
+}
+
+// Message returns the summary and the detail of the diagnostic
+func (d Diagnostic) Message() string {
+	if d.Detail == "" {
+		return d.Summary
+	}
+
+	return fmt.Sprintf("%s: %s", d.Summary, d.Detail)
+}
+
+func (d Diagnostic) Error() string {
+	msg := d.Summary
+	if d.Detail != "" {
+		msg = fmt.Sprintf("%s; %s", d.Summary, d.Detail)
+	}
+
+	if pos := d.Pos(); pos != "" {
+		return fmt.Sprintf("%s: %s", pos, msg)
+	}
+
+	return msg
+}
+
+// Diagnostics is a list of diagnostics which can be returned as an error
+type Diagnostics []Diagnostic
+
+// FromHCL converts the diagnostics reported by the hcl package, giving them the provided code
+func FromHCL(code string, hclDiags hcl.Diagnostics) Diagnostics {
+	var diags Diagnostics
+
+	for _, hclDiag := range hclDiags {
+		diag := Diagnostic{
+			Severity: Error,
+			Code:     code,
+			Summary:  hclDiag.Summary,
+			Detail:   hclDiag.Detail,
+		}
+
+		if hclDiag.Severity == hcl.DiagWarning {
+			diag.Severity = Warning
+		}
+
+		if hclDiag.Subject != nil {
+			diag.Range = *hclDiag.Subject
+		}
+
+		diags = append(diags, diag)
+	}
+
+	return diags
+}
+
+// HasErrors reports whether the list contains diagnostics with error severity
+func (ds Diagnostics) HasErrors() bool {
+	for _, d := range ds {
+		if d.Severity == Error {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Sort sorts the diagnostics by file and position
+func (ds Diagnostics) Sort() {
+	sort.SliceStable(ds, func(i, j int) bool {
+		a, b := ds[i].Range, ds[j].Range
+
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+
+		if a.Start.Byte != b.Start.Byte {
+			return a.Start.Byte < b.Start.Byte
+		}
+
+		return ds[i].Message() < ds[j].Message()
+	})
+}
+
+// FromError returns the diagnostics in the chain of err
+func FromError(err error) (Diagnostics, bool) {
+	var diags Diagnostics
+	if errors.As(err, &diags) {
+		return diags, true
+	}
+
+	var diag Diagnostic
+	if errors.As(err, &diag) {
+		return Diagnostics{diag}, true
+	}
+
+	return nil, false
+}
+
+func (ds Diagnostics) Error() string {
+	msgs := make([]string, len(ds))
+	for i, d := range ds {
+		msgs[i] = d.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,107 @@
+package diagnostics_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+)
+
+func TestFromError(t *testing.T) {
+	diag := diagnostics.New(diagnostics.CodeSchema, testRange(2, 3, 2, 8), "Invalid block", "block is not valid")
+
+	t.Run("Diagnostics", func(t *testing.T) {
+		err := fmt.Errorf("parsing section: %w", diagnostics.Diagnostics{diag})
+
+		diags, ok := diagnostics.FromError(err)
+		if !ok {
+			t.Fatal("expected error to have diagnostics")
+		}
+
+		assert.EqualInts(t, 1, len(diags))
+		assert.EqualStrings(t, "doc.tfdoc.hcl:2:3", diags[0].Pos())
+		assert.EqualStrings(t, "parsing section: doc.tfdoc.hcl:2:3: Invalid block; block is not valid", err.Error())
+	})
+
+	t.Run("Diagnostic", func(t *testing.T) {
+		diags, ok := diagnostics.FromError(fmt.Errorf("parsing section: %w", diag))
+		if !ok {
+			t.Fatal("expected error to have diagnostics")
+		}
+
+		assert.EqualInts(t, 1, len(diags))
+	})
+
+	t.Run("PlainError", func(t *testing.T) {
+		_, ok := diagnostics.FromError(errors.New("plain"))
+		if ok {
+			t.Error("expected plain error to have no diagnostics")
+		}
+	})
+}
+
+func TestFromHCL(t *testing.T) {
+	rng := testRange(1, 1, 1, 4)
+
+	diags := diagnostics.FromHCL(diagnostics.CodeSyntax, hcl.Diagnostics{
+		{Severity: hcl.DiagError, Summary: "Invalid expression", Detail: "Expected the start of an expression.", Subject: &rng},
+		{Severity: hcl.DiagWarning, Summary: "Deprecated"},
+	})
+
+	want := diagnostics.Diagnostics{
+		{Severity: diagnostics.Error, Code: diagnostics.CodeSyntax, Summary: "Invalid expression", Detail: "Expected the start of an expression.", Range: rng},
+		{Severity: diagnostics.Warning, Code: diagnostics.CodeSyntax, Summary: "Deprecated"},
+	}
+
+	if diff := cmp.Diff(want, diags); diff != "" {
+		t.Errorf("Result is not expected (-want +got):\n%s", diff)
+	}
+
+	if !diags.HasErrors() {
+		t.Error("expected diagnostics to have errors")
+	}
+}
+
+func TestWriter(t *testing.T) {
+	src := []byte("section {\n  variable \"beer\" {\n\ttype = string\n  }\n}\n")
+
+	diags := diagnostics.Diagnostics{
+		diagnostics.New("missing-definition", testRange(2, 3, 2, 18), "Unknown variable documented", `"beer" is not defined in any .tf files`),
+		diagnostics.New(diagnostics.CodeInvalidType, testRange(3, 9, 3, 15), "Invalid type", ""),
+		{Severity: diagnostics.Warning, Summary: "No position"},
+	}
+
+	buf := new(bytes.Buffer)
+
+	w := diagnostics.NewWriter(buf)
+	w.AddSource("doc.tfdoc.hcl", src)
+
+	err := w.Write(diags)
+	assert.NoError(t, err)
+
+	want := `doc.tfdoc.hcl:2:3: error: Unknown variable documented: "beer" is not defined in any .tf files [missing-definition]
+  2 |   variable "beer" {
+    |   ^^^^^^^^^^^^^^^
+doc.tfdoc.hcl:3:9: error: Invalid type [invalid-type]
+  3 | 	type = string
+    | 	       ^^^^^^
+warning: No position
+`
+
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("Result is not expected (-want +got):\n%s", diff)
+	}
+}
+
+func testRange(startLine, startCol, endLine, endCol int) hcl.Range {
+	return hcl.Range{
+		Filename: "doc.tfdoc.hcl",
+		Start:    hcl.Pos{Line: startLine, Column: startCol},
+		End:      hcl.Pos{Line: endLine, Column: endCol},
+	}
+}
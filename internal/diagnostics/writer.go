@@ -0,0 +1,131 @@
+package diagnostics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"unicode/utf8"
+)
+
+// Writer writes diagnostics in a human readable form followed by a snippet
+// of the source line they refer to, e.g.:
+//
+//	README.tfdoc.hcl:12:3: error: Unknown variable documented: "beer" is not defined in any .tf files [missing-definition]
+//	  12 |   variable "beer" {
+//	     |   ^^^^^^^^^^^^^^^
+type Writer struct {
+	w       io.Writer
+	sources map[string][]byte
+}
+
+// NewWriter creates a writer which reads the sources of the diagnostics from the filesystem.
+// Sources which can not be read from the filesystem can be provided with AddSource.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w, sources: map[string][]byte{}}
+}
+
+// AddSource sets the source of filename
+func (wr *Writer) AddSource(filename string, src []byte) {
+	wr.sources[filename] = src
+}
+
+// Write writes all diagnostics
+func (wr *Writer) Write(diags Diagnostics) error {
+	for _, d := range diags {
+		if err := wr.WriteDiagnostic(d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteDiagnostic writes a single diagnostic
+func (wr *Writer) WriteDiagnostic(d Diagnostic) error {
+	var buf strings.Builder
+
+	if pos := d.Pos(); pos != "" {
+		buf.WriteString(pos + ": ")
+	}
+
+	fmt.Fprintf(&buf, "%s: %s", d.Severity, d.Message())
+
+	if d.Code != "" {
+		fmt.Fprintf(&buf, " [%s]", d.Code)
+	}
+
+	buf.WriteString("\n")
+
+	if d.HasPos() {
+		buf.WriteString(wr.snippet(d))
+	}
+
+	_, err := io.WriteString(wr.w, buf.String())
+
+	return err
+}
+
+func (wr *Writer) snippet(d Diagnostic) string {
+	src, ok := wr.source(d.Range.Filename)
+	if !ok {
+		return ""
+	}
+
+	lines := bytes.Split(src, []byte("\n"))
+	if d.Range.Start.Line > len(lines) {
+		return ""
+	}
+
+	line := strings.TrimRight(string(lines[d.Range.Start.Line-1]), "\r")
+	lineLen := utf8.RuneCountInString(line)
+
+	start := d.Range.Start.Column - 1
+	if start > lineLen {
+		start = lineLen
+	}
+
+	end := lineLen
+	if d.Range.End.Line == d.Range.Start.Line && d.Range.End.Column-1 < end {
+		end = d.Range.End.Column - 1
+	}
+
+	if end <= start {
+		end = start + 1
+	}
+
+	// keep the tabs of the source line so the marker is aligned with it
+	var prefix strings.Builder
+	for i, r := range []rune(line) {
+		if i >= start {
+			break
+		}
+
+		if r == '\t' {
+			prefix.WriteRune('\t')
+		} else {
+			prefix.WriteRune(' ')
+		}
+	}
+
+	lineNumber := fmt.Sprintf("%d", d.Range.Start.Line)
+	gutter := strings.Repeat(" ", len(lineNumber))
+
+	return fmt.Sprintf("  %s | %s\n  %s | %s%s\n", lineNumber, line, gutter, prefix.String(), strings.Repeat("^", end-start))
+}
+
+func (wr *Writer) source(filename string) ([]byte, bool) {
+	if src, ok := wr.sources[filename]; ok {
+		return src, src != nil
+	}
+
+	src, err := ioutil.ReadFile(filename)
+	if err != nil {
+		src = nil
+	}
+
+	wr.sources[filename] = src
+
+	return src, src != nil
+}
@@ -0,0 +1,285 @@
+package docsync
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/parsers/docparser"
+	"github.com/mineiros-io/terradoc/internal/renderers/tfdoc"
+	"github.com/mineiros-io/terradoc/internal/scaffold"
+	"github.com/mineiros-io/terradoc/internal/validators"
+	"github.com/mineiros-io/terradoc/internal/validators/outputsvalidator"
+	"github.com/mineiros-io/terradoc/internal/validators/varsvalidator"
+)
+
+const staleMarker = "terradoc:stale"
+
+// Options configures how a document is synchronized
+type Options struct {
+	// VariablesSection is the title of the section where stubs for undocumented variables are appended to.
+	// Defaults to the section of the last documented variable.
+	VariablesSection string
+	// OutputsSection is the title of the section where stubs for undocumented outputs are appended to.
+	// Defaults to the section of the last documented output.
+	OutputsSection string
+	// Prune removes blocks of documented variables and outputs that are not defined anymore
+	// instead of marking them as stale.
+	Prune bool
+	// Variables enables the synchronization of variables
+	Variables bool
+	// Outputs enables the synchronization of outputs
+	Outputs bool
+}
+
+// Change describes a single modification made to the document
+type Change struct {
+	Type    string
+	Name    string
+	Message string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s %q: %s", c.Type, c.Name, c.Message)
+}
+
+// Sync updates the document in src so it is synchronized with the given definitions.
+// Comments and hand-written content of the document are kept as they are.
+func Sync(src []byte, filename string, definitions entities.ValidationContents, opts Options) ([]byte, []Change, error) {
+	doc, err := docparser.Parse(bytes.NewReader(src), filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, diags := hclwrite.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, nil, fmt.Errorf("parsing HCL: %w", diagnostics.FromHCL(diagnostics.CodeSyntax, diags))
+	}
+
+	idx := indexDocument(doc, f, filename)
+
+	s := syncer{index: idx, prune: opts.Prune}
+
+	if opts.Variables {
+		summary := varsvalidator.Validate(doc, definitions)
+
+		err := s.syncVariables(summary, definitions.Variables, opts.VariablesSection)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if opts.Outputs {
+		summary := outputsvalidator.Validate(doc, definitions)
+
+		err := s.syncOutputs(summary, definitions.Outputs, opts.OutputsSection)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	outSrc := f.Bytes()
+	if s.pruned {
+		outSrc = removeBlankLines(outSrc)
+	}
+
+	return hclwrite.Format(outSrc), s.changes, nil
+}
+
+type syncer struct {
+	index   *documentIndex
+	prune   bool
+	pruned  bool
+	changes []Change
+}
+
+func (s *syncer) syncVariables(summary validators.Summary, defined entities.VariableCollection, sectionTitle string) error {
+	// keep the order in which variables are defined in the .tf files
+	var missing []entities.Variable
+	for _, variable := range defined {
+		if containsString(summary.MissingDocumentation, variable.Name) {
+			missing = append(missing, variable)
+		}
+	}
+
+	if len(missing) > 0 {
+		section, err := s.index.section(sectionTitle, s.index.lastVariableSection)
+		if err != nil {
+			return err
+		}
+
+		for _, variable := range missing {
+			section.body.AppendNewline()
+			section.body.AppendBlock(tfdoc.VariableBlock(scaffold.Variable(variable)))
+
+			s.addChange(varsvalidator.CheckType, variable.Name, fmt.Sprintf("added to section %q", section.title))
+		}
+	}
+
+	sortSummary(summary)
+
+	for _, tMismatch := range summary.TypeMismatch {
+		definedVar, _ := defined.VarByName(tMismatch.Name)
+		blk, ok := s.index.variables[tMismatch.Name]
+		if !ok {
+			s.notSynced(varsvalidator.CheckType, tMismatch.Name, s.index.includedVariables[tMismatch.Name],
+				fmt.Sprintf("type %q is defined as %q", tMismatch.DocumentedType, tMismatch.DefinedType))
+
+			continue
+		}
+
+		blk.Body().RemoveAttribute("readme_type")
+		blk.Body().SetAttributeRaw("type", tfdoc.TypeTokens(scaffold.Variable(definedVar).Type))
+
+		s.addChange(varsvalidator.CheckType, tMismatch.Name, fmt.Sprintf("type changed from %q to %q", tMismatch.DocumentedType, tMismatch.DefinedType))
+	}
+
+	for _, name := range summary.MissingDefinition {
+		blk, ok := s.index.variables[name]
+		if !ok {
+			s.notSynced(varsvalidator.CheckType, name, s.index.includedVariables[name], "it is not defined in any .tf files")
+
+			continue
+		}
+
+		s.removeStale(varsvalidator.CheckType, name, blk)
+	}
+
+	return nil
+}
+
+func (s *syncer) syncOutputs(summary validators.Summary, defined entities.OutputCollection, sectionTitle string) error {
+	var missing []entities.Output
+	for _, output := range defined {
+		if containsString(summary.MissingDocumentation, output.Name) {
+			missing = append(missing, output)
+		}
+	}
+
+	if len(missing) > 0 {
+		section, err := s.index.section(sectionTitle, s.index.lastOutputSection)
+		if err != nil {
+			return err
+		}
+
+		for _, output := range missing {
+			section.body.AppendNewline()
+			section.body.AppendBlock(tfdoc.OutputBlock(scaffold.Output(output)))
+
+			s.addChange(outputsvalidator.CheckType, output.Name, fmt.Sprintf("added to section %q", section.title))
+		}
+	}
+
+	sortSummary(summary)
+
+	for _, name := range summary.MissingDefinition {
+		blk, ok := s.index.outputs[name]
+		if !ok {
+			s.notSynced(outputsvalidator.CheckType, name, s.index.includedOutputs[name], "it is not defined in any .tf files")
+
+			continue
+		}
+
+		s.removeStale(outputsvalidator.CheckType, name, blk)
+	}
+
+	return nil
+}
+
+func (s *syncer) removeStale(checkType, name string, blk *blockRef) {
+	if s.prune {
+		blk.parent.RemoveBlock(blk.Block)
+		s.pruned = true
+
+		s.addChange(checkType, name, "removed as it is not defined in any .tf files")
+
+		return
+	}
+
+	body := blk.Body()
+	tokens := body.BuildTokens(nil)
+
+	if bytes.Contains(tokens.Bytes(), []byte(staleMarker)) {
+		return
+	}
+
+	comment := &hclwrite.Token{
+		Type:  hclsyntax.TokenComment,
+		Bytes: []byte(fmt.Sprintf("# %s: %q is not defined in any .tf files\n", staleMarker, name)),
+	}
+
+	// the first token of a block body is the newline after its opening brace
+	var marked hclwrite.Tokens
+	if len(tokens) > 0 && tokens[0].Type == hclsyntax.TokenNewline {
+		marked = append(hclwrite.Tokens{tokens[0], comment}, tokens[1:]...)
+	} else {
+		marked = append(hclwrite.Tokens{comment}, tokens...)
+	}
+
+	body.Clear()
+	body.AppendUnstructuredTokens(marked)
+
+	s.addChange(checkType, name, "marked as stale as it is not defined in any .tf files")
+}
+
+// notSynced reports a change that is needed in the included file documenting name as included
+// files are not edited
+func (s *syncer) notSynced(checkType, name, filename, reason string) {
+	s.addChange(checkType, name, fmt.Sprintf("not synced as it is documented in included file %q: %s", filename, reason))
+}
+
+func (s *syncer) addChange(checkType, name, msg string) {
+	s.changes = append(s.changes, Change{Type: checkType, Name: name, Message: msg})
+}
+
+// removeBlankLines removes the blank lines left behind by removed blocks, i.e. blank lines
+// before a closing brace and consecutive blank lines
+func removeBlankLines(src []byte) []byte {
+	tokens, diags := hclsyntax.LexConfig(src, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return src
+	}
+
+	var result []byte
+
+	last := 0
+
+	for i := 0; i+2 < len(tokens); i++ {
+		if tokens[i].Type != hclsyntax.TokenNewline || tokens[i+1].Type != hclsyntax.TokenNewline {
+			continue
+		}
+
+		if next := tokens[i+2].Type; next == hclsyntax.TokenCBrace || next == hclsyntax.TokenNewline {
+			blankLine := tokens[i+1].Range
+
+			result = append(result, src[last:blankLine.Start.Byte]...)
+			last = blankLine.End.Byte
+		}
+	}
+
+	return append(result, src[last:]...)
+}
+
+// sortSummary sorts the summary results so changes are always applied in the same order
+func sortSummary(summary validators.Summary) {
+	sort.Strings(summary.MissingDefinition)
+	sort.Strings(summary.MissingDocumentation)
+	sort.Slice(summary.TypeMismatch, func(i, j int) bool {
+		return summary.TypeMismatch[i].Name < summary.TypeMismatch[j].Name
+	})
+}
+
+func containsString(list []string, str string) bool {
+	for _, s := range list {
+		if s == str {
+			return true
+		}
+	}
+
+	return false
+}
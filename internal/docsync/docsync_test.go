@@ -0,0 +1,127 @@
+package docsync_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/internal/docsync"
+	"github.com/mineiros-io/terradoc/internal/parsers/validationparser"
+	"github.com/mineiros-io/terradoc/test"
+)
+
+func TestSync(t *testing.T) {
+	for _, tt := range []struct {
+		desc        string
+		opts        docsync.Options
+		want        string
+		wantChanges []string
+	}{
+		{
+			desc: "when stale blocks are marked",
+			opts: docsync.Options{Variables: true, Outputs: true},
+			want: "sync/expected.tfdoc.hcl",
+			wantChanges: []string{
+				`variable "tags": added to section "Arguments"`,
+				`variable "count": type changed from "string" to "number"`,
+				`variable "removed": marked as stale as it is not defined in any .tf files`,
+				`output "arn": added to section "Outputs"`,
+			},
+		},
+		{
+			desc: "when stale blocks are pruned",
+			opts: docsync.Options{Variables: true, Outputs: true, Prune: true},
+			want: "sync/expected-pruned.tfdoc.hcl",
+			wantChanges: []string{
+				`variable "tags": added to section "Arguments"`,
+				`variable "count": type changed from "string" to "number"`,
+				`variable "removed": removed as it is not defined in any .tf files`,
+				`output "arn": added to section "Outputs"`,
+			},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			src := test.ReadFixture(t, "sync/input.tfdoc.hcl")
+			definitions, err := validationparser.Parse(bytes.NewReader(test.ReadFixture(t, "sync/main.tf")), "main.tf", validationparser.Options{Variables: true, Outputs: true})
+			assert.NoError(t, err)
+
+			got, changes, err := docsync.Sync(src, "input.tfdoc.hcl", definitions, tt.opts)
+			assert.NoError(t, err)
+
+			want := test.ReadFixture(t, tt.want)
+			if diff := cmp.Diff(string(want), string(got)); diff != "" {
+				t.Errorf("Result is not expected (-want +got):\n%s", diff)
+			}
+
+			var gotChanges []string
+			for _, change := range changes {
+				gotChanges = append(gotChanges, change.String())
+			}
+
+			if diff := cmp.Diff(tt.wantChanges, gotChanges); diff != "" {
+				t.Errorf("Changes are not expected (-want +got):\n%s", diff)
+			}
+
+			// synchronizing an already synchronized document must not change it
+			again, changes, err := docsync.Sync(got, "input.tfdoc.hcl", definitions, tt.opts)
+			assert.NoError(t, err)
+			assert.EqualInts(t, 0, len(changes))
+
+			if diff := cmp.Diff(string(got), string(again)); diff != "" {
+				t.Errorf("Sync is not idempotent (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("when section does not exist", func(t *testing.T) {
+		src := test.ReadFixture(t, "sync/input.tfdoc.hcl")
+		definitions, err := validationparser.Parse(bytes.NewReader(test.ReadFixture(t, "sync/main.tf")), "main.tf", validationparser.Options{Variables: true, Outputs: true})
+		assert.NoError(t, err)
+
+		_, _, err = docsync.Sync(src, "input.tfdoc.hcl", definitions, docsync.Options{Variables: true, VariablesSection: "Nope"})
+		assert.Error(t, err)
+	})
+}
+
+func TestSyncIncludes(t *testing.T) {
+	dir := t.TempDir()
+
+	included := "section {\n  title = \"Inputs\"\n\n  variable \"name\" {\n    type = number\n  }\n\n  variable \"removed\" {\n    type = string\n  }\n}\n"
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "inputs.tfdoc.hcl"), []byte(included), 0644))
+
+	src := []byte("section {\n  title = \"Module\"\n}\n\ninclude \"inputs.tfdoc.hcl\" {}\n")
+
+	tf := "variable \"name\" {\n  type = string\n}\n\nvariable \"tags\" {\n  type = map(string)\n}\n"
+	definitions, err := validationparser.Parse(strings.NewReader(tf), "main.tf", validationparser.Options{Variables: true})
+	assert.NoError(t, err)
+
+	got, changes, err := docsync.Sync(src, filepath.Join(dir, "README.tfdoc.hcl"), definitions, docsync.Options{Variables: true, Prune: true})
+	assert.NoError(t, err)
+
+	// blocks of included files are left untouched
+	want := "section {\n  title = \"Module\"\n\n  variable \"tags\" {\n    type        = map(string)\n    required    = true\n    description = \"\"\n  }\n}\n\ninclude \"inputs.tfdoc.hcl\" {}\n"
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("Result is not expected (-want +got):\n%s", diff)
+	}
+
+	inputs := filepath.Join(dir, "inputs.tfdoc.hcl")
+	wantChanges := []string{
+		`variable "tags": added to section "Module"`,
+		fmt.Sprintf(`variable "name": not synced as it is documented in included file %q: type "number" is defined as "string"`, inputs),
+		fmt.Sprintf(`variable "removed": not synced as it is documented in included file %q: it is not defined in any .tf files`, inputs),
+	}
+
+	var gotChanges []string
+	for _, change := range changes {
+		gotChanges = append(gotChanges, change.String())
+	}
+
+	if diff := cmp.Diff(wantChanges, gotChanges); diff != "" {
+		t.Errorf("Changes are not expected (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,132 @@
+package docsync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/mineiros-io/terradoc/internal/entities"
+)
+
+// blockRef is a block of the document together with the body containing it
+type blockRef struct {
+	*hclwrite.Block
+	parent *hclwrite.Body
+}
+
+type sectionRef struct {
+	title string
+	body  *hclwrite.Body
+}
+
+// documentIndex maps the parsed document entities to the blocks of the editable document
+type documentIndex struct {
+	sections  []sectionRef
+	variables map[string]*blockRef
+	outputs   map[string]*blockRef
+
+	// includedVariables and includedOutputs map the names documented in included files to the
+	// file documenting them
+	includedVariables map[string]string
+	includedOutputs   map[string]string
+
+	lastVariableSection *sectionRef
+	lastOutputSection   *sectionRef
+}
+
+// indexDocument indexes the sections of the document parsed from filename. Sections of included
+// documents are not indexed as they can not be edited, only the files documenting their variables
+// and outputs are.
+func indexDocument(doc entities.Doc, f *hclwrite.File, filename string) *documentIndex {
+	idx := &documentIndex{
+		variables:         map[string]*blockRef{},
+		outputs:           map[string]*blockRef{},
+		includedVariables: map[string]string{},
+		includedOutputs:   map[string]string{},
+	}
+
+	for _, variable := range doc.AllVariables() {
+		if variable.DefRange.Filename != filename {
+			idx.includedVariables[variable.Name] = variable.DefRange.Filename
+		}
+	}
+
+	for _, output := range doc.AllOutputs() {
+		if output.DefRange.Filename != filename {
+			idx.includedOutputs[output.Name] = output.DefRange.Filename
+		}
+	}
+
+	var sections []entities.Section
+	for _, section := range doc.Sections {
+		if section.DefRange.Filename == filename {
+			sections = append(sections, section)
+		}
+	}
+
+	idx.indexSections(sections, f.Body())
+
+	return idx
+}
+
+// indexSections walks the parsed sections and the section blocks of body in parallel.
+// Both are in the same order as they were parsed from the same source.
+func (idx *documentIndex) indexSections(sections []entities.Section, body *hclwrite.Body) {
+	blocks := blocksOfType(body, "section")
+
+	for i, section := range sections {
+		if i >= len(blocks) {
+			return
+		}
+
+		sectionBody := blocks[i].Body()
+		ref := sectionRef{title: section.Title, body: sectionBody}
+		idx.sections = append(idx.sections, ref)
+
+		for _, blk := range blocksOfType(sectionBody, "variable") {
+			idx.variables[blk.Labels()[0]] = &blockRef{Block: blk, parent: sectionBody}
+			idx.lastVariableSection = &ref
+		}
+
+		for _, blk := range blocksOfType(sectionBody, "output") {
+			idx.outputs[blk.Labels()[0]] = &blockRef{Block: blk, parent: sectionBody}
+			idx.lastOutputSection = &ref
+		}
+
+		idx.indexSections(section.SubSections, sectionBody)
+	}
+}
+
+// section returns the section with the given title. If title is empty the fallback section is
+// returned and if there is no fallback section the first section of the document is returned.
+func (idx *documentIndex) section(title string, fallback *sectionRef) (*sectionRef, error) {
+	if title == "" {
+		if fallback != nil {
+			return fallback, nil
+		}
+
+		if len(idx.sections) == 0 {
+			return nil, errors.New("document has no sections")
+		}
+
+		return &idx.sections[0], nil
+	}
+
+	for i := range idx.sections {
+		if idx.sections[i].title == title {
+			return &idx.sections[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("section %q not found", title)
+}
+
+func blocksOfType(body *hclwrite.Body, blockType string) (result []*hclwrite.Block) {
+	for _, blk := range body.Blocks() {
+		if blk.Type() == blockType && (blockType == "section" || len(blk.Labels()) == 1) {
+			result = append(result, blk)
+		}
+	}
+
+	return result
+}
@@ -2,6 +2,8 @@ package entities
 
 import (
 	"encoding/json"
+
+	"github.com/hashicorp/hcl/v2"
 )
 
 // Attribute represents an `attribute` block from the input file
@@ -24,4 +26,6 @@ type Attribute struct {
 	Attributes []Attribute `json:"attributes,omitempty"`
 	// Level is the nesting level of this attribute
 	Level int `json:"-"`
+	// DefRange is the source range of the `attribute` block header
+	DefRange hcl.Range `json:"-"`
 }
@@ -0,0 +1,13 @@
+package entities
+
+import "github.com/hashicorp/hcl/v2"
+
+// Constraint represents a `validation` block of a variable, which restricts the values the variable accepts
+type Constraint struct {
+	// Condition is the source of the condition expression, e.g. `length(var.name) > 3`
+	Condition string `json:"condition"`
+	// ErrorMessage is the message shown by Terraform when the condition does not hold
+	ErrorMessage string `json:"error_message,omitempty"`
+	// DefRange is the source range of the `validation` block header
+	DefRange hcl.Range `json:"-"`
+}
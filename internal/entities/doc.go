@@ -8,6 +8,8 @@ type Doc struct {
 	Sections []Section `json:"sections"`
 	// References is a collection of references defined in the source file.
 	References []Reference `json:"references"`
+	// ReferenceUses are the references used in the source file and the files it includes
+	ReferenceUses []ReferenceUse `json:"-"`
 }
 
 // Header represents the `header` block on the parsed source file
@@ -40,3 +42,12 @@ func (d Doc) AllOutputs() (result []Output) {
 
 	return result
 }
+
+// AllRequirements returns the requirements listed in the sections of the document
+func (d Doc) AllRequirements() (result []Requirements) {
+	for _, s := range d.Sections {
+		result = append(result, s.AllRequirements()...)
+	}
+
+	return result
+}
@@ -1,5 +1,7 @@
 package entities
 
+import "github.com/hashicorp/hcl/v2"
+
 // Output represents an `output` block from the input file.
 type Output struct {
 	// Name as defined in the `output` block label.
@@ -8,6 +10,12 @@ type Output struct {
 	Type Type `json:"type_definition"`
 	// Description is an optional output description
 	Description string `json:"description,omitempty"`
+	// Sensitive specifies if the value of the output is hidden in Terraform's output
+	Sensitive bool `json:"sensitive,omitempty"`
+	// DependsOn specifies if the output declares explicit dependencies with `depends_on`
+	DependsOn bool `json:"depends_on,omitempty"`
+	// DefRange is the source range of the `output` block header
+	DefRange hcl.Range `json:"-"`
 }
 
 type OutputCollection []Output
@@ -1,7 +1,22 @@
 package entities
 
+import "github.com/hashicorp/hcl/v2"
+
 // Reference represents a `ref` block on the source document
 type Reference struct {
 	Name  string `json:"name"`  // Name is the identifier for the reference
 	Value string `json:"value"` // Value is the value that the reference holds
+	// DefRange is the range of the `ref` block header in the source file
+	DefRange hcl.Range `json:"-"`
+}
+
+// ReferenceUse is a reference used in the content, a description or a readme example, either as
+// a markdown reference-style link, e.g. `[text][name]`, or as an interpolation, e.g. `${ref.name}`
+type ReferenceUse struct {
+	Name string
+	// Shortcut reports whether the reference is used as `[name]`, which is only a link if the
+	// reference is defined
+	Shortcut bool
+	// Range is the range of the link or interpolation in the source file
+	Range hcl.Range
 }
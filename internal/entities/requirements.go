@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+const (
+	// DefaultRegistry is the host of provider and module sources without one
+	DefaultRegistry = "registry.terraform.io"
+	// DefaultProviderNamespace is the namespace of providers required without a source
+	DefaultProviderNamespace = "hashicorp"
+)
+
+// Requirements are the versions of Terraform and of the providers required by a module, either
+// declared in the `terraform` blocks of .tf files or documented in a `requirements` block
+type Requirements struct {
+	// TerraformVersion is the `required_version` constraint
+	TerraformVersion string `json:"terraform,omitempty"`
+	// Providers are the `required_providers`
+	Providers []ProviderRequirement `json:"providers,omitempty"`
+	// FromCode reports whether a documented `requirements` block was empty and filled with the
+	// requirements declared in the .tf files
+	FromCode bool `json:"-"`
+	// DefRange is the source range of the `requirements` or `terraform` block header
+	DefRange hcl.Range `json:"-"`
+}
+
+// ProviderRequirement is a provider required by a module
+type ProviderRequirement struct {
+	Name    string `json:"name"`
+	Source  string `json:"source,omitempty"`
+	Version string `json:"version,omitempty"`
+	// DefRange is the source range of the documented `provider` block header or of the declared
+	// `required_providers` attribute
+	DefRange hcl.Range `json:"-"`
+}
+
+// RegistrySource returns the lowercase source of the provider without the default registry, e.g.
+// `hashicorp/aws`. Providers required without a source are in the default namespace.
+func (p ProviderRequirement) RegistrySource() string {
+	source := p.Source
+	if source == "" {
+		source = DefaultProviderNamespace + "/" + p.Name
+	}
+
+	source = strings.ToLower(strings.TrimSpace(source))
+
+	return strings.TrimPrefix(source, DefaultRegistry+"/")
+}
+
+// IsEmpty reports whether no versions are required
+func (r Requirements) IsEmpty() bool {
+	return r.TerraformVersion == "" && len(r.Providers) == 0
+}
+
+// Provider returns the requirement of the provider with the given local name
+func (r Requirements) Provider(name string) (ProviderRequirement, bool) {
+	for _, p := range r.Providers {
+		if p.Name == name {
+			return p, true
+		}
+	}
+
+	return ProviderRequirement{}, false
+}
+
+// Merge returns the requirements of both r and other, e.g. of two `terraform` blocks. Terraform
+// versions must both be satisfied, so they are joined into one constraint.
+func (r Requirements) Merge(other Requirements) Requirements {
+	result := r
+
+	if result.DefRange.Filename == "" {
+		result.DefRange = other.DefRange
+	}
+
+	switch {
+	case result.TerraformVersion == "":
+		result.TerraformVersion = other.TerraformVersion
+	case other.TerraformVersion != "":
+		result.TerraformVersion = strings.Join([]string{result.TerraformVersion, other.TerraformVersion}, ", ")
+	}
+
+	result.Providers = append(append([]ProviderRequirement{}, r.Providers...), other.Providers...)
+
+	return result
+}
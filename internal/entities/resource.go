@@ -0,0 +1,43 @@
+package entities
+
+import "github.com/hashicorp/hcl/v2"
+
+// Kinds of the blocks inventoried as resources of a module
+const (
+	ResourceKind   = "resource"
+	DataSourceKind = "data"
+	ModuleKind     = "module"
+)
+
+// Resource is a `resource`, `data` or `module` block declared in the .tf files of a module
+type Resource struct {
+	// Kind is the type of the block, one of ResourceKind, DataSourceKind or ModuleKind
+	Kind string `json:"kind"`
+	// Type is the resource type, e.g. `aws_s3_bucket`. It is empty for modules.
+	Type string `json:"type,omitempty"`
+	Name string `json:"name"`
+	// Provider is the local name of the provider of a resource or data source
+	Provider string `json:"provider,omitempty"`
+	// Source is the source of a module
+	Source string `json:"source,omitempty"`
+	// Count and ForEach report whether the block sets the `count` or `for_each` meta-argument
+	Count   bool `json:"count,omitempty"`
+	ForEach bool `json:"for_each,omitempty"`
+	// URL is the documentation of the resource type or of the module, if it is known
+	URL string `json:"url,omitempty"`
+	// DefRange is the source range of the block header
+	DefRange hcl.Range `json:"-"`
+}
+
+// Address returns the address of the block as used in Terraform expressions, e.g.
+// `data.aws_iam_policy_document.this`
+func (r Resource) Address() string {
+	switch r.Kind {
+	case DataSourceKind:
+		return DataSourceKind + "." + r.Type + "." + r.Name
+	case ModuleKind:
+		return ModuleKind + "." + r.Name
+	}
+
+	return r.Type + "." + r.Name
+}
@@ -1,5 +1,7 @@
 package entities
 
+import "github.com/hashicorp/hcl/v2"
+
 // Section represents a `section` block from the input file.
 type Section struct {
 	// Title is an optional title for the section.
@@ -10,12 +12,22 @@ type Section struct {
 	Variables []Variable `json:"variables,omitempty"`
 	// Ouputs is a collection of output definitions contained in the section block.
 	Outputs []Output `json:"outputs,omitempty"`
+	// Requirements are the versions of Terraform and the providers listed in the section. It is nil
+	// if the section has no `requirements` block.
+	Requirements *Requirements `json:"requirements,omitempty"`
+	// Generated names the content generated from the .tf files for the section, e.g. "resources"
+	Generated string `json:"generated,omitempty"`
+	// Resources are the resources of the module listed by a section generating "resources"
+	Resources []Resource `json:"resources,omitempty"`
 	// SubSections is a collection of nested sections contained in the section block.
 	SubSections []Section `json:"subsections,omitempty"`
 	// Level is the nesting of this section
 	Level int `json:"-"`
 	// TOC is a flag for generating table of contents for nested sections
 	TOC bool `json:"-"`
+	// DefRange is the source range of the `section` block header. Sections of included files
+	// refer to the included file.
+	DefRange hcl.Range `json:"-"`
 }
 
 func (s Section) AllVariables() (result VariableCollection) {
@@ -41,3 +53,16 @@ func (s Section) AllOutputs() (result OutputCollection) {
 
 	return result
 }
+
+// AllRequirements returns the requirements listed in the section and its subsections
+func (s Section) AllRequirements() (result []Requirements) {
+	if s.Requirements != nil {
+		result = append(result, *s.Requirements)
+	}
+
+	for _, s := range s.SubSections {
+		result = append(result, s.AllRequirements()...)
+	}
+
+	return result
+}
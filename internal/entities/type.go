@@ -1,32 +1,135 @@
 package entities
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/mineiros-io/terradoc/internal/types"
 )
 
-// Type represents a variable or attribute type with its readme and Terraform type data
+// Type represents a variable or attribute type with its readme and Terraform type data.
+// Types are trees, e.g. `map(list(object({ name = string })))` is a map type nesting a list
+// type nesting an object type with a `name` field.
 type Type struct {
 	// TFType is the specific Terraform type definition for this type
 	TFType types.TerraformType `json:"type"`
 	// Label is an optional label for the TerraformType
 	Label string `json:"label"`
-	// Nested is an optional nested type definition
+	// Nested is the element type of list, set and map types
 	Nested *Type `json:"nested,omitempty"`
+	// Fields are the attribute types of object types declared as `object({...})`
+	Fields map[string]Type `json:"fields,omitempty"`
+	// Elements are the element types of tuple types declared as `tuple([...])`
+	Elements []Type `json:"elements,omitempty"`
+	// Optional is set for object attribute types declared as `optional(type, default)`
+	Optional bool `json:"optional,omitempty"`
+	// Default is the optional default value of optional attribute types. Must be a valid JSON value.
+	Default json.RawMessage `json:"default,omitempty"`
 }
 
+// AsString returns the type as a Terraform type constraint, where labeled objects are written
+// as `object(label)`
 func (t Type) AsString() string {
+	return t.modifier(t.constraint(Type.AsString))
+}
+
+func (t Type) HasNestedType() bool {
+	return t.Nested != nil
+}
+
+// Element returns the innermost element type of nested list, set and map types, e.g. the object
+// type of `map(list(object(rule)))`, and the type itself for other types
+func (t Type) Element() Type {
+	for t.HasNestedType() {
+		t = *t.Nested
+	}
+
+	return t
+}
+
+// CollectionPath describes the list, set and map types nesting the element type from the
+// innermost one, e.g. "list in the map" for `map(list(object(rule)))`
+func (t Type) CollectionPath() string {
+	var path []string
+
+	for ; t.HasNestedType(); t = *t.Nested {
+		path = append([]string{t.TFType.String()}, path...)
+	}
+
+	return strings.Join(path, " in the ")
+}
+
+// HasFields reports whether the type is an object type with declared attribute types
+func (t Type) HasFields() bool {
+	return len(t.Fields) > 0
+}
+
+// WithoutModifier returns the type without its `optional` modifier
+func (t Type) WithoutModifier() Type {
+	t.Optional = false
+	t.Default = nil
+
+	return t
+}
+
+// FieldNames returns the names of the object attribute types in sorted order
+func (t Type) FieldNames() []string {
+	names := make([]string, 0, len(t.Fields))
+	for name := range t.Fields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// DocString returns the type as it is written in documents, where labeled
+// nested objects are written as `list(label)` instead of `list(object(label))`
+func (t Type) DocString() string {
+	if t.HasNestedType() && t.Nested.Label != "" && t.Nested.TFType == types.TerraformObject {
+		return t.modifier(fmt.Sprintf("%s(%s)", t.TFType.String(), t.Nested.Label))
+	}
+
+	return t.modifier(t.constraint(Type.DocString))
+}
+
+// constraint writes the type using str to write the types it is composed of
+func (t Type) constraint(str func(Type) string) string {
 	switch {
 	case t.HasNestedType():
-		return fmt.Sprintf("%s(%s)", t.TFType.String(), t.Nested.AsString())
+		return fmt.Sprintf("%s(%s)", t.TFType.String(), str(*t.Nested))
 	case t.Label != "":
 		return fmt.Sprintf("%s(%s)", t.TFType.String(), t.Label)
+	case t.HasFields():
+		fields := make([]string, 0, len(t.Fields))
+		for _, name := range t.FieldNames() {
+			fields = append(fields, fmt.Sprintf("%s = %s", name, str(t.Fields[name])))
+		}
+
+		return fmt.Sprintf("%s({ %s })", t.TFType.String(), strings.Join(fields, ", "))
+	case len(t.Elements) > 0:
+		elements := make([]string, len(t.Elements))
+		for i, elem := range t.Elements {
+			elements[i] = str(elem)
+		}
+
+		return fmt.Sprintf("%s([%s])", t.TFType.String(), strings.Join(elements, ", "))
 	}
 
 	return t.TFType.String()
 }
 
-func (t Type) HasNestedType() bool {
-	return t.Nested != nil
+// modifier wraps the type constraint with its `optional` modifier
+func (t Type) modifier(constraint string) string {
+	switch {
+	case t.Optional && len(t.Default) > 0:
+		return fmt.Sprintf("optional(%s, %s)", constraint, t.Default)
+	case t.Optional:
+		return fmt.Sprintf("optional(%s)", constraint)
+	}
+
+	return constraint
 }
@@ -1,6 +1,7 @@
 package entities_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/madlambda/spells/assert"
@@ -48,6 +49,39 @@ func TestTypeAsString(t *testing.T) {
 			},
 			wantString: "object(foo)",
 		},
+		{
+			ty: entities.Type{
+				TFType: types.TerraformMap,
+				Nested: &entities.Type{
+					TFType: types.TerraformList,
+					Nested: &entities.Type{
+						TFType: types.TerraformObject,
+						Fields: map[string]entities.Type{
+							"port": {TFType: types.TerraformNumber, Optional: true, Default: json.RawMessage("80")},
+							"name": {TFType: types.TerraformString},
+						},
+					},
+				},
+			},
+			wantString: "map(list(object({ name = string, port = optional(number, 80) })))",
+		},
+		{
+			ty: entities.Type{
+				TFType: types.TerraformTuple,
+				Elements: []entities.Type{
+					{TFType: types.TerraformString},
+					{TFType: types.TerraformList, Nested: &entities.Type{TFType: types.TerraformNumber}},
+				},
+			},
+			wantString: "tuple([string, list(number)])",
+		},
+		{
+			ty: entities.Type{
+				TFType:   types.TerraformString,
+				Optional: true,
+			},
+			wantString: "optional(string)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -56,3 +90,62 @@ func TestTypeAsString(t *testing.T) {
 		})
 	}
 }
+
+func TestTypeDocString(t *testing.T) {
+	tests := []struct {
+		ty         entities.Type
+		wantString string
+	}{
+		{
+			ty: entities.Type{
+				TFType: types.TerraformList,
+				Nested: &entities.Type{
+					TFType: types.TerraformObject,
+					Label:  "beer",
+				},
+			},
+			wantString: "list(beer)",
+		},
+		{
+			ty: entities.Type{
+				TFType: types.TerraformMap,
+				Nested: &entities.Type{
+					TFType: types.TerraformList,
+					Nested: &entities.Type{
+						TFType: types.TerraformObject,
+						Label:  "beer",
+					},
+				},
+			},
+			wantString: "map(list(beer))",
+		},
+		{
+			ty: entities.Type{
+				TFType: types.TerraformMap,
+				Nested: &entities.Type{
+					TFType: types.TerraformString,
+				},
+			},
+			wantString: "map(string)",
+		},
+		{
+			ty: entities.Type{
+				TFType: types.TerraformObject,
+				Label:  "foo",
+			},
+			wantString: "object(foo)",
+		},
+		{
+			ty: entities.Type{
+				TFType: types.TerraformString,
+			},
+			wantString: "string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantString, func(t *testing.T) {
+			assert.EqualStrings(t, tt.wantString, tt.ty.DocString())
+		})
+	}
+}
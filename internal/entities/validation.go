@@ -3,4 +3,18 @@ package entities
 type ValidationContents struct {
 	Variables VariableCollection
 	Outputs   OutputCollection
+	// Requirements are the versions required by the `terraform` blocks
+	Requirements Requirements
+	// Resources are the `resource`, `data` and `module` blocks in the order they are declared
+	Resources []Resource
+}
+
+// Merge returns the contents of both c and other, e.g. of two .tf files of a module
+func (c ValidationContents) Merge(other ValidationContents) ValidationContents {
+	return ValidationContents{
+		Variables:    append(append(VariableCollection{}, c.Variables...), other.Variables...),
+		Outputs:      append(append(OutputCollection{}, c.Outputs...), other.Outputs...),
+		Requirements: c.Requirements.Merge(other.Requirements),
+		Resources:    append(append([]Resource{}, c.Resources...), other.Resources...),
+	}
 }
@@ -2,6 +2,8 @@ package entities
 
 import (
 	"encoding/json"
+
+	"github.com/hashicorp/hcl/v2"
 )
 
 type VariableCollection []Variable
@@ -28,10 +30,21 @@ type Variable struct {
 	Default json.RawMessage `json:"default,omitempty"`
 	// Required specifies if the variable is required
 	Required bool `json:"required,omitempty"`
+	// Sensitive specifies if the value of the variable is hidden in Terraform's output
+	Sensitive bool `json:"sensitive,omitempty"`
+	// Nullable specifies if the variable accepts null values. Terraform variables are nullable unless
+	// they set `nullable = false`.
+	Nullable bool `json:"nullable,omitempty"`
+	// Ephemeral specifies if the value of the variable is not persisted in the plan and state
+	Ephemeral bool `json:"ephemeral,omitempty"`
 	// ForcesRecreation specifies if a change in the variable triggers the recreation of the resource.
 	ForcesRecreation bool `json:"forces_recreation,omitempty"`
 	// ReadmeExample is an optional readme example to be used in the documentation
 	ReadmeExample string `json:"readme_example,omitempty"`
 	// Attributes is a collection attributes that make up the value of this variable.
 	Attributes []Attribute `json:"attributes,omitempty"`
+	// Constraints are the `validation` blocks of the variable
+	Constraints []Constraint `json:"constraints,omitempty"`
+	// DefRange is the source range of the `variable` block header
+	DefRange hcl.Range `json:"-"`
 }
@@ -0,0 +1,127 @@
+package lsp
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/mineiros-io/terradoc/internal/schemas/docschema"
+)
+
+// blockSchemas are the schemas of the bodies of the blocks of a .tfdoc.hcl file by block type
+var blockSchemas = map[string]func() *hcl.BodySchema{
+	"header":       docschema.HeaderSchema,
+	"badge":        docschema.BadgeSchema,
+	"references":   docschema.ReferencesSchema,
+	"ref":          docschema.RefSchema,
+	"section":      docschema.SectionSchema,
+	"variable":     docschema.VariableSchema,
+	"validation":   docschema.ValidationSchema,
+	"output":       docschema.OutputSchema,
+	"attribute":    docschema.AttributeSchema,
+	"include":      docschema.IncludeSchema,
+	"requirements": docschema.RequirementsSchema,
+	"provider":     docschema.ProviderRequirementSchema,
+}
+
+// completion returns the attributes and blocks allowed by the schema of the block at the position.
+// Attributes that are already set are left out.
+func (s *Server) completion(params TextDocumentPositionParams) (interface{}, error) {
+	path, src, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := offsetAt(src, params.Position)
+
+	// the parser recovers from most syntax errors, which are common while typing
+	f, _ := hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return []CompletionItem{}, nil
+	}
+
+	schema := docschema.RootSchema()
+
+	for _, blk := range blocksAt(body, offset) {
+		if !inBody(blk, offset) {
+			break
+		}
+
+		schemaFunc, ok := blockSchemas[blk.Type]
+		if !ok {
+			return []CompletionItem{}, nil
+		}
+
+		schema = schemaFunc()
+		body = blk.Body
+	}
+
+	items := []CompletionItem{}
+
+	for _, attrSchema := range schema.Attributes {
+		if attr, ok := body.Attributes[attrSchema.Name]; ok {
+			if offset > attr.EqualsRange.Start.Byte && offset <= attr.SrcRange.End.Byte {
+				// no names are completed in the value of an attribute
+				return []CompletionItem{}, nil
+			}
+
+			if !contains(attr.SrcRange, offset) {
+				continue
+			}
+		}
+
+		detail := "attribute"
+		if attrSchema.Required {
+			detail = "required attribute"
+		}
+
+		items = append(items, CompletionItem{
+			Label:      attrSchema.Name,
+			Kind:       completionItemKindProperty,
+			Detail:     detail,
+			InsertText: attrSchema.Name + " = ",
+		})
+	}
+
+	for _, blkSchema := range schema.Blocks {
+		items = append(items, CompletionItem{
+			Label:  blkSchema.Type,
+			Kind:   completionItemKindClass,
+			Detail: "block",
+		})
+	}
+
+	return items, nil
+}
+
+// blocksAt returns the blocks containing the offset from the outermost to the innermost block
+func blocksAt(body *hclsyntax.Body, offset int) []*hclsyntax.Block {
+	var blocks []*hclsyntax.Block
+
+	for {
+		var next *hclsyntax.Block
+
+		for _, blk := range body.Blocks {
+			if contains(blk.Range(), offset) {
+				next = blk
+				break
+			}
+		}
+
+		if next == nil {
+			return blocks
+		}
+
+		blocks = append(blocks, next)
+		body = next.Body
+	}
+}
+
+// inBody reports whether the offset is between the braces of a block
+func inBody(blk *hclsyntax.Block, offset int) bool {
+	return offset >= blk.OpenBraceRange.End.Byte && offset <= blk.CloseBraceRange.Start.Byte
+}
+
+func contains(rng hcl.Range, offset int) bool {
+	return offset >= rng.Start.Byte && offset <= rng.End.Byte
+}
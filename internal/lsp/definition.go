@@ -0,0 +1,114 @@
+package lsp
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// definedBlockTypes are the documented blocks that have a definition in the .tf files
+var definedBlockTypes = map[string]bool{
+	"variable": true,
+	"output":   true,
+}
+
+// definition is the block of a .tf file that defines a documented variable or output
+type definition struct {
+	filename string
+	src      []byte
+	block    *hclsyntax.Block
+}
+
+// hover shows the definition of the variable or output whose documentation header is at the
+// position
+func (s *Server) hover(params TextDocumentPositionParams) (interface{}, error) {
+	rng, def, err := s.findDefinition(params)
+	if err != nil || def == nil {
+		return nil, err
+	}
+
+	source := def.block.Range().SliceBytes(def.src)
+
+	return Hover{
+		Contents: MarkupContent{
+			Kind:  markupKindMarkdown,
+			Value: fmt.Sprintf("Defined in `%s`:\n\n```hcl\n%s\n```", filepath.Base(def.filename), source),
+		},
+		Range: &rng,
+	}, nil
+}
+
+// definition returns the location of the definition of the variable or output whose
+// documentation header is at the position
+func (s *Server) definition(params TextDocumentPositionParams) (interface{}, error) {
+	_, def, err := s.findDefinition(params)
+	if err != nil || def == nil {
+		return nil, err
+	}
+
+	return Location{
+		URI:   pathToURI(def.filename),
+		Range: rangeOf(def.src, def.block.DefRange()),
+	}, nil
+}
+
+// findDefinition returns the range of the documented block header at the position and the
+// definition of the block in the .tf files of the module. The definition is nil if there is no
+// such block.
+func (s *Server) findDefinition(params TextDocumentPositionParams) (Range, *definition, error) {
+	path, src, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return Range{}, nil, err
+	}
+
+	f, _ := hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return Range{}, nil, nil
+	}
+
+	offset := offsetAt(src, params.Position)
+
+	var docBlock *hclsyntax.Block
+
+	for _, blk := range blocksAt(body, offset) {
+		if definedBlockTypes[blk.Type] && len(blk.Labels) == 1 && contains(blk.DefRange(), offset) {
+			docBlock = blk
+			break
+		}
+	}
+
+	if docBlock == nil {
+		return Range{}, nil, nil
+	}
+
+	tfFiles, err := s.terraformFiles(path)
+	if err != nil {
+		return Range{}, nil, err
+	}
+
+	for _, tfFile := range tfFiles {
+		tfSrc, err := s.source(tfFile)
+		if err != nil {
+			return Range{}, nil, err
+		}
+
+		tf, _ := hclsyntax.ParseConfig(tfSrc, tfFile, hcl.InitialPos)
+
+		tfBody, ok := tf.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+
+		for _, blk := range tfBody.Blocks {
+			if blk.Type == docBlock.Type && len(blk.Labels) == 1 && blk.Labels[0] == docBlock.Labels[0] {
+				return rangeOf(src, docBlock.DefRange()), &definition{filename: tfFile, src: tfSrc, block: blk}, nil
+			}
+		}
+	}
+
+	return Range{}, nil, nil
+}
@@ -0,0 +1,340 @@
+package lsp
+
+import (
+	"bytes"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/mineiros-io/terradoc/internal/detect"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/parsers/docparser"
+	"github.com/mineiros-io/terradoc/internal/parsers/validationparser"
+	"github.com/mineiros-io/terradoc/internal/validators/outputsvalidator"
+	"github.com/mineiros-io/terradoc/internal/validators/refsvalidator"
+	"github.com/mineiros-io/terradoc/internal/validators/requirementsvalidator"
+	"github.com/mineiros-io/terradoc/internal/validators/varsvalidator"
+)
+
+// check parses and validates a document and publishes its diagnostics. Documents included by
+// another document are validated as part of the root document including them, so the root
+// document owns the diagnostics of itself, of the documents it includes and of the .tf files of
+// its module and is the only document publishing them. The diagnostics of docPath are published
+// first.
+func (s *Server) check(docPath string) error {
+	root, err := rootDocument(docPath)
+	if err != nil {
+		return s.publish(docPath, errorDiagnostics(docPath, err))
+	}
+
+	byFile := map[string]diagnostics.Diagnostics{root: nil, docPath: nil}
+
+	for _, diag := range s.diagnose(root) {
+		filename := diag.Range.Filename
+		if filename == "" {
+			filename = root
+		}
+
+		byFile[filename] = append(byFile[filename], diag)
+	}
+
+	// open included documents are also checked alone since the root document reads them from disk
+	for path := range s.docs {
+		if path == root || !isDocFile(path) {
+			continue
+		}
+
+		if included, err := rootDocument(path); err != nil || included != root {
+			continue
+		}
+
+		byFile[path] = s.diagnoseIncluded(path, byFile[path])
+	}
+
+	var related []string
+
+	for path := range byFile {
+		if path != root {
+			related = append(related, path)
+		}
+	}
+
+	sort.Strings(related)
+
+	paths := []string{docPath}
+	for _, path := range append([]string{root}, related...) {
+		if path != docPath {
+			paths = append(paths, path)
+		}
+	}
+
+	for _, path := range paths {
+		if err := s.publish(path, byFile[path]); err != nil {
+			return err
+		}
+	}
+
+	// clear the diagnostics of files which have no problems anymore
+	for _, path := range s.related[root] {
+		if _, ok := byFile[path]; !ok {
+			if err := s.publish(path, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.related[root] = related
+
+	return nil
+}
+
+// diagnose returns the problems of a root document
+func (s *Server) diagnose(root string) diagnostics.Diagnostics {
+	src, err := s.source(root)
+	if err != nil {
+		return errorDiagnostics(root, err)
+	}
+
+	return s.validate(root, src)
+}
+
+// diagnoseIncluded returns the problems of an open included document given the problems found
+// in it when validating the root document. The included document alone is only checked for
+// syntax and schema problems since it may use the references and locals of the root document and
+// document only a part of the module.
+func (s *Server) diagnoseIncluded(docPath string, rootDiags diagnostics.Diagnostics) diagnostics.Diagnostics {
+	var diags diagnostics.Diagnostics
+
+	if _, err := s.parse(docPath, s.docs[docPath]); err != nil {
+		for _, diag := range errorDiagnostics(docPath, err) {
+			if isSchemaDiagnostic(diag) {
+				diags = append(diags, diag)
+			}
+		}
+	}
+
+	for _, diag := range rootDiags {
+		// the schema problems of the open document were found above
+		if !isSchemaDiagnostic(diag) {
+			diags = append(diags, diag)
+		}
+	}
+
+	return diags
+}
+
+// isSchemaDiagnostic reports whether a diagnostic is a syntax or schema problem of a single file
+func isSchemaDiagnostic(diag diagnostics.Diagnostic) bool {
+	switch diag.Code {
+	case diagnostics.CodeSyntax, diagnostics.CodeSchema, diagnostics.CodeInvalidType:
+		return true
+	}
+
+	return false
+}
+
+// rootDocument returns the document that is not included by another document and includes
+// docPath directly or through other included documents. Documents that are not included are their
+// own root document.
+func rootDocument(docPath string) (string, error) {
+	seen := map[string]bool{docPath: true}
+
+	for {
+		including, err := includingDocument(docPath)
+		if err != nil {
+			return "", err
+		}
+
+		if including == "" || seen[including] {
+			return docPath, nil
+		}
+
+		seen[including] = true
+		docPath = including
+	}
+}
+
+// includingDocument returns the document including docPath with an `include` block, if any. Like
+// the documents of modules, included documents are searched in the directory of docPath and its
+// parent directory.
+func includingDocument(docPath string) (string, error) {
+	dir := filepath.Dir(docPath)
+
+	for _, searchDir := range []string{dir, filepath.Dir(dir)} {
+		candidates, err := filepath.Glob(filepath.Join(searchDir, "*"+docFileSuffix))
+		if err != nil {
+			return "", err
+		}
+
+		for _, candidate := range candidates {
+			if candidate == docPath {
+				continue
+			}
+
+			includes, err := docparser.Includes(candidate)
+			if err != nil {
+				return "", err
+			}
+
+			for _, include := range includes {
+				if filepath.Clean(include) == docPath {
+					return candidate, nil
+				}
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// validate returns the problems of a document with the given source. Documents are validated
+// against the .tf files of their module once they can be parsed. Modules without .tf files are
+// not validated.
+func (s *Server) validate(docPath string, src []byte) diagnostics.Diagnostics {
+	tfFiles, err := s.terraformFiles(docPath)
+	if err != nil {
+		return errorDiagnostics(docPath, err)
+	}
+
+	tfContent := entities.ValidationContents{}
+
+	// the problems of .tf files are reported once the document can be parsed
+	var tfDiags diagnostics.Diagnostics
+
+	for _, tfFile := range tfFiles {
+		src, err := s.source(tfFile)
+		if err != nil {
+			tfDiags = append(tfDiags, errorDiagnostics(tfFile, err)...)
+			continue
+		}
+
+		content, err := validationparser.Parse(bytes.NewReader(src), tfFile, validationparser.Options{
+			Variables:    true,
+			Outputs:      true,
+			Requirements: s.opts.Requirements,
+		})
+		if err != nil {
+			tfDiags = append(tfDiags, errorDiagnostics(tfFile, err)...)
+			continue
+		}
+
+		tfContent = tfContent.Merge(content)
+	}
+
+	doc, err := s.parse(docPath, src)
+	if err != nil {
+		return errorDiagnostics(docPath, err)
+	}
+
+	if len(tfDiags) > 0 {
+		return tfDiags
+	}
+
+	diags := refsvalidator.Validate(doc).Diagnostics()
+
+	if len(tfFiles) == 0 {
+		return diags
+	}
+
+	diags = append(diags, varsvalidator.ValidateWithOptions(doc, tfContent, s.opts).Diagnostics()...)
+	diags = append(diags, outputsvalidator.ValidateWithOptions(doc, tfContent, s.opts).Diagnostics()...)
+
+	if s.opts.Requirements {
+		diags = append(diags, requirementsvalidator.Validate(doc, tfContent).Diagnostics()...)
+	}
+
+	return diags
+}
+
+// parse parses a document with the module values detected once for its directory and the .tf
+// files of its module as they are open in the editor
+func (s *Server) parse(docPath string, src []byte) (entities.Doc, error) {
+	dir := filepath.Dir(docPath)
+
+	opts, ok := s.parseOpts[dir]
+	if !ok {
+		opts = detect.Options(dir, docparser.Options{})
+		s.parseOpts[dir] = opts
+	}
+
+	opts.Code = s.code(docPath)
+
+	return docparser.ParseWithOptions(bytes.NewReader(src), docPath, opts)
+}
+
+// code returns a loader parsing the .tf files of the module of a document. Files that can not be
+// parsed are ignored, they are reported by validation.
+func (s *Server) code(docPath string) docparser.CodeLoader {
+	return func(opts validationparser.Options) entities.ValidationContents {
+		code := entities.ValidationContents{}
+
+		tfFiles, err := s.terraformFiles(docPath)
+		if err != nil {
+			return code
+		}
+
+		for _, tfFile := range tfFiles {
+			src, err := s.source(tfFile)
+			if err != nil {
+				continue
+			}
+
+			content, err := validationparser.Parse(bytes.NewReader(src), tfFile, opts)
+			if err != nil {
+				continue
+			}
+
+			code = code.Merge(content)
+		}
+
+		return code
+	}
+}
+
+// errorDiagnostics returns the diagnostics of err. Errors without a source position are reported
+// at the beginning of the file.
+func errorDiagnostics(filename string, err error) diagnostics.Diagnostics {
+	if diags, ok := diagnostics.FromError(err); ok {
+		return diags
+	}
+
+	diag := diagnostics.New("", hcl.Range{Filename: filename}, err.Error(), "")
+
+	return diagnostics.Diagnostics{diag}
+}
+
+// publish sends the diagnostics of a file to the client
+func (s *Server) publish(path string, diags diagnostics.Diagnostics) error {
+	params := PublishDiagnosticsParams{
+		URI:         pathToURI(path),
+		Diagnostics: []Diagnostic{},
+	}
+
+	if len(diags) > 0 {
+		// unreadable files get their diagnostics at the beginning of the file
+		src, _ := s.source(path)
+
+		diags.Sort()
+
+		for _, diag := range diags {
+			params.Diagnostics = append(params.Diagnostics, Diagnostic{
+				Range:    rangeOf(src, diag.Range),
+				Severity: severity(diag.Severity),
+				Code:     diag.Code,
+				Source:   serverName,
+				Message:  diag.Message(),
+			})
+		}
+	}
+
+	return s.conn.notify("textDocument/publishDiagnostics", params)
+}
+
+func severity(s diagnostics.Severity) int {
+	if s == diagnostics.Warning {
+		return severityWarning
+	}
+
+	return severityError
+}
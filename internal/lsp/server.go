@@ -0,0 +1,399 @@
+// Package lsp implements a language server for .tfdoc.hcl files speaking the Language Server
+// Protocol over a stream, usually stdio
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/mineiros-io/terradoc/internal/parsers/docparser"
+	"github.com/mineiros-io/terradoc/internal/validators"
+)
+
+const (
+	serverName = "terradoc"
+
+	docFileSuffix       = ".tfdoc.hcl"
+	terraformFileSuffix = ".tf"
+)
+
+var errExit = errors.New("exit")
+
+// Server is a language server for .tfdoc.hcl files. Documents are synchronized in full on every
+// change and checked with the documentation parser and the validators.
+type Server struct {
+	conn *conn
+	opts validators.Options
+
+	// docs holds the content of the open documents by file path
+	docs map[string][]byte
+	// related holds the included documents and .tf files that got diagnostics when checking a
+	// root document
+	related map[string][]string
+	// parseOpts holds the module values detected with git by module directory, so documents are
+	// not parsed with git on every change
+	parseOpts map[string]docparser.Options
+
+	shutdown bool
+}
+
+// NewServer returns a server reading requests from r and writing responses to w. The optional
+// checks in opts are enabled when validating documents.
+func NewServer(r io.Reader, w io.Writer, opts validators.Options) *Server {
+	return &Server{
+		conn:      newConn(r, w),
+		opts:      opts,
+		docs:      map[string][]byte{},
+		related:   map[string][]string{},
+		parseOpts: map[string]docparser.Options{},
+	}
+}
+
+// Run handles requests until the client sends the `exit` notification or closes the connection
+func (s *Server) Run() error {
+	for {
+		req, err := s.conn.read()
+		if err != nil {
+			var rerr responseError
+			if errors.As(err, &rerr) {
+				if err := s.conn.replyError(nil, rerr); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return fmt.Errorf("reading request: %v", err)
+		}
+
+		err = s.handle(req)
+		if errors.Is(err, errExit) {
+			if !s.shutdown {
+				return errors.New("exit requested before shutdown")
+			}
+
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(req request) error {
+	if req.Method == "exit" {
+		return errExit
+	}
+
+	if s.shutdown && req.ID != nil {
+		return s.conn.replyError(req.ID, responseError{Code: codeInvalidRequest, Message: "server is shut down"})
+	}
+
+	if s.shutdown {
+		// notifications other than exit are dropped after shutdown
+		return nil
+	}
+
+	result, err := s.dispatch(req)
+
+	if req.ID == nil {
+		// notifications have no response. Errors of notifications can only be logged.
+		if err != nil {
+			return s.conn.notify("window/logMessage", map[string]interface{}{"type": 1, "message": err.Error()})
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		var rerr responseError
+		if !errors.As(err, &rerr) {
+			rerr = responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+
+		return s.conn.replyError(req.ID, rerr)
+	}
+
+	return s.conn.reply(req.ID, result)
+}
+
+func (s *Server) dispatch(req request) (interface{}, error) {
+	switch req.Method {
+	case "initialize":
+		return s.initialize(), nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, err
+		}
+
+		return nil, s.didOpen(params)
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, err
+		}
+
+		return nil, s.didChange(params)
+	case "textDocument/didSave":
+		var params DidSaveTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, err
+		}
+
+		return nil, s.didSave(params)
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, err
+		}
+
+		return nil, s.didClose(params)
+	case "textDocument/completion":
+		var params TextDocumentPositionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, err
+		}
+
+		return s.completion(params)
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, err
+		}
+
+		return s.hover(params)
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, err
+		}
+
+		return s.definition(params)
+	case "textDocument/formatting":
+		var params DocumentFormattingParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, err
+		}
+
+		return s.formatting(params)
+	}
+
+	if req.ID == nil {
+		// unknown notifications, e.g. `initialized` or `$/cancelRequest`, are ignored
+		return nil, nil
+	}
+
+	return nil, responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method %q is not supported", req.Method)}
+}
+
+func (s *Server) initialize() interface{} {
+	return map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			"textDocumentSync": map[string]interface{}{
+				"openClose": true,
+				"change":    textDocumentSyncFull,
+				"save":      true,
+			},
+			"completionProvider":         map[string]interface{}{},
+			"hoverProvider":              true,
+			"definitionProvider":         true,
+			"documentFormattingProvider": true,
+		},
+		"serverInfo": map[string]interface{}{
+			"name": serverName,
+		},
+	}
+}
+
+func (s *Server) didOpen(params DidOpenTextDocumentParams) error {
+	path, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+
+	s.docs[path] = []byte(params.TextDocument.Text)
+
+	return s.changed(path)
+}
+
+func (s *Server) didChange(params DidChangeTextDocumentParams) error {
+	path, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+
+	// documents are synchronized in full, so the last change holds the whole content
+	if n := len(params.ContentChanges); n > 0 {
+		s.docs[path] = []byte(params.ContentChanges[n-1].Text)
+	}
+
+	return s.changed(path)
+}
+
+func (s *Server) didSave(params DidSaveTextDocumentParams) error {
+	path, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+
+	return s.changed(path)
+}
+
+func (s *Server) didClose(params DidCloseTextDocumentParams) error {
+	path, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+
+	delete(s.docs, path)
+
+	if !isDocFile(path) {
+		return s.changed(path)
+	}
+
+	root, err := rootDocument(path)
+	if err != nil {
+		root = path
+	}
+
+	// the other open documents of the root document keep their diagnostics
+	for docPath := range s.docs {
+		if isDocFile(docPath) {
+			if docRoot, err := rootDocument(docPath); err == nil && docRoot == root {
+				return s.check(root)
+			}
+		}
+	}
+
+	closed := []string{root}
+	if path != root {
+		closed = append(closed, path)
+	}
+
+	for _, p := range append(closed, s.related[root]...) {
+		if err := s.publish(p, nil); err != nil {
+			return err
+		}
+	}
+
+	delete(s.related, root)
+
+	return nil
+}
+
+// changed checks a changed document. Changes of .tf files are checked by checking the open
+// documents of their module.
+func (s *Server) changed(path string) error {
+	if isDocFile(path) {
+		return s.check(path)
+	}
+
+	if !strings.HasSuffix(path, terraformFileSuffix) {
+		return nil
+	}
+
+	for docPath := range s.docs {
+		if isDocFile(docPath) && filepath.Dir(docPath) == filepath.Dir(path) {
+			if err := s.check(docPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) formatting(params DocumentFormattingParams) (interface{}, error) {
+	_, src, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	formatted := hclwrite.Format(src)
+	if string(formatted) == string(src) {
+		return []TextEdit{}, nil
+	}
+
+	edit := TextEdit{
+		Range:   Range{End: positionAt(src, len(src))},
+		NewText: string(formatted),
+	}
+
+	return []TextEdit{edit}, nil
+}
+
+// document returns the path and the content of an open document
+func (s *Server) document(uri string) (string, []byte, error) {
+	path, err := uriToPath(uri)
+	if err != nil {
+		return "", nil, err
+	}
+
+	src, ok := s.docs[path]
+	if !ok {
+		return "", nil, fmt.Errorf("document %q is not open", uri)
+	}
+
+	return path, src, nil
+}
+
+// source returns the content of a file, preferring the content of an open document over the
+// content on disk
+func (s *Server) source(path string) ([]byte, error) {
+	if src, ok := s.docs[path]; ok {
+		return src, nil
+	}
+
+	return ioutil.ReadFile(path)
+}
+
+// terraformFiles returns the .tf files of the module of a document, including open .tf documents
+// that were not saved yet
+func (s *Server) terraformFiles(docPath string) ([]string, error) {
+	dir := filepath.Dir(docPath)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+terraformFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	for path := range s.docs {
+		if filepath.Dir(path) == dir && strings.HasSuffix(path, terraformFileSuffix) && !hasString(files, path) {
+			files = append(files, path)
+		}
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func hasString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isDocFile(path string) bool {
+	return strings.HasSuffix(path, docFileSuffix)
+}
@@ -0,0 +1,486 @@
+package lsp_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/internal/lsp"
+	"github.com/mineiros-io/terradoc/internal/validators"
+)
+
+const variablesTF = `variable "name" {
+  type        = string
+  description = "The name of the bucket."
+}
+
+variable "tags" {
+  type = map(string)
+}
+`
+
+const docHCL = `section {
+  title = "Variables"
+
+  variable "name" {
+    type        = number
+    description = "The name of the bucket."
+  }
+}
+`
+
+type message struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type client struct {
+	t        *testing.T
+	w        io.Writer
+	messages chan message
+	done     chan error
+	id       int
+}
+
+// startServer runs a server on pipes and returns a client connected to it
+func startServer(t *testing.T) *client {
+	t.Helper()
+
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+
+	c := &client{t: t, w: clientW, messages: make(chan message, 100), done: make(chan error, 1)}
+
+	go func() {
+		c.done <- lsp.NewServer(serverR, serverW, validators.Options{}).Run()
+		serverW.Close()
+	}()
+
+	go func() {
+		r := bufio.NewReader(clientR)
+		for {
+			header, err := textproto.NewReader(r).ReadMIMEHeader()
+			if err != nil {
+				close(c.messages)
+				return
+			}
+
+			length, _ := strconv.Atoi(header.Get("Content-Length"))
+			content := make([]byte, length)
+			if _, err := io.ReadFull(r, content); err != nil {
+				close(c.messages)
+				return
+			}
+
+			var msg message
+			if err := json.Unmarshal(content, &msg); err != nil {
+				t.Errorf("invalid message %s: %v", content, err)
+			}
+
+			c.messages <- msg
+		}
+	}()
+
+	t.Cleanup(func() { clientW.Close() })
+
+	c.request("initialize", map[string]interface{}{})
+	c.notify("initialized", map[string]interface{}{})
+
+	return c
+}
+
+func (c *client) send(msg map[string]interface{}) {
+	c.t.Helper()
+
+	msg["jsonrpc"] = "2.0"
+
+	content, err := json.Marshal(msg)
+	assert.NoError(c.t, err)
+
+	_, err = fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(content), content)
+	assert.NoError(c.t, err)
+}
+
+func (c *client) notify(method string, params interface{}) {
+	c.t.Helper()
+	c.send(map[string]interface{}{"method": method, "params": params})
+}
+
+// request sends a request and returns its response, skipping notifications
+func (c *client) request(method string, params interface{}) message {
+	c.t.Helper()
+
+	c.id++
+	c.send(map[string]interface{}{"id": c.id, "method": method, "params": params})
+
+	for {
+		msg := c.next()
+		if msg.ID != nil && *msg.ID == c.id {
+			return msg
+		}
+	}
+}
+
+// diagnostics returns the next diagnostics published for uri
+func (c *client) diagnostics(uri string) []lsp.Diagnostic {
+	c.t.Helper()
+
+	for {
+		msg := c.next()
+		if msg.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+
+		var params lsp.PublishDiagnosticsParams
+		assert.NoError(c.t, json.Unmarshal(msg.Params, &params))
+
+		if params.URI == uri {
+			return params.Diagnostics
+		}
+	}
+}
+
+func (c *client) next() message {
+	c.t.Helper()
+
+	select {
+	case msg, ok := <-c.messages:
+		if !ok {
+			c.t.Fatal("server closed the connection")
+		}
+
+		return msg
+	case <-time.After(5 * time.Second):
+		c.t.Fatal("timeout waiting for a message from the server")
+	}
+
+	return message{}
+}
+
+func (c *client) open(uri, text string) {
+	c.t.Helper()
+	c.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "hcl", "version": 1, "text": text},
+	})
+}
+
+func (c *client) change(uri, text string) {
+	c.t.Helper()
+	c.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": uri, "version": 2},
+		"contentChanges": []map[string]interface{}{{"text": text}},
+	})
+}
+
+func positionParams(uri string, line, character int) map[string]interface{} {
+	return map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"position":     map[string]interface{}{"line": line, "character": character},
+	}
+}
+
+// module writes the files of a module and returns the URI of each file
+func module(t *testing.T, files map[string]string) map[string]string {
+	t.Helper()
+
+	dir := t.TempDir()
+	uris := map[string]string{}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if content != "" {
+			assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+		}
+
+		uris[name] = (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+	}
+
+	return uris
+}
+
+func TestDiagnostics(t *testing.T) {
+	uris := module(t, map[string]string{
+		"variables.tf":     variablesTF,
+		"README.tfdoc.hcl": "",
+	})
+
+	c := startServer(t)
+	c.open(uris["README.tfdoc.hcl"], docHCL)
+
+	got := c.diagnostics(uris["README.tfdoc.hcl"])
+	assert.EqualInts(t, 1, len(got))
+	assert.EqualStrings(t, "type-mismatch", got[0].Code)
+	assert.EqualStrings(t, `Type mismatch for variable: "name" is documented as "number" but defined as "string" in .tf files`, got[0].Message)
+	assert.EqualInts(t, 3, got[0].Range.Start.Line)
+	assert.EqualInts(t, 2, got[0].Range.Start.Character)
+
+	tfDiags := c.diagnostics(uris["variables.tf"])
+	assert.EqualInts(t, 1, len(tfDiags))
+	assert.EqualStrings(t, "missing-documentation", tfDiags[0].Code)
+	assert.EqualInts(t, 5, tfDiags[0].Range.Start.Line)
+
+	t.Run("Fixed", func(t *testing.T) {
+		fixed := strings.Replace(docHCL, "number", "string", 1)
+		fixed = strings.Replace(fixed, "  }\n}", "  }\n\n  variable \"tags\" {\n    type = map(string)\n  }\n}", 1)
+		c.change(uris["README.tfdoc.hcl"], fixed)
+
+		assert.EqualInts(t, 0, len(c.diagnostics(uris["README.tfdoc.hcl"])))
+		assert.EqualInts(t, 0, len(c.diagnostics(uris["variables.tf"])))
+	})
+
+	t.Run("SyntaxError", func(t *testing.T) {
+		c.change(uris["README.tfdoc.hcl"], "section {\n  title = \n}\n")
+
+		got := c.diagnostics(uris["README.tfdoc.hcl"])
+		assert.EqualInts(t, 1, len(got))
+		assert.EqualStrings(t, "syntax", got[0].Code)
+		assert.EqualInts(t, 1, got[0].Range.Start.Line)
+	})
+}
+
+func TestDiagnosticsIncluded(t *testing.T) {
+	inputsHCL := strings.Replace(docHCL, "number", "string", 1)
+
+	uris := module(t, map[string]string{
+		"variables.tf":     variablesTF,
+		"inputs.tfdoc.hcl": inputsHCL,
+		"README.tfdoc.hcl": `include "inputs.tfdoc.hcl" {}
+
+section {
+  title = "Tags"
+
+  variable "tags" {
+    type = number
+  }
+}
+`,
+	})
+
+	c := startServer(t)
+	c.open(uris["inputs.tfdoc.hcl"], inputsHCL)
+
+	assert.EqualInts(t, 0, len(c.diagnostics(uris["inputs.tfdoc.hcl"])))
+
+	got := c.diagnostics(uris["README.tfdoc.hcl"])
+	assert.EqualInts(t, 1, len(got))
+	assert.EqualStrings(t, "type-mismatch", got[0].Code)
+	assert.EqualInts(t, 5, got[0].Range.Start.Line)
+
+	t.Run("SchemaError", func(t *testing.T) {
+		c.change(uris["inputs.tfdoc.hcl"], "section {\n  unknown = 1\n}\n")
+
+		got := c.diagnostics(uris["inputs.tfdoc.hcl"])
+		assert.EqualInts(t, 1, len(got))
+		assert.EqualStrings(t, "schema", got[0].Code)
+	})
+
+}
+
+func TestDiagnosticsCloseIncluded(t *testing.T) {
+	inputsHCL := strings.Replace(docHCL, "number", "string", 1)
+	readmeHCL := `include "inputs.tfdoc.hcl" {}
+
+section {
+  title = "Tags"
+
+  variable "tags" {
+    type = number
+  }
+}
+`
+
+	uris := module(t, map[string]string{
+		"variables.tf":     variablesTF,
+		"inputs.tfdoc.hcl": inputsHCL,
+		"README.tfdoc.hcl": readmeHCL,
+	})
+
+	c := startServer(t)
+	c.open(uris["inputs.tfdoc.hcl"], inputsHCL)
+
+	assert.EqualInts(t, 0, len(c.diagnostics(uris["inputs.tfdoc.hcl"])))
+	assert.EqualInts(t, 1, len(c.diagnostics(uris["README.tfdoc.hcl"])))
+
+	c.open(uris["README.tfdoc.hcl"], readmeHCL)
+	assert.EqualInts(t, 1, len(c.diagnostics(uris["README.tfdoc.hcl"])))
+
+	c.notify("textDocument/didClose", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uris["inputs.tfdoc.hcl"]},
+	})
+
+	// the open root document keeps its diagnostics
+	got := c.diagnostics(uris["README.tfdoc.hcl"])
+	assert.EqualInts(t, 1, len(got))
+	assert.EqualStrings(t, "type-mismatch", got[0].Code)
+}
+
+func TestCompletion(t *testing.T) {
+	uris := module(t, map[string]string{"README.tfdoc.hcl": ""})
+
+	c := startServer(t)
+	c.open(uris["README.tfdoc.hcl"], docHCL)
+
+	tests := []struct {
+		desc      string
+		line      int
+		character int
+		want      []string
+	}{
+		{
+			desc: "root",
+			line: 8,
+			want: []string{"header", "section", "references"},
+		},
+		{
+			desc:      "section",
+			line:      2,
+			character: 2,
+			want:      []string{"content", "toc", "section", "variable", "output"},
+		},
+		{
+			desc:      "variable",
+			line:      6,
+			character: 2,
+			want:      []string{"default", "required", "readme_example", "attribute", "validation"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			resp := c.request("textDocument/completion", positionParams(uris["README.tfdoc.hcl"], tt.line, tt.character))
+
+			var items []lsp.CompletionItem
+			assert.NoError(t, json.Unmarshal(resp.Result, &items))
+
+			labels := map[string]bool{}
+			for _, item := range items {
+				labels[item.Label] = true
+			}
+
+			for _, want := range tt.want {
+				if !labels[want] {
+					t.Errorf("wanted completion %q in %v", want, labels)
+				}
+			}
+
+			if tt.desc == "variable" && (labels["type"] || labels["description"]) {
+				t.Errorf("wanted attributes that are already set to be left out, got %v", labels)
+			}
+		})
+	}
+}
+
+func TestHoverAndDefinition(t *testing.T) {
+	uris := module(t, map[string]string{
+		"variables.tf":     variablesTF,
+		"README.tfdoc.hcl": "",
+	})
+
+	c := startServer(t)
+	c.open(uris["README.tfdoc.hcl"], docHCL)
+
+	resp := c.request("textDocument/hover", positionParams(uris["README.tfdoc.hcl"], 3, 14))
+
+	var hover lsp.Hover
+	assert.NoError(t, json.Unmarshal(resp.Result, &hover))
+
+	want := "Defined in `variables.tf`:\n\n```hcl\nvariable \"name\" {\n  type        = string\n  description = \"The name of the bucket.\"\n}\n```"
+	assert.EqualStrings(t, want, hover.Contents.Value)
+
+	resp = c.request("textDocument/definition", positionParams(uris["README.tfdoc.hcl"], 3, 4))
+
+	var location lsp.Location
+	assert.NoError(t, json.Unmarshal(resp.Result, &location))
+	assert.EqualStrings(t, uris["variables.tf"], location.URI)
+	assert.EqualInts(t, 0, location.Range.Start.Line)
+	assert.EqualInts(t, 0, location.Range.Start.Character)
+	assert.EqualInts(t, 17, location.Range.End.Character)
+
+	// positions outside of a variable header have no definition
+	resp = c.request("textDocument/definition", positionParams(uris["README.tfdoc.hcl"], 4, 6))
+	assert.EqualStrings(t, "null", string(resp.Result))
+}
+
+func TestFormatting(t *testing.T) {
+	uris := module(t, map[string]string{"README.tfdoc.hcl": ""})
+
+	c := startServer(t)
+	c.open(uris["README.tfdoc.hcl"], "section {\ntitle = \"a\"\n    content=\"b\"\n}")
+
+	resp := c.request("textDocument/formatting", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uris["README.tfdoc.hcl"]},
+	})
+
+	var edits []lsp.TextEdit
+	assert.NoError(t, json.Unmarshal(resp.Result, &edits))
+	assert.EqualInts(t, 1, len(edits))
+	assert.EqualStrings(t, "section {\n  title   = \"a\"\n  content = \"b\"\n}", edits[0].NewText)
+	assert.EqualInts(t, 3, edits[0].Range.End.Line)
+	assert.EqualInts(t, 1, edits[0].Range.End.Character)
+}
+
+func TestInvalidContentLength(t *testing.T) {
+	c := startServer(t)
+
+	for _, length := range []string{"-1", "1099511627776"} {
+		_, err := fmt.Fprintf(c.w, "Content-Length: %s\r\n\r\n", length)
+		assert.NoError(t, err)
+
+		msg := c.next()
+		if msg.Error == nil || msg.Error.Code != -32700 {
+			t.Errorf("wanted parse error for Content-Length %s, got %+v", length, msg)
+		}
+	}
+
+	resp := c.request("shutdown", nil)
+	assert.EqualStrings(t, "null", string(resp.Result))
+}
+
+func TestShutdown(t *testing.T) {
+	c := startServer(t)
+
+	resp := c.request("textDocument/unknown", map[string]interface{}{})
+	if resp.Error == nil || resp.Error.Code != -32601 {
+		t.Errorf("wanted method not found error, got %+v", resp)
+	}
+
+	resp = c.request("shutdown", nil)
+	assert.EqualStrings(t, "null", string(resp.Result))
+
+	uris := module(t, map[string]string{"README.tfdoc.hcl": docHCL})
+	c.open(uris["README.tfdoc.hcl"], docHCL)
+
+	// the notification is dropped, so the next message is the response of the request
+	c.id++
+	c.send(map[string]interface{}{"id": c.id, "method": "textDocument/hover", "params": positionParams(uris["README.tfdoc.hcl"], 0, 0)})
+
+	msg := c.next()
+	if msg.ID == nil || *msg.ID != c.id || msg.Error == nil {
+		t.Errorf("wanted shut down error response, got %+v", msg)
+	}
+
+	c.notify("exit", nil)
+
+	select {
+	case err := <-c.done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the server to exit")
+	}
+}
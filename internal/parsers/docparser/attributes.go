@@ -4,18 +4,19 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/parsers/hclparser"
 	"github.com/mineiros-io/terradoc/internal/schemas/docschema"
 )
 
-func parseVariableAttributes(attributeBlocks hcl.Blocks) (attributes []entities.Attribute, err error) {
+func parseVariableAttributes(attributeBlocks hcl.Blocks, ctx *hcl.EvalContext) (attributes []entities.Attribute, err error) {
 	const variableAttributeLevel = 1
 
 	for _, attrBlk := range attributeBlocks {
-		attribute, err := parseAttribute(attrBlk, variableAttributeLevel)
+		attribute, err := parseAttribute(attrBlk, variableAttributeLevel, ctx)
 		if err != nil {
-			return nil, fmt.Errorf("parsing attributes: %s", err)
+			return nil, fmt.Errorf("parsing attributes: %w", err)
 		}
 
 		attributes = append(attributes, attribute)
@@ -24,30 +25,32 @@ func parseVariableAttributes(attributeBlocks hcl.Blocks) (attributes []entities.
 	return attributes, nil
 }
 
-func parseAttribute(attrBlock *hcl.Block, level int) (entities.Attribute, error) {
+func parseAttribute(attrBlock *hcl.Block, level int, ctx *hcl.EvalContext) (entities.Attribute, error) {
 	attrContent, diags := attrBlock.Body.Content(docschema.AttributeSchema())
 	if diags.HasErrors() {
-		return entities.Attribute{}, fmt.Errorf("parsing attribute block: %v", diags.Errs())
+		return entities.Attribute{}, fmt.Errorf("parsing attribute block: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
 	}
 
 	if len(attrBlock.Labels) != 1 {
-		return entities.Attribute{}, fmt.Errorf("expected single 'name' label, got %v", attrBlock.Labels)
+		return entities.Attribute{}, diagnostics.New(diagnostics.CodeSchema, attrBlock.DefRange, fmt.Sprintf("expected single 'name' label, got %v", attrBlock.Labels), "")
 	}
 
 	// variable blocks are required to have a label as defined in the schema
 	name := attrBlock.Labels[0]
 
-	attr, err := createAttributeFromHCLAttributes(attrContent.Attributes, name, level)
+	attr, err := createAttributeFromHCLAttributes(attrContent.Attributes, name, level, ctx)
 	if err != nil {
-		return entities.Attribute{}, fmt.Errorf("parsing attribute: %s", err)
+		return entities.Attribute{}, fmt.Errorf("parsing attribute: %w", err)
 	}
 
+	attr.DefRange = attrBlock.DefRange
+
 	nestedAttributeLevel := level + 1
 	// attribute blocks have only `attribute` blocks
 	for _, blk := range attrContent.Blocks.OfType(attributeBlockName) {
-		nestedAttr, err := parseAttribute(blk, nestedAttributeLevel)
+		nestedAttr, err := parseAttribute(blk, nestedAttributeLevel, ctx)
 		if err != nil {
-			return entities.Attribute{}, fmt.Errorf("parsing nested attribute: %s", err)
+			return entities.Attribute{}, fmt.Errorf("parsing nested attribute: %w", err)
 		}
 
 		attr.Attributes = append(attr.Attributes, nestedAttr)
@@ -56,12 +59,12 @@ func parseAttribute(attrBlock *hcl.Block, level int) (entities.Attribute, error)
 	return attr, nil
 }
 
-func createAttributeFromHCLAttributes(attrs hcl.Attributes, name string, level int) (entities.Attribute, error) {
+func createAttributeFromHCLAttributes(attrs hcl.Attributes, name string, level int, ctx *hcl.EvalContext) (entities.Attribute, error) {
 	var err error
 
 	attr := entities.Attribute{Name: name, Level: level}
 
-	attr.Description, err = hclparser.GetAttribute(attrs, descriptionAttributeName).String()
+	attr.Description, err = hclparser.GetAttribute(attrs, descriptionAttributeName).StringWithContext(ctx)
 	if err != nil {
 		return entities.Attribute{}, err
 	}
@@ -76,7 +79,7 @@ func createAttributeFromHCLAttributes(attrs hcl.Attributes, name string, level i
 		return entities.Attribute{}, err
 	}
 
-	attr.ReadmeExample, err = hclparser.GetAttribute(attrs, readmeExampleAttributeName).String()
+	attr.ReadmeExample, err = hclparser.GetAttribute(attrs, readmeExampleAttributeName).StringWithContext(ctx)
 	if err != nil {
 		return entities.Attribute{}, err
 	}
@@ -84,9 +87,9 @@ func createAttributeFromHCLAttributes(attrs hcl.Attributes, name string, level i
 	// type definition
 	readmeType := hclparser.GetAttribute(attrs, readmeTypeAttributeName)
 	if readmeType == nil {
-		attr.Type, err = hclparser.GetAttribute(attrs, typeAttributeName).VarType()
+		attr.Type, err = hclparser.GetAttribute(attrs, typeAttributeName).AttributeType()
 	} else {
-		attr.Type, err = readmeType.VarTypeFromString()
+		attr.Type, err = readmeType.AttributeTypeFromString()
 	}
 
 	if err != nil {
@@ -98,5 +101,31 @@ func createAttributeFromHCLAttributes(attrs hcl.Attributes, name string, level i
 		return entities.Attribute{}, err
 	}
 
+	if attr.Type.Optional {
+		return optionalAttribute(attr, attrs)
+	}
+
+	return attr, nil
+}
+
+// optionalAttribute makes an attribute typed as `optional(type, default)` optional and gives it the
+// default of the modifier
+func optionalAttribute(attr entities.Attribute, attrs hcl.Attributes) (entities.Attribute, error) {
+	if required, ok := attrs[requiredAttributeName]; ok && attr.Required {
+		return entities.Attribute{}, diagnostics.New(diagnostics.CodeInvalidValue, required.Range,
+			fmt.Sprintf("attribute %q is declared as optional and can not be required", attr.Name), "")
+	}
+
+	if len(attr.Type.Default) > 0 {
+		if def, ok := attrs[defaultAttributeName]; ok {
+			return entities.Attribute{}, diagnostics.New(diagnostics.CodeInvalidValue, def.Range,
+				fmt.Sprintf("attribute %q has a default in both its optional type and its default attribute", attr.Name), "")
+		}
+
+		attr.Default = attr.Type.Default
+	}
+
+	attr.Type = attr.Type.WithoutModifier()
+
 	return attr, nil
 }
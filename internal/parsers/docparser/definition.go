@@ -2,36 +2,83 @@ package docparser
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/schemas/docschema"
+	"github.com/zclconf/go-cty/cty"
 )
 
-func parseDoc(f *hcl.File) (entities.Doc, error) {
+// parseDoc parses the document of f and the documents it includes. The sections of included
+// documents are inserted at the position of their `include` block and their references are
+// appended after the references of the including document. Parents are the absolute paths of the
+// documents including f and are used to detect include cycles. The locals of the including
+// documents are available to the expressions of f and the documents it includes.
+func parseDoc(p *hclparse.Parser, f *hcl.File, filename string, parents []string, env *environment, locals map[string]cty.Value) (entities.Doc, error) {
 	docContent, diags := f.Body.Content(docschema.RootSchema())
 	if diags.HasErrors() {
-		return entities.Doc{}, fmt.Errorf("parsing Terradoc doc: %v", diags.Errs())
+		return entities.Doc{}, fmt.Errorf("parsing Terradoc doc: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
 	}
 
-	var err error
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return entities.Doc{}, err
+	}
 
-	def := entities.Doc{}
+	parents = append(parents, abs)
 
-	def.Header, err = parseHeader(docContent.Blocks.OfType(headerBlockName))
+	ctx, locals, err := env.context(f, docContent.Blocks.OfType(localsBlockName), locals)
 	if err != nil {
-		return entities.Doc{}, fmt.Errorf("parsing header: %v", err)
+		return entities.Doc{}, err
 	}
 
-	def.Sections, err = parseSections(docContent.Blocks.OfType(sectionBlockName))
+	def := entities.Doc{}
+
+	def.Header, err = parseHeader(docContent.Blocks.OfType(headerBlockName), ctx)
 	if err != nil {
-		return entities.Doc{}, err
+		return entities.Doc{}, fmt.Errorf("parsing header: %w", err)
 	}
 
-	def.References, err = parseReferences(docContent.Blocks.OfType(referencesBlockName))
+	def.References, err = parseReferences(docContent.Blocks.OfType(referencesBlockName), ctx)
 	if err != nil {
 		return entities.Doc{}, err
 	}
 
+	var includedRefs []entities.Reference
+
+	for _, blk := range docContent.Blocks {
+		switch blk.Type {
+		case sectionBlockName:
+			sections, err := parseSections(hcl.Blocks{blk}, f.Bytes, ctx)
+			if err != nil {
+				return entities.Doc{}, err
+			}
+
+			def.Sections = append(def.Sections, sections...)
+		case includeBlockName:
+			included, err := parseInclude(p, blk, filename, parents, env, locals)
+			if err != nil {
+				return entities.Doc{}, err
+			}
+
+			if !isEmptyHeader(included.Header) {
+				if !isEmptyHeader(def.Header) {
+					return entities.Doc{}, diagnostics.New(diagnostics.CodeSchema, blk.DefRange,
+						"Duplicate header", fmt.Sprintf("%q includes a header but the document already has one", blk.Labels[0]))
+				}
+
+				def.Header = included.Header
+			}
+
+			def.Sections = append(def.Sections, included.Sections...)
+			includedRefs = append(includedRefs, included.References...)
+		}
+	}
+
+	def.References = append(def.References, includedRefs...)
+
 	return def, nil
 }
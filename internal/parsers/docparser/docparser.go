@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
 	"github.com/mineiros-io/terradoc/internal/entities"
 )
 
@@ -17,40 +18,84 @@ const (
 	defaultAttributeName          = "default"
 	requiredAttributeName         = "required"
 	forcesRecreationAttributeName = "forces_recreation"
+	sensitiveAttributeName        = "sensitive"
+	nullableAttributeName         = "nullable"
+	ephemeralAttributeName        = "ephemeral"
+	conditionAttributeName        = "condition"
+	errorMessageAttributeName     = "error_message"
 	readmeExampleAttributeName    = "readme_example"
 	valueAttributeName            = "value"
 	imageAttributeName            = "image"
 	urlAttributeName              = "url"
 	textAttributeName             = "text"
 	tocAttributeName              = "toc"
+	terraformAttributeName        = "terraform"
+	sourceAttributeName           = "source"
+	versionAttributeName          = "version"
+	generatedAttributeName        = "generated"
 
-	sectionBlockName    = "section"
-	variableBlockName   = "variable"
-	attributeBlockName  = "attribute"
-	referencesBlockName = "references"
-	refBlockName        = "ref"
-	headerBlockName     = "header"
-	badgeBlockName      = "badge"
-	outputBlockName     = "output"
+	sectionBlockName      = "section"
+	variableBlockName     = "variable"
+	attributeBlockName    = "attribute"
+	referencesBlockName   = "references"
+	refBlockName          = "ref"
+	headerBlockName       = "header"
+	badgeBlockName        = "badge"
+	outputBlockName       = "output"
+	validationBlockName   = "validation"
+	includeBlockName      = "include"
+	localsBlockName       = "locals"
+	requirementsBlockName = "requirements"
+	providerBlockName     = "provider"
 )
 
-// Parse reads the content of a io.Reader and returns a Definition entity from its parsed values
+// Parse reads the content of a io.Reader and returns a Definition entity from its parsed values.
+// Files included with `include` blocks are read relative to the directory of filename.
 func Parse(r io.Reader, filename string) (entities.Doc, error) {
+	return ParseWithOptions(r, filename, Options{})
+}
+
+// ParseWithOptions parses a document like Parse with the values of the module expressions set in
+// opts
+func ParseWithOptions(r io.Reader, filename string, opts Options) (entities.Doc, error) {
 	src, err := io.ReadAll(r)
 	if err != nil {
 		return entities.Doc{}, err
 	}
 
-	return parseHCL(src, filename)
+	return parseHCL(src, filename, opts)
 }
 
-func parseHCL(src []byte, filename string) (entities.Doc, error) {
+func parseHCL(src []byte, filename string, opts Options) (entities.Doc, error) {
 	p := hclparse.NewParser()
 
 	f, diags := p.ParseHCL(src, filename)
 	if diags.HasErrors() {
-		return entities.Doc{}, fmt.Errorf("parsing HCL: %v", diags.Errs())
+		return entities.Doc{}, fmt.Errorf("parsing HCL: %w", diagnostics.FromHCL(diagnostics.CodeSyntax, diags))
+	}
+
+	env, err := newEnvironment(filename, opts)
+	if err != nil {
+		return entities.Doc{}, err
+	}
+
+	doc, err := parseDoc(p, f, filename, nil, env, nil)
+	if err != nil {
+		return entities.Doc{}, err
 	}
 
-	return parseDoc(f)
+	if err := checkDuplicates(doc); err != nil {
+		return entities.Doc{}, err
+	}
+
+	doc.ReferenceUses, err = parseReferenceUses(p.Files(), doc.References)
+	if err != nil {
+		return entities.Doc{}, err
+	}
+
+	expandReferences(&doc)
+	fillRequirements(&doc, env)
+	fillResources(&doc, env)
+
+	return doc, nil
 }
@@ -3,12 +3,18 @@ package docparser_test
 import (
 	"bytes"
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/internal/detect"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/parsers/docparser"
+	"github.com/mineiros-io/terradoc/internal/parsers/validationparser"
 	"github.com/mineiros-io/terradoc/internal/types"
 	"github.com/mineiros-io/terradoc/test"
 )
@@ -186,11 +192,13 @@ func TestParseInvalidContent(t *testing.T) {
 	for _, tt := range []struct {
 		desc                 string
 		wantErrorMsgContains string
+		wantPos              string
 		content              string
 	}{
 		{
 			desc:                 "variable block without a label",
 			wantErrorMsgContains: "Missing name for variable; All variable blocks must have 1 labels (name).",
+			wantPos:              "foo-file:8:14",
 			content: `
 section {
   title = "test"
@@ -209,6 +217,7 @@ section {
 		{
 			desc:                 "variable block without a type",
 			wantErrorMsgContains: "Missing required argument; The argument \"type\" is required, but no definition was found.",
+			wantPos:              "foo-file:8:20",
 			content: `
 section {
   title = "test"
@@ -227,6 +236,7 @@ section {
 		{
 			desc:                 "attribute block without a label",
 			wantErrorMsgContains: "Missing name for attribute; All attribute blocks must have 1 labels (name)",
+			wantPos:              "foo-file:11:17",
 			content: `
 section {
   title = "test"
@@ -248,6 +258,7 @@ section {
 		{
 			desc:                 "attribute block without a type",
 			wantErrorMsgContains: "Missing required argument; The argument \"type\" is required, but no definition was found",
+			wantPos:              "foo-file:11:23",
 			content: `
 section {
   title = "test"
@@ -264,6 +275,58 @@ section {
     }
   }
 }
+`,
+		},
+		{
+			desc:                 "optional attribute documented as required",
+			wantErrorMsgContains: `attribute "port" is declared as optional and can not be required`,
+			wantPos:              "foo-file:9:7",
+			content: `
+section {
+  title = "test"
+
+  variable "test" {
+    type = object(test)
+
+    attribute "port" {
+      required = true
+      type     = optional(number)
+    }
+  }
+}
+`,
+		},
+		{
+			desc:                 "optional attribute with two defaults",
+			wantErrorMsgContains: `attribute "port" has a default in both its optional type and its default attribute`,
+			wantPos:              "foo-file:9:7",
+			content: `
+section {
+  title = "test"
+
+  variable "test" {
+    type = object(test)
+
+    attribute "port" {
+      default = 443
+      type    = optional(number, 80)
+    }
+  }
+}
+`,
+		},
+		{
+			desc:                 "optional variable type",
+			wantErrorMsgContains: "optional is only allowed for the attributes of object types",
+			wantPos:              "foo-file:6:12",
+			content: `
+section {
+  title = "test"
+
+  variable "test" {
+    type = optional(string)
+  }
+}
 `,
 		},
 	} {
@@ -276,6 +339,135 @@ section {
 			if !strings.Contains(err.Error(), tt.wantErrorMsgContains) {
 				t.Errorf("Expected error message to contain %q but got %q instead", tt.wantErrorMsgContains, err.Error())
 			}
+
+			diags, ok := diagnostics.FromError(err)
+			if !ok {
+				t.Fatalf("Expected error to have diagnostics, got %q instead", err.Error())
+			}
+
+			assert.EqualStrings(t, tt.wantPos, diags[0].Pos())
+		})
+	}
+}
+
+func TestParseVariableFlags(t *testing.T) {
+	const content = `
+section {
+  title = "test"
+
+  variable "password" {
+    type      = string
+    sensitive = true
+    ephemeral = true
+    nullable  = false
+  }
+
+  variable "name" {
+    type = string
+  }
+}
+`
+
+	got, err := docparser.Parse(bytes.NewBufferString(content), "foo-file")
+	assert.NoError(t, err)
+
+	variables := got.AllVariables()
+	assert.EqualInts(t, 2, len(variables))
+
+	password := variables[0]
+	if !password.Sensitive || !password.Ephemeral || password.Nullable {
+		t.Errorf("wanted variable %q to be sensitive, ephemeral and not nullable, got %+v", password.Name, password)
+	}
+
+	name := variables[1]
+	if name.Sensitive || name.Ephemeral || !name.Nullable {
+		t.Errorf("wanted variable %q to have the Terraform defaults, got %+v", name.Name, name)
+	}
+}
+
+func TestParseConstraints(t *testing.T) {
+	const content = `
+section {
+  title = "test"
+
+  variable "name" {
+    type = string
+
+    validation {
+      condition     = length(var.name) > 3
+      error_message = "The name must be longer than 3 characters."
+    }
+
+    validation {
+      condition = can(regex("^[a-z]+$", var.name))
+    }
+  }
+}
+`
+
+	got, err := docparser.Parse(bytes.NewBufferString(content), "foo-file")
+	assert.NoError(t, err)
+
+	constraints := got.AllVariables()[0].Constraints
+	assert.EqualInts(t, 2, len(constraints))
+
+	assert.EqualStrings(t, "length(var.name) > 3", constraints[0].Condition)
+	assert.EqualStrings(t, "The name must be longer than 3 characters.", constraints[0].ErrorMessage)
+
+	assert.EqualStrings(t, `can(regex("^[a-z]+$", var.name))`, constraints[1].Condition)
+	assert.EqualStrings(t, "", constraints[1].ErrorMessage)
+}
+
+func TestParseOptionalAttributes(t *testing.T) {
+	const content = `
+section {
+  title = "test"
+
+  variable "rules" {
+    type = list(rule)
+
+    attribute "name" {
+      type = string
+    }
+
+    attribute "port" {
+      type = optional(number, 80)
+    }
+
+    attribute "tags" {
+      type    = optional(map(string))
+      default = {}
+    }
+  }
+}
+`
+
+	got, err := docparser.Parse(bytes.NewBufferString(content), "foo-file")
+	assert.NoError(t, err)
+
+	attributes := got.AllVariables()[0].Attributes
+	assert.EqualInts(t, 3, len(attributes))
+
+	for _, tt := range []struct {
+		attribute   entities.Attribute
+		wantType    string
+		wantDefault string
+	}{
+		{attribute: attributes[0], wantType: "string"},
+		{attribute: attributes[1], wantType: "number", wantDefault: "80"},
+		{attribute: attributes[2], wantType: "map(string)", wantDefault: "{}"},
+	} {
+		t.Run(tt.attribute.Name, func(t *testing.T) {
+			assert.EqualStrings(t, tt.wantType, tt.attribute.Type.AsString())
+			assert.EqualStrings(t, tt.wantDefault, string(tt.attribute.Default))
+
+			if tt.attribute.Required {
+				t.Error("wanted attribute not to be required")
+			}
+
+			if tt.attribute.Type.Optional {
+				t.Error("wanted the optional modifier to be removed from the attribute type")
+			}
 		})
 	}
 }
@@ -451,3 +643,579 @@ func assertOutputEquals(t *testing.T, want, got entities.Output) {
 
 	test.AssertEqualTypes(t, want.Type, got.Type)
 }
+
+func TestParseIncludes(t *testing.T) {
+	dir := writeDocs(t, map[string]string{
+		"README.tfdoc.hcl": `
+header {
+  image = "https://example.com/logo.png"
+  url   = "https://example.com"
+}
+
+section {
+  title = "Introduction"
+}
+
+include "inputs.tfdoc.hcl" {}
+
+include "outputs/*.tfdoc.hcl" {}
+
+section {
+  title = "About"
+}
+
+references {
+  ref "homepage" {
+    value = "https://example.com"
+  }
+}
+`,
+		"inputs.tfdoc.hcl": `
+section {
+  title = "Inputs"
+
+  variable "name" {
+    type = string
+  }
+}
+
+references {
+  ref "terraform" {
+    value = "https://www.terraform.io"
+  }
+}
+`,
+		"outputs/b.tfdoc.hcl": `
+section {
+  title = "Other Outputs"
+}
+`,
+		"outputs/a.tfdoc.hcl": `
+section {
+  title = "Outputs"
+
+  output "id" {
+    type = string
+  }
+}
+`,
+	})
+
+	filename := filepath.Join(dir, "README.tfdoc.hcl")
+
+	got, err := docparser.Parse(strings.NewReader(readFile(t, filename)), filename)
+	assert.NoError(t, err)
+
+	var titles []string
+	for _, section := range got.Sections {
+		titles = append(titles, section.Title)
+	}
+
+	assert.EqualStrings(t, "Introduction, Inputs, Outputs, Other Outputs, About", strings.Join(titles, ", "))
+	assert.EqualStrings(t, "https://example.com/logo.png", got.Header.Image)
+
+	assert.EqualInts(t, 2, len(got.References))
+	assert.EqualStrings(t, "homepage", got.References[0].Name)
+	assert.EqualStrings(t, "terraform", got.References[1].Name)
+
+	variables := got.AllVariables()
+	assert.EqualInts(t, 1, len(variables))
+	assert.EqualStrings(t, filepath.Join(dir, "inputs.tfdoc.hcl"), variables[0].DefRange.Filename)
+	assert.EqualStrings(t, filepath.Join(dir, "outputs", "a.tfdoc.hcl"), got.Sections[2].DefRange.Filename)
+}
+
+func TestParseInvalidIncludes(t *testing.T) {
+	for _, tt := range []struct {
+		desc                 string
+		files                map[string]string
+		wantErrorMsgContains string
+		wantPos              string
+	}{
+		{
+			desc:                 "missing file",
+			files:                map[string]string{"README.tfdoc.hcl": `include "missing.tfdoc.hcl" {}`},
+			wantErrorMsgContains: `Included file not found; "missing.tfdoc.hcl" matches no files`,
+			wantPos:              "README.tfdoc.hcl:1:9",
+		},
+		{
+			desc: "include cycle",
+			files: map[string]string{
+				"README.tfdoc.hcl": `include "a.tfdoc.hcl" {}`,
+				"a.tfdoc.hcl":      `include "README.tfdoc.hcl" {}`,
+			},
+			wantErrorMsgContains: "Include cycle",
+			wantPos:              "a.tfdoc.hcl:1:9",
+		},
+		{
+			desc: "duplicate variable",
+			files: map[string]string{
+				"README.tfdoc.hcl": "section {\n  variable \"name\" {\n    type = string\n  }\n}\n\ninclude \"a.tfdoc.hcl\" {}\n",
+				"a.tfdoc.hcl":      "section {\n  section {\n    variable \"name\" {\n      type = string\n    }\n  }\n}\n",
+			},
+			wantErrorMsgContains: `Duplicate variable; variable "name" is already documented at README.tfdoc.hcl:2:3`,
+			wantPos:              "a.tfdoc.hcl:3:5",
+		},
+		{
+			desc: "duplicate output",
+			files: map[string]string{
+				"README.tfdoc.hcl":    "include \"*-outputs.tfdoc.hcl\" {}\n",
+				"a-outputs.tfdoc.hcl": "section {\n  output \"id\" {\n    type = string\n  }\n}\n",
+				"b-outputs.tfdoc.hcl": "section {\n  output \"id\" {\n    type = string\n  }\n}\n",
+			},
+			wantErrorMsgContains: `Duplicate output; output "id" is already documented at a-outputs.tfdoc.hcl:2:3`,
+			wantPos:              "b-outputs.tfdoc.hcl:2:3",
+		},
+		{
+			desc: "duplicate header",
+			files: map[string]string{
+				"README.tfdoc.hcl": "header {\n  image = \"a\"\n}\n\ninclude \"a.tfdoc.hcl\" {}\n",
+				"a.tfdoc.hcl":      "header {\n  image = \"b\"\n}\n",
+			},
+			wantErrorMsgContains: "Duplicate header",
+			wantPos:              "README.tfdoc.hcl:5:1",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			dir := writeDocs(t, tt.files)
+
+			// relative paths keep the positions short
+			wd, err := os.Getwd()
+			assert.NoError(t, err)
+			assert.NoError(t, os.Chdir(dir))
+			defer os.Chdir(wd)
+
+			_, err = docparser.Parse(strings.NewReader(tt.files["README.tfdoc.hcl"]), "README.tfdoc.hcl")
+			assert.Error(t, err)
+
+			if !strings.Contains(err.Error(), tt.wantErrorMsgContains) {
+				t.Errorf("Expected error message to contain %q but got %q instead", tt.wantErrorMsgContains, err.Error())
+			}
+
+			diags, ok := diagnostics.FromError(err)
+			if !ok {
+				t.Fatalf("Expected error to have diagnostics, got %q instead", err.Error())
+			}
+
+			assert.EqualStrings(t, tt.wantPos, diags[0].Pos())
+		})
+	}
+}
+
+func writeDocs(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	return dir
+}
+
+func readFile(t *testing.T, filename string) string {
+	t.Helper()
+
+	content, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+
+	return string(content)
+}
+
+func TestParseReferenceUses(t *testing.T) {
+	const content = `section {
+  title   = "Docs of ${ref.Name}"
+  content = <<-END
+    See the [docs][homepage], the [guide][] and the [changelog].
+    Escaped \[text] and inline [links](https://example.com) are no references.
+    Code ` + "`[a][b]`" + ` is skipped:
+    ` + "```" + `
+    [c][d]
+    ` + "```" + `
+  END
+
+  variable "name" {
+    type           = string
+    description    = "Visit ${ref.homepage}"
+    readme_example = "name = \"[e][f]\""
+  }
+}
+
+references {
+  ref "homepage" {
+    value = "https://example.com"
+  }
+
+  ref "Name" {
+    value = "terradoc"
+  }
+}
+`
+
+	got, err := docparser.Parse(bytes.NewBufferString(content), "foo-file")
+	assert.NoError(t, err)
+
+	assert.EqualStrings(t, "Docs of terradoc", got.Sections[0].Title)
+	assert.EqualStrings(t, "Visit https://example.com", got.AllVariables()[0].Description)
+
+	want := []struct {
+		name     string
+		shortcut bool
+		pos      string
+	}{
+		{name: "Name", pos: "foo-file:2:24"},
+		{name: "homepage", pos: "foo-file:4:13"},
+		{name: "guide", pos: "foo-file:4:35"},
+		{name: "changelog", shortcut: true, pos: "foo-file:4:53"},
+		{name: "homepage", pos: "foo-file:14:31"},
+		{name: "f", pos: "foo-file:15:32"},
+	}
+
+	assert.EqualInts(t, len(want), len(got.ReferenceUses))
+
+	for i, use := range got.ReferenceUses {
+		assert.EqualStrings(t, want[i].name, use.Name)
+		assert.EqualStrings(t, want[i].pos, diagnostics.New("", use.Range, "", "").Pos())
+
+		if use.Shortcut != want[i].shortcut {
+			t.Errorf("wanted shortcut %t for use of %q, got %t", want[i].shortcut, use.Name, use.Shortcut)
+		}
+	}
+}
+
+func TestParseUndefinedReference(t *testing.T) {
+	const content = `section {
+  title   = "test"
+  content = "See ${ref.missing}."
+}
+`
+
+	_, err := docparser.Parse(bytes.NewBufferString(content), "foo-file")
+	assert.Error(t, err)
+
+	diags, ok := diagnostics.FromError(err)
+	if !ok {
+		t.Fatalf("Expected error to have diagnostics, got %q instead", err.Error())
+	}
+
+	assert.EqualStrings(t, diagnostics.CodeUndefinedReference, diags[0].Code)
+	assert.EqualStrings(t, "foo-file:3:20", diags[0].Pos())
+}
+
+func TestParseUnsupportedReference(t *testing.T) {
+	const content = `header {
+  image = "https://example.com/logo.png"
+  url   = "${ref.home}"
+}
+
+references {
+  ref "home" {
+    value = "https://example.com"
+  }
+}
+`
+
+	_, err := docparser.Parse(bytes.NewBufferString(content), "foo-file")
+	assert.Error(t, err)
+
+	diags, ok := diagnostics.FromError(err)
+	if !ok {
+		t.Fatalf("Expected error to have diagnostics, got %q instead", err.Error())
+	}
+
+	assert.EqualStrings(t, "Unsupported reference", diags[0].Summary)
+	assert.EqualStrings(t, "foo-file:3:14", diags[0].Pos())
+}
+
+func TestParseLocals(t *testing.T) {
+	dir := writeDocs(t, map[string]string{
+		"README.tfdoc.hcl": `locals {
+  usage  = "source = \"${local.source}\""
+  source = "${module.source}?ref=${module.version}"
+}
+
+section {
+  title   = "${module.name} ${module.version}"
+  content = "Requires Terraform ${terraform.required_version}."
+
+  variable "name" {
+    type           = string
+    readme_example = local.usage
+  }
+}
+
+include "outputs.tfdoc.hcl" {}
+`,
+		"outputs.tfdoc.hcl": `locals {
+  suffix = "of ${module.name}"
+}
+
+section {
+  title = "Outputs ${local.suffix}"
+
+  output "id" {
+    type        = string
+    description = "The id, see ${local.source}"
+  }
+}
+`,
+		"main.tf": "terraform {\n  required_version = \">= 1.0\"\n}\n",
+	})
+
+	filename := filepath.Join(dir, "README.tfdoc.hcl")
+	opts := docparser.Options{ModuleVersion: "v1.2.0", ModuleSource: "github.com/acme/bucket", Code: detect.Code(dir)}
+
+	got, err := docparser.ParseWithOptions(strings.NewReader(readFile(t, filename)), filename, opts)
+	assert.NoError(t, err)
+
+	assert.EqualStrings(t, filepath.Base(dir)+" v1.2.0", got.Sections[0].Title)
+	assert.EqualStrings(t, "Requires Terraform >= 1.0.", got.Sections[0].Content)
+	assert.EqualStrings(t, `source = "github.com/acme/bucket?ref=v1.2.0"`, got.AllVariables()[0].ReadmeExample)
+
+	// included documents use the locals of the including document
+	assert.EqualStrings(t, "Outputs of "+filepath.Base(dir), got.Sections[1].Title)
+	assert.EqualStrings(t, "The id, see github.com/acme/bucket?ref=v1.2.0", got.AllOutputs()[0].Description)
+
+	// the .tf files are not read when their contents are given
+	opts.Code = func(codeOpts validationparser.Options) entities.ValidationContents {
+		// documents without generated resources do not need them
+		if codeOpts.Resources {
+			t.Errorf("wanted resources not to be loaded")
+		}
+
+		return entities.ValidationContents{Requirements: entities.Requirements{TerraformVersion: ">= 1.5"}}
+	}
+
+	got, err = docparser.ParseWithOptions(strings.NewReader(readFile(t, filename)), filename, opts)
+	assert.NoError(t, err)
+	assert.EqualStrings(t, "Requires Terraform >= 1.5.", got.Sections[0].Content)
+}
+
+func TestParseInvalidLocals(t *testing.T) {
+	for _, tt := range []struct {
+		desc                 string
+		content              string
+		wantErrorMsgContains string
+		wantPos              string
+	}{
+		{
+			desc:                 "cycle",
+			content:              "locals {\n  a = local.b\n  b = local.a\n}\n",
+			wantErrorMsgContains: `Cycle in locals; local "a" depends on itself`,
+			wantPos:              "README.tfdoc.hcl:2:7",
+		},
+		{
+			desc:                 "duplicate",
+			content:              "locals {\n  a = 1\n}\n\nlocals {\n  a = 2\n}\n",
+			wantErrorMsgContains: `Duplicate local; local "a" is already defined`,
+			wantPos:              "README.tfdoc.hcl:6:3",
+		},
+		{
+			desc:                 "undefined local",
+			content:              "section {\n  title = local.missing\n}\n",
+			wantErrorMsgContains: "Unsupported attribute",
+			wantPos:              "README.tfdoc.hcl:2:16",
+		},
+		{
+			desc:                 "unknown module version",
+			content:              "section {\n  title = \"${module.version}\"\n}\n",
+			wantErrorMsgContains: "Unsupported attribute",
+			wantPos:              "README.tfdoc.hcl:2:20",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			dir := writeDocs(t, map[string]string{"README.tfdoc.hcl": tt.content})
+
+			// relative paths keep the positions short
+			wd, err := os.Getwd()
+			assert.NoError(t, err)
+			assert.NoError(t, os.Chdir(dir))
+			defer os.Chdir(wd)
+
+			_, err = docparser.Parse(strings.NewReader(tt.content), "README.tfdoc.hcl")
+			assert.Error(t, err)
+
+			if !strings.Contains(err.Error(), tt.wantErrorMsgContains) {
+				t.Errorf("Expected error message to contain %q but got %q instead", tt.wantErrorMsgContains, err.Error())
+			}
+
+			diags, ok := diagnostics.FromError(err)
+			if !ok {
+				t.Fatalf("Expected error to have diagnostics, got %q instead", err.Error())
+			}
+
+			assert.EqualStrings(t, tt.wantPos, diags[0].Pos())
+		})
+	}
+}
+
+func TestParseRequirements(t *testing.T) {
+	dir := writeDocs(t, map[string]string{
+		"README.tfdoc.hcl": `locals {
+  aws_version = ">= 4.0"
+}
+
+section {
+  title = "Requirements"
+
+  requirements {
+    terraform = ">= 1.0"
+
+    provider "aws" {
+      source  = "hashicorp/aws"
+      version = local.aws_version
+    }
+  }
+
+  section {
+    title = "From code"
+
+    requirements {}
+  }
+}
+`,
+		"versions.tf": `terraform {
+  required_version = ">= 1.3"
+
+  required_providers {
+    google = {
+      source  = "hashicorp/google"
+      version = "~> 4.0"
+    }
+  }
+}
+`,
+	})
+
+	filename := filepath.Join(dir, "README.tfdoc.hcl")
+
+	got, err := docparser.ParseWithOptions(strings.NewReader(readFile(t, filename)), filename, docparser.Options{Code: detect.Code(dir)})
+	assert.NoError(t, err)
+
+	documented := got.Sections[0].Requirements
+	if documented == nil {
+		t.Fatal("wanted the section to have requirements")
+	}
+
+	assert.EqualStrings(t, ">= 1.0", documented.TerraformVersion)
+	assert.EqualInts(t, 1, len(documented.Providers))
+	assert.EqualStrings(t, "aws", documented.Providers[0].Name)
+	assert.EqualStrings(t, "hashicorp/aws", documented.Providers[0].Source)
+	assert.EqualStrings(t, ">= 4.0", documented.Providers[0].Version)
+	assert.EqualInts(t, 11, documented.Providers[0].DefRange.Start.Line)
+
+	if documented.FromCode {
+		t.Error("wanted documented requirements not to be filled from the .tf files")
+	}
+
+	// empty requirements blocks are filled from the .tf files
+	fromCode := got.Sections[0].SubSections[0].Requirements
+	if fromCode == nil || !fromCode.FromCode {
+		t.Fatal("wanted empty requirements to be filled from the .tf files")
+	}
+
+	assert.EqualStrings(t, ">= 1.3", fromCode.TerraformVersion)
+	assert.EqualInts(t, 1, len(fromCode.Providers))
+	assert.EqualStrings(t, "google", fromCode.Providers[0].Name)
+	assert.EqualStrings(t, "~> 4.0", fromCode.Providers[0].Version)
+	assert.EqualStrings(t, filename, fromCode.DefRange.Filename)
+}
+
+func TestParseDuplicateRequirements(t *testing.T) {
+	const content = `section {
+  requirements {}
+  requirements {}
+}
+`
+
+	_, err := docparser.Parse(strings.NewReader(content), "README.tfdoc.hcl")
+	assert.Error(t, err)
+
+	diags, ok := diagnostics.FromError(err)
+	if !ok {
+		t.Fatalf("wanted diagnostics, got %v", err)
+	}
+
+	assert.EqualStrings(t, "Duplicate requirements", diags[0].Summary)
+	assert.EqualInts(t, 3, diags[0].Range.Start.Line)
+}
+
+func TestParseGeneratedResources(t *testing.T) {
+	dir := writeDocs(t, map[string]string{
+		"README.tfdoc.hcl": `section {
+  title     = "Resources"
+  generated = "resources"
+}
+`,
+		"versions.tf": `terraform {
+  required_providers {
+    acme = {
+      source = "registry.terraform.io/acme-corp/acme"
+    }
+    internal = {
+      source = "example.com/corp/internal"
+    }
+  }
+}
+`,
+		"main.tf": `resource "aws_s3_bucket" "this" {
+  count = 1
+}
+
+data "acme_thing" "this" {}
+
+resource "internal_thing" "this" {}
+
+module "vpc" {
+  source = "terraform-aws-modules/vpc/aws"
+}
+
+module "local" {
+  source = "./modules/local"
+}
+`,
+	})
+
+	filename := filepath.Join(dir, "README.tfdoc.hcl")
+
+	got, err := docparser.ParseWithOptions(strings.NewReader(readFile(t, filename)), filename, docparser.Options{Code: detect.Code(dir)})
+	assert.NoError(t, err)
+
+	resources := got.Sections[0].Resources
+	assert.EqualInts(t, 5, len(resources))
+
+	for i, want := range []struct {
+		address string
+		url     string
+	}{
+		{"aws_s3_bucket.this", "https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/s3_bucket"},
+		{"data.acme_thing.this", "https://registry.terraform.io/providers/acme-corp/acme/latest/docs/data-sources/thing"},
+		{"internal_thing.this", ""},
+		{"module.vpc", "https://registry.terraform.io/modules/terraform-aws-modules/vpc/aws/latest"},
+		{"module.local", ""},
+	} {
+		assert.EqualStrings(t, want.address, resources[i].Address())
+		assert.EqualStrings(t, want.url, resources[i].URL)
+	}
+}
+
+func TestParseUnsupportedGenerated(t *testing.T) {
+	const content = `section {
+  generated = "everything"
+}
+`
+
+	_, err := docparser.Parse(strings.NewReader(content), "README.tfdoc.hcl")
+	assert.Error(t, err)
+
+	diags, ok := diagnostics.FromError(err)
+	if !ok {
+		t.Fatalf("wanted diagnostics, got %v", err)
+	}
+
+	assert.EqualStrings(t, "Unsupported generated content", diags[0].Summary)
+	assert.EqualInts(t, 2, diags[0].Range.Start.Line)
+	assert.EqualInts(t, 15, diags[0].Range.Start.Column)
+}
@@ -0,0 +1,225 @@
+package docparser
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/parsers/validationparser"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Names of the variables available to the expressions of a document
+const (
+	localVariable     = "local"
+	moduleVariable    = "module"
+	terraformVariable = "terraform"
+)
+
+// Options sets the values of the expressions of a document that depend on its module. The parser
+// does not run git or read the .tf files of the module itself, values that are not set are left
+// out, so using them is an error.
+type Options struct {
+	// ModuleVersion is the value of `module.version`
+	ModuleVersion string
+	// ModuleSource is the value of `module.source`, e.g. `github.com/org/repo//modules/bucket`
+	ModuleSource string
+	// Code returns the blocks selected by its options of the module's .tf files. It is only called
+	// if a document uses them, e.g. resources are only parsed for sections generating them. The
+	// module has no requirements and resources if it is nil.
+	Code CodeLoader
+}
+
+// CodeLoader returns the contents of the .tf files of a module selected by opts
+type CodeLoader func(opts validationparser.Options) entities.ValidationContents
+
+// environment holds the values available to the expressions of a document and the documents it
+// includes. The values of the module are only set if a document uses them because they may need
+// the .tf files of the module.
+type environment struct {
+	opts      Options
+	dir       string
+	moduleSet bool
+	module    map[string]cty.Value
+	terraform map[string]cty.Value
+	// code has the requirements and, if codeResources is set, the resources declared in the .tf
+	// files, loaded on first use
+	code          *entities.ValidationContents
+	codeResources bool
+}
+
+func newEnvironment(filename string, opts Options) (*environment, error) {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return &environment{opts: opts, dir: filepath.Dir(abs)}, nil
+}
+
+// context returns the evaluation context of a document. The locals of the document are added to
+// the locals of the documents including it, which are returned along with the context.
+func (e *environment) context(f *hcl.File, localsBlocks hcl.Blocks, inherited map[string]cty.Value) (*hcl.EvalContext, map[string]cty.Value, error) {
+	if e.usedBy(f) {
+		e.setModule()
+	}
+
+	locals, err := e.evalLocals(localsBlocks, inherited)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return e.evalContext(locals), locals, nil
+}
+
+func (e *environment) evalContext(locals map[string]cty.Value) *hcl.EvalContext {
+	return &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			localVariable:     cty.ObjectVal(locals),
+			moduleVariable:    cty.ObjectVal(e.module),
+			terraformVariable: cty.ObjectVal(e.terraform),
+		},
+	}
+}
+
+// usedBy reports whether the values of the module are used by the expressions of a file
+func (e *environment) usedBy(f *hcl.File) bool {
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return false
+	}
+
+	used := false
+
+	walkAttributes(body, func(attr *hclsyntax.Attribute) {
+		for _, traversal := range attr.Expr.Variables() {
+			if root := traversal.RootName(); root == moduleVariable || root == terraformVariable {
+				used = true
+			}
+		}
+	})
+
+	return used
+}
+
+// evalLocals evaluates the attributes of `locals` blocks, which may refer to other locals in any
+// order
+func (e *environment) evalLocals(blocks hcl.Blocks, inherited map[string]cty.Value) (map[string]cty.Value, error) {
+	locals := make(map[string]cty.Value, len(inherited))
+	for name, val := range inherited {
+		locals[name] = val
+	}
+
+	pending := map[string]*hcl.Attribute{}
+
+	for _, blk := range blocks {
+		attrs, diags := blk.Body.JustAttributes()
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("parsing locals: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
+		}
+
+		for name, attr := range attrs {
+			if _, ok := locals[name]; ok || pending[name] != nil {
+				return nil, diagnostics.New(diagnostics.CodeSchema, attr.NameRange,
+					"Duplicate local", fmt.Sprintf("local %q is already defined", name))
+			}
+
+			pending[name] = attr
+		}
+	}
+
+	for len(pending) > 0 {
+		names := make([]string, 0, len(pending))
+		for name := range pending {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		evaluated := false
+
+		for _, name := range names {
+			attr := pending[name]
+			if dependsOn(attr.Expr, pending) {
+				continue
+			}
+
+			val, diags := attr.Expr.Value(e.evalContext(locals))
+			if diags.HasErrors() {
+				return nil, fmt.Errorf("evaluating local %q: %w", name, diagnostics.FromHCL(diagnostics.CodeInvalidValue, diags))
+			}
+
+			locals[name] = val
+			delete(pending, name)
+			evaluated = true
+		}
+
+		if !evaluated {
+			attr := pending[names[0]]
+			return nil, diagnostics.New(diagnostics.CodeInvalidValue, attr.Expr.Range(),
+				"Cycle in locals", fmt.Sprintf("local %q depends on itself", names[0]))
+		}
+	}
+
+	return locals, nil
+}
+
+// dependsOn reports whether an expression refers to one of the given locals
+func dependsOn(expr hcl.Expression, locals map[string]*hcl.Attribute) bool {
+	for _, traversal := range expr.Variables() {
+		if traversal.RootName() != localVariable || len(traversal) < 2 {
+			continue
+		}
+
+		if attr, ok := traversal[1].(hcl.TraverseAttr); ok && locals[attr.Name] != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setModule sets the values of the module. Values that are not known are left out, so using
+// them is an error.
+func (e *environment) setModule() {
+	if e.moduleSet {
+		return
+	}
+
+	e.moduleSet = true
+	e.module = map[string]cty.Value{"name": cty.StringVal(filepath.Base(e.dir))}
+	e.terraform = map[string]cty.Value{}
+
+	if e.opts.ModuleVersion != "" {
+		e.module["version"] = cty.StringVal(e.opts.ModuleVersion)
+	}
+
+	if e.opts.ModuleSource != "" {
+		e.module["source"] = cty.StringVal(e.opts.ModuleSource)
+	}
+
+	if requiredVersion := e.codeContents(false).Requirements.TerraformVersion; requiredVersion != "" {
+		e.terraform["required_version"] = cty.StringVal(requiredVersion)
+	}
+}
+
+// codeContents returns the requirements and, if resources is set, the resources declared in the
+// .tf files of the module
+func (e *environment) codeContents(resources bool) entities.ValidationContents {
+	if e.code != nil && (e.codeResources || !resources) {
+		return *e.code
+	}
+
+	code := entities.ValidationContents{}
+	if e.opts.Code != nil {
+		code = e.opts.Code(validationparser.Options{Requirements: true, Resources: resources})
+	}
+
+	e.code, e.codeResources = &code, resources
+
+	return code
+}
@@ -4,12 +4,13 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/parsers/hclparser"
 	"github.com/mineiros-io/terradoc/internal/schemas/docschema"
 )
 
-func parseHeader(headerBlocks hcl.Blocks) (entities.Header, error) {
+func parseHeader(headerBlocks hcl.Blocks, ctx *hcl.EvalContext) (entities.Header, error) {
 	switch {
 	case len(headerBlocks) == 0:
 		return entities.Header{}, nil
@@ -21,19 +22,19 @@ func parseHeader(headerBlocks hcl.Blocks) (entities.Header, error) {
 
 	headerContent, diags := headerBlock.Body.Content(docschema.HeaderSchema())
 	if diags.HasErrors() {
-		return entities.Header{}, fmt.Errorf("parsing Terradoc header: %v", diags.Errs())
+		return entities.Header{}, fmt.Errorf("parsing Terradoc header: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
 	}
 
-	header, err := createHeaderFromHCLAttributes(headerContent.Attributes)
+	header, err := createHeaderFromHCLAttributes(headerContent.Attributes, ctx)
 	if err != nil {
-		return entities.Header{}, fmt.Errorf("parsing header: %s", err)
+		return entities.Header{}, fmt.Errorf("parsing header: %w", err)
 	}
 
 	// parse `badge` blocks
 	for _, badgeBlk := range headerContent.Blocks.OfType(badgeBlockName) {
-		badge, err := parseBadge(badgeBlk)
+		badge, err := parseBadge(badgeBlk, ctx)
 		if err != nil {
-			return entities.Header{}, fmt.Errorf("parsing header badge: %s", err)
+			return entities.Header{}, fmt.Errorf("parsing header badge: %w", err)
 		}
 
 		header.Badges = append(header.Badges, badge)
@@ -42,30 +43,30 @@ func parseHeader(headerBlocks hcl.Blocks) (entities.Header, error) {
 	return header, nil
 }
 
-func parseBadge(badgeBlock *hcl.Block) (entities.Badge, error) {
+func parseBadge(badgeBlock *hcl.Block, ctx *hcl.EvalContext) (entities.Badge, error) {
 	// badge blocks are required to have a label as defined in the schema
 	name := badgeBlock.Labels[0]
 
 	badgeContent, diags := badgeBlock.Body.Content(docschema.BadgeSchema())
 	if diags.HasErrors() {
-		return entities.Badge{}, fmt.Errorf("parsing badge: %v", diags.Errs())
+		return entities.Badge{}, fmt.Errorf("parsing badge: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
 	}
 
-	return createBadgeFromHCLAttributes(badgeContent.Attributes, name)
+	return createBadgeFromHCLAttributes(badgeContent.Attributes, name, ctx)
 }
 
-func createHeaderFromHCLAttributes(attrs hcl.Attributes) (entities.Header, error) {
+func createHeaderFromHCLAttributes(attrs hcl.Attributes, ctx *hcl.EvalContext) (entities.Header, error) {
 	header := entities.Header{}
 
 	// image
-	image, err := hclparser.GetAttribute(attrs, imageAttributeName).String()
+	image, err := hclparser.GetAttribute(attrs, imageAttributeName).StringWithContext(ctx)
 	if err != nil {
 		return entities.Header{}, err
 	}
 	header.Image = image
 
 	// url
-	url, err := hclparser.GetAttribute(attrs, urlAttributeName).String()
+	url, err := hclparser.GetAttribute(attrs, urlAttributeName).StringWithContext(ctx)
 	if err != nil {
 		return entities.Header{}, err
 	}
@@ -74,25 +75,25 @@ func createHeaderFromHCLAttributes(attrs hcl.Attributes) (entities.Header, error
 	return header, nil
 }
 
-func createBadgeFromHCLAttributes(attrs hcl.Attributes, name string) (entities.Badge, error) {
+func createBadgeFromHCLAttributes(attrs hcl.Attributes, name string, ctx *hcl.EvalContext) (entities.Badge, error) {
 	badge := entities.Badge{Name: name}
 
 	// image
-	image, err := hclparser.GetAttribute(attrs, imageAttributeName).String()
+	image, err := hclparser.GetAttribute(attrs, imageAttributeName).StringWithContext(ctx)
 	if err != nil {
 		return entities.Badge{}, err
 	}
 	badge.Image = image
 
 	// url
-	url, err := hclparser.GetAttribute(attrs, urlAttributeName).String()
+	url, err := hclparser.GetAttribute(attrs, urlAttributeName).StringWithContext(ctx)
 	if err != nil {
 		return entities.Badge{}, err
 	}
 	badge.URL = url
 
 	// url
-	text, err := hclparser.GetAttribute(attrs, textAttributeName).String()
+	text, err := hclparser.GetAttribute(attrs, textAttributeName).StringWithContext(ctx)
 	if err != nil {
 		return entities.Badge{}, err
 	}
@@ -0,0 +1,151 @@
+package docparser
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/schemas/docschema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// parseInclude parses the documents matched by the path of an `include` block. The path is relative
+// to the including document and may be a glob pattern, whose matches are included in lexical order.
+func parseInclude(p *hclparse.Parser, blk *hcl.Block, filename string, parents []string, env *environment, locals map[string]cty.Value) (entities.Doc, error) {
+	if _, diags := blk.Body.Content(docschema.IncludeSchema()); diags.HasErrors() {
+		return entities.Doc{}, fmt.Errorf("parsing include: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
+	}
+
+	matches, err := includeMatches(blk, filename)
+	if err != nil {
+		return entities.Doc{}, diagnostics.New(diagnostics.CodeInvalidValue, blk.LabelRanges[0],
+			"Invalid include path", fmt.Sprintf("%q: %v", blk.Labels[0], err))
+	}
+
+	if len(matches) == 0 {
+		return entities.Doc{}, diagnostics.New(diagnostics.CodeInvalidValue, blk.LabelRanges[0],
+			"Included file not found", fmt.Sprintf("%q matches no files", blk.Labels[0]))
+	}
+
+	result := entities.Doc{}
+
+	for _, match := range matches {
+		abs, err := filepath.Abs(match)
+		if err != nil {
+			return entities.Doc{}, err
+		}
+
+		for _, parent := range parents {
+			if parent == abs {
+				return entities.Doc{}, diagnostics.New(diagnostics.CodeInvalidValue, blk.LabelRanges[0],
+					"Include cycle", fmt.Sprintf("%q includes a document that includes it", match))
+			}
+		}
+
+		f, diags := p.ParseHCLFile(match)
+		if diags.HasErrors() {
+			return entities.Doc{}, fmt.Errorf("parsing included file %q: %w", match, diagnostics.FromHCL(diagnostics.CodeSyntax, diags))
+		}
+
+		included, err := parseDoc(p, f, match, parents, env, locals)
+		if err != nil {
+			return entities.Doc{}, fmt.Errorf("parsing included file %q: %w", match, err)
+		}
+
+		if !isEmptyHeader(included.Header) && !isEmptyHeader(result.Header) {
+			return entities.Doc{}, diagnostics.New(diagnostics.CodeSchema, blk.DefRange,
+				"Duplicate header", fmt.Sprintf("more than one document included by %q has a header", blk.Labels[0]))
+		}
+
+		if !isEmptyHeader(included.Header) {
+			result.Header = included.Header
+		}
+
+		result.Sections = append(result.Sections, included.Sections...)
+		result.References = append(result.References, included.References...)
+	}
+
+	return result, nil
+}
+
+// checkDuplicates returns an error if a variable or output is documented more than once, e.g. in
+// two included files
+func checkDuplicates(doc entities.Doc) error {
+	var diags diagnostics.Diagnostics
+
+	variables := map[string]hcl.Range{}
+	for _, variable := range doc.AllVariables() {
+		if first, ok := variables[variable.Name]; ok {
+			diags = append(diags, duplicate(variableBlockName, variable.Name, variable.DefRange, first))
+			continue
+		}
+
+		variables[variable.Name] = variable.DefRange
+	}
+
+	outputs := map[string]hcl.Range{}
+	for _, output := range doc.AllOutputs() {
+		if first, ok := outputs[output.Name]; ok {
+			diags = append(diags, duplicate(outputBlockName, output.Name, output.DefRange, first))
+			continue
+		}
+
+		outputs[output.Name] = output.DefRange
+	}
+
+	if len(diags) > 0 {
+		return diags
+	}
+
+	return nil
+}
+
+func duplicate(blockType, name string, rng, first hcl.Range) diagnostics.Diagnostic {
+	return diagnostics.New(diagnostics.CodeSchema, rng,
+		fmt.Sprintf("Duplicate %s", blockType),
+		fmt.Sprintf("%s %q is already documented at %s:%d:%d", blockType, name, first.Filename, first.Start.Line, first.Start.Column))
+}
+
+// Includes returns the files included by the `include` blocks of a document but not the files
+// included by them. Documents that can not be parsed include no files.
+func Includes(filename string) ([]string, error) {
+	f, diags := hclparse.NewParser().ParseHCLFile(filename)
+	if diags.HasErrors() {
+		return nil, nil
+	}
+
+	content, _, diags := f.Body.PartialContent(docschema.RootSchema())
+	if diags.HasErrors() {
+		return nil, nil
+	}
+
+	var result []string
+
+	for _, blk := range content.Blocks.OfType(includeBlockName) {
+		matches, err := includeMatches(blk, filename)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, matches...)
+	}
+
+	return result, nil
+}
+
+// includeMatches returns the files matching the path of an `include` block in lexical order
+func includeMatches(blk *hcl.Block, filename string) ([]string, error) {
+	pattern := blk.Labels[0]
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(filepath.Dir(filename), pattern)
+	}
+
+	return filepath.Glob(pattern)
+}
+
+func isEmptyHeader(header entities.Header) bool {
+	return header.Image == "" && header.URL == "" && len(header.Badges) == 0
+}
@@ -1,20 +1,20 @@
 package docparser
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/parsers/hclparser"
 	"github.com/mineiros-io/terradoc/internal/schemas/docschema"
 )
 
-func parseOutputs(outputBlocks hcl.Blocks) (outputs []entities.Output, err error) {
+func parseOutputs(outputBlocks hcl.Blocks, ctx *hcl.EvalContext) (outputs []entities.Output, err error) {
 	for _, outputBlk := range outputBlocks {
-		output, err := parseOutput(outputBlk)
+		output, err := parseOutput(outputBlk, ctx)
 		if err != nil {
-			return nil, fmt.Errorf("parsing output: %s", err)
+			return nil, fmt.Errorf("parsing output: %w", err)
 		}
 
 		outputs = append(outputs, output)
@@ -23,32 +23,39 @@ func parseOutputs(outputBlocks hcl.Blocks) (outputs []entities.Output, err error
 	return outputs, nil
 }
 
-func parseOutput(outputBlock *hcl.Block) (entities.Output, error) {
+func parseOutput(outputBlock *hcl.Block, ctx *hcl.EvalContext) (entities.Output, error) {
 	if len(outputBlock.Labels) != 1 {
-		return entities.Output{}, errors.New("output block does not have a name")
+		return entities.Output{}, diagnostics.New(diagnostics.CodeSchema, outputBlock.DefRange, "output block does not have a name", "")
 	}
 
 	outputContent, diags := outputBlock.Body.Content(docschema.OutputSchema())
 	if diags.HasErrors() {
-		return entities.Output{}, fmt.Errorf("parsing output: %v", diags.Errs())
+		return entities.Output{}, fmt.Errorf("parsing output: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
 	}
 
 	// output blocks are required to have a label as defined in the schema
 	name := outputBlock.Labels[0]
-	output, err := createOutputFromHCLAttributes(outputContent.Attributes, name)
+	output, err := createOutputFromHCLAttributes(outputContent.Attributes, name, ctx)
 	if err != nil {
-		return entities.Output{}, fmt.Errorf("parsing output: %s", err)
+		return entities.Output{}, fmt.Errorf("parsing output: %w", err)
 	}
 
+	output.DefRange = outputBlock.DefRange
+
 	return output, nil
 }
 
-func createOutputFromHCLAttributes(attrs hcl.Attributes, name string) (entities.Output, error) {
+func createOutputFromHCLAttributes(attrs hcl.Attributes, name string, ctx *hcl.EvalContext) (entities.Output, error) {
 	var err error
 
 	output := entities.Output{Name: name}
 
-	output.Description, err = hclparser.GetAttribute(attrs, descriptionAttributeName).String()
+	output.Description, err = hclparser.GetAttribute(attrs, descriptionAttributeName).StringWithContext(ctx)
+	if err != nil {
+		return entities.Output{}, err
+	}
+
+	output.Sensitive, err = hclparser.GetAttribute(attrs, sensitiveAttributeName).Bool()
 	if err != nil {
 		return entities.Output{}, err
 	}
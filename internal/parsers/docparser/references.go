@@ -4,12 +4,13 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/parsers/hclparser"
 	"github.com/mineiros-io/terradoc/internal/schemas/docschema"
 )
 
-func parseReferences(referencesBlocks hcl.Blocks) ([]entities.Reference, error) {
+func parseReferences(referencesBlocks hcl.Blocks, ctx *hcl.EvalContext) ([]entities.Reference, error) {
 	switch {
 	case len(referencesBlocks) == 0:
 		return nil, nil
@@ -20,15 +21,15 @@ func parseReferences(referencesBlocks hcl.Blocks) ([]entities.Reference, error)
 
 	referencesContent, diags := referencesBlocks[0].Body.Content(docschema.ReferencesSchema())
 	if diags.HasErrors() {
-		return nil, fmt.Errorf("parsing references: %v", diags.Errs())
+		return nil, fmt.Errorf("parsing references: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
 	}
 
-	return parseRefs(referencesContent.Blocks.OfType(refBlockName))
+	return parseRefs(referencesContent.Blocks.OfType(refBlockName), ctx)
 }
 
-func parseRefs(refBlocks hcl.Blocks) (refs []entities.Reference, err error) {
+func parseRefs(refBlocks hcl.Blocks, ctx *hcl.EvalContext) (refs []entities.Reference, err error) {
 	for _, refBlock := range refBlocks {
-		ref, err := parseRef(refBlock)
+		ref, err := parseRef(refBlock, ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -39,19 +40,19 @@ func parseRefs(refBlocks hcl.Blocks) (refs []entities.Reference, err error) {
 	return refs, nil
 }
 
-func parseRef(refBlock *hcl.Block) (entities.Reference, error) {
+func parseRef(refBlock *hcl.Block, ctx *hcl.EvalContext) (entities.Reference, error) {
 	// reference blocks are required to have a label as defined in the schema
 	name := refBlock.Labels[0]
 
 	refContent, diags := refBlock.Body.Content(docschema.RefSchema())
 	if diags.HasErrors() {
-		return entities.Reference{}, fmt.Errorf("parsing Terradoc `references`: %v", diags.Errs())
+		return entities.Reference{}, fmt.Errorf("parsing Terradoc `references`: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
 	}
 
-	value, err := hclparser.GetAttribute(refContent.Attributes, valueAttributeName).String()
+	value, err := hclparser.GetAttribute(refContent.Attributes, valueAttributeName).StringWithContext(ctx)
 	if err != nil {
 		return entities.Reference{}, err
 	}
 
-	return entities.Reference{Name: name, Value: value}, nil
+	return entities.Reference{Name: name, Value: value, DefRange: refBlock.DefRange}, nil
 }
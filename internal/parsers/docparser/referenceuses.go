@@ -0,0 +1,314 @@
+package docparser
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/parsers/hclparser"
+)
+
+// markdownAttributes are the attributes whose markdown is scanned for reference-style links
+var markdownAttributes = map[string]bool{
+	contentAttributeName:       true,
+	descriptionAttributeName:   true,
+	readmeExampleAttributeName: true,
+}
+
+// interpolatingAttributes are the attributes whose references are interpolated, e.g. `${ref.name}`
+var interpolatingAttributes = map[string]bool{
+	titleAttributeName:         true,
+	contentAttributeName:       true,
+	descriptionAttributeName:   true,
+	readmeExampleAttributeName: true,
+}
+
+// referenceLinkPattern matches the full `[text][label]`, collapsed `[label][]` and shortcut
+// `[label]` reference links. The escaping backslash and the character following the link are
+// matched to skip escaped brackets, inline links and link definitions.
+var referenceLinkPattern = regexp.MustCompile(`\\?\[([^\[\]\n]+)\](?:\[([^\[\]\n]*)\])?[(:]?`)
+
+// parseReferenceUses returns the references used in the given files ordered by their position.
+// Interpolations of undefined references and interpolations in other attributes than the
+// interpolatingAttributes are errors because they can not be expanded.
+func parseReferenceUses(files map[string]*hcl.File, refs []entities.Reference) ([]entities.ReferenceUse, error) {
+	filenames := make([]string, 0, len(files))
+	for filename := range files {
+		filenames = append(filenames, filename)
+	}
+
+	sort.Strings(filenames)
+
+	defined := map[string]bool{}
+	for _, ref := range refs {
+		defined[ref.Name] = true
+	}
+
+	var (
+		uses  []entities.ReferenceUse
+		diags diagnostics.Diagnostics
+	)
+
+	for _, filename := range filenames {
+		f := files[filename]
+
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+
+		walkAttributes(body, func(attr *hclsyntax.Attribute) {
+			if !interpolatingAttributes[attr.Name] {
+				// the placeholders of references are only expanded in the interpolating attributes
+				for _, traversal := range attr.Expr.Variables() {
+					if _, ok := hclparser.ReferenceName(traversal); ok {
+						diags = append(diags, diagnostics.New(diagnostics.CodeInvalidValue, traversal.SourceRange(),
+							"Unsupported reference", fmt.Sprintf("references can not be interpolated in %q, only in titles, contents, descriptions and readme examples", attr.Name)))
+					}
+				}
+
+				return
+			}
+
+			for _, traversal := range attr.Expr.Variables() {
+				name, ok := hclparser.ReferenceName(traversal)
+				if !ok {
+					continue
+				}
+
+				if !defined[name] {
+					diags = append(diags, diagnostics.New(diagnostics.CodeUndefinedReference, traversal.SourceRange(),
+						"Undefined reference", fmt.Sprintf("%q is not defined in the `references` block", name)))
+					continue
+				}
+
+				uses = append(uses, entities.ReferenceUse{Name: name, Range: traversal.SourceRange()})
+			}
+
+			if markdownAttributes[attr.Name] {
+				uses = append(uses, referenceLinks(f.Bytes, attr.Expr.Range())...)
+			}
+		})
+	}
+
+	if len(diags) > 0 {
+		return nil, diags
+	}
+
+	sort.SliceStable(uses, func(i, j int) bool {
+		a, b := uses[i].Range, uses[j].Range
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+
+		return a.Start.Byte < b.Start.Byte
+	})
+
+	return uses, nil
+}
+
+// walkAttributes calls fn for the attributes of body and of its nested blocks
+func walkAttributes(body *hclsyntax.Body, fn func(*hclsyntax.Attribute)) {
+	names := make([]string, 0, len(body.Attributes))
+	for name := range body.Attributes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		fn(body.Attributes[name])
+	}
+
+	for _, blk := range body.Blocks {
+		walkAttributes(blk.Body, fn)
+	}
+}
+
+// referenceLinks returns the reference-style links in the source of an expression. Links in
+// code blocks and code spans are ignored.
+func referenceLinks(src []byte, rng hcl.Range) []entities.ReferenceUse {
+	text := maskCode(rng.SliceBytes(src))
+
+	var uses []entities.ReferenceUse
+
+	for _, m := range referenceLinkPattern.FindAllSubmatchIndex(text, -1) {
+		link := text[m[0]:m[1]]
+		if link[0] == '\\' || link[len(link)-1] == '(' || link[len(link)-1] == ':' {
+			continue
+		}
+
+		use := entities.ReferenceUse{Name: string(text[m[2]:m[3]])}
+
+		switch {
+		case m[4] < 0:
+			use.Shortcut = true
+		case m[5] > m[4]:
+			use.Name = string(text[m[4]:m[5]])
+		}
+
+		use.Name = strings.TrimSpace(use.Name)
+		use.Range = hcl.Range{
+			Filename: rng.Filename,
+			Start:    posAt(src, rng.Start.Byte+m[0]),
+			End:      posAt(src, rng.Start.Byte+m[1]),
+		}
+
+		uses = append(uses, use)
+	}
+
+	return uses
+}
+
+// maskCode returns a copy of the markdown text with fenced code blocks and code spans replaced by
+// spaces, keeping the offsets of the remaining text
+func maskCode(text []byte) []byte {
+	masked := make([]byte, len(text))
+	copy(masked, text)
+
+	inFence := false
+	start := 0
+
+	for start < len(masked) {
+		end := bytes.IndexByte(masked[start:], '\n')
+		if end < 0 {
+			end = len(masked)
+		} else {
+			end += start
+		}
+
+		line := bytes.TrimLeft(masked[start:end], " \t")
+		isFence := bytes.HasPrefix(line, []byte("```")) || bytes.HasPrefix(line, []byte("~~~"))
+
+		if inFence || isFence {
+			blank(masked[start:end])
+		} else {
+			maskCodeSpans(masked[start:end])
+		}
+
+		if isFence {
+			inFence = !inFence
+		}
+
+		start = end + 1
+	}
+
+	return masked
+}
+
+// maskCodeSpans replaces the code spans of a line, which are delimited by backtick strings of the
+// same length, by spaces
+func maskCodeSpans(line []byte) {
+	for i := 0; i < len(line); {
+		if line[i] != '`' {
+			i++
+			continue
+		}
+
+		n := countBackticks(line[i:])
+		closing := -1
+
+		for j := i + n; j < len(line); {
+			if line[j] != '`' {
+				j++
+				continue
+			}
+
+			m := countBackticks(line[j:])
+			if m == n {
+				closing = j
+				break
+			}
+
+			j += m
+		}
+
+		if closing < 0 {
+			i += n
+			continue
+		}
+
+		blank(line[i : closing+n])
+		i = closing + n
+	}
+}
+
+func countBackticks(text []byte) int {
+	n := 0
+	for n < len(text) && text[n] == '`' {
+		n++
+	}
+
+	return n
+}
+
+func blank(text []byte) {
+	for i := range text {
+		text[i] = ' '
+	}
+}
+
+// posAt returns the position of a byte offset in src
+func posAt(src []byte, offset int) hcl.Pos {
+	lineStart := bytes.LastIndexByte(src[:offset], '\n') + 1
+
+	return hcl.Pos{
+		Line:   bytes.Count(src[:offset], []byte("\n")) + 1,
+		Column: utf8.RuneCount(src[lineStart:offset]) + 1,
+		Byte:   offset,
+	}
+}
+
+// expandReferences replaces the interpolated references of the document by their values
+func expandReferences(doc *entities.Doc) {
+	if len(doc.References) == 0 {
+		return
+	}
+
+	pairs := make([]string, 0, 2*len(doc.References))
+	for _, ref := range doc.References {
+		pairs = append(pairs, hclparser.ReferencePlaceholder(ref.Name), ref.Value)
+	}
+
+	r := strings.NewReplacer(pairs...)
+
+	for i := range doc.Sections {
+		expandSectionReferences(r, &doc.Sections[i])
+	}
+}
+
+func expandSectionReferences(r *strings.Replacer, section *entities.Section) {
+	section.Title = r.Replace(section.Title)
+	section.Content = r.Replace(section.Content)
+
+	for i := range section.Variables {
+		variable := &section.Variables[i]
+		variable.Description = r.Replace(variable.Description)
+		variable.ReadmeExample = r.Replace(variable.ReadmeExample)
+		expandAttributeReferences(r, variable.Attributes)
+	}
+
+	for i := range section.Outputs {
+		section.Outputs[i].Description = r.Replace(section.Outputs[i].Description)
+	}
+
+	for i := range section.SubSections {
+		expandSectionReferences(r, &section.SubSections[i])
+	}
+}
+
+func expandAttributeReferences(r *strings.Replacer, attributes []entities.Attribute) {
+	for i := range attributes {
+		attr := &attributes[i]
+		attr.Description = r.Replace(attr.Description)
+		attr.ReadmeExample = r.Replace(attr.ReadmeExample)
+		expandAttributeReferences(r, attr.Attributes)
+	}
+}
@@ -0,0 +1,97 @@
+package docparser
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/parsers/hclparser"
+	"github.com/mineiros-io/terradoc/internal/schemas/docschema"
+)
+
+func parseRequirements(requirementsBlocks hcl.Blocks, ctx *hcl.EvalContext) (*entities.Requirements, error) {
+	switch {
+	case len(requirementsBlocks) == 0:
+		return nil, nil
+	case len(requirementsBlocks) != 1:
+		return nil, diagnostics.New(diagnostics.CodeSchema, requirementsBlocks[1].DefRange,
+			"Duplicate requirements", "a section can have at most 1 `requirements` block")
+	}
+
+	blk := requirementsBlocks[0]
+
+	requirementsContent, diags := blk.Body.Content(docschema.RequirementsSchema())
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("parsing requirements: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
+	}
+
+	terraformVersion, err := hclparser.GetAttribute(requirementsContent.Attributes, terraformAttributeName).StringWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requirements := &entities.Requirements{TerraformVersion: terraformVersion, DefRange: blk.DefRange}
+
+	for _, providerBlk := range requirementsContent.Blocks.OfType(providerBlockName) {
+		provider, err := parseProviderRequirement(providerBlk, ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := requirements.Provider(provider.Name); ok {
+			return nil, diagnostics.New(diagnostics.CodeSchema, providerBlk.DefRange,
+				"Duplicate provider", fmt.Sprintf("provider %q is already documented", provider.Name))
+		}
+
+		requirements.Providers = append(requirements.Providers, provider)
+	}
+
+	return requirements, nil
+}
+
+func parseProviderRequirement(providerBlk *hcl.Block, ctx *hcl.EvalContext) (entities.ProviderRequirement, error) {
+	// provider blocks are required to have a label as defined in the schema
+	provider := entities.ProviderRequirement{Name: providerBlk.Labels[0], DefRange: providerBlk.DefRange}
+
+	providerContent, diags := providerBlk.Body.Content(docschema.ProviderRequirementSchema())
+	if diags.HasErrors() {
+		return entities.ProviderRequirement{}, fmt.Errorf("parsing provider: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
+	}
+
+	var err error
+
+	provider.Source, err = hclparser.GetAttribute(providerContent.Attributes, sourceAttributeName).StringWithContext(ctx)
+	if err != nil {
+		return entities.ProviderRequirement{}, err
+	}
+
+	provider.Version, err = hclparser.GetAttribute(providerContent.Attributes, versionAttributeName).StringWithContext(ctx)
+	if err != nil {
+		return entities.ProviderRequirement{}, err
+	}
+
+	return provider, nil
+}
+
+// fillRequirements sets the empty `requirements` blocks of a document to the requirements
+// declared in the .tf files of the module
+func fillRequirements(doc *entities.Doc, env *environment) {
+	for i := range doc.Sections {
+		fillSectionRequirements(&doc.Sections[i], env)
+	}
+}
+
+func fillSectionRequirements(section *entities.Section, env *environment) {
+	if section.Requirements != nil && section.Requirements.IsEmpty() {
+		requirements := env.codeContents(false).Requirements
+		requirements.FromCode = true
+		requirements.DefRange = section.Requirements.DefRange
+
+		section.Requirements = &requirements
+	}
+
+	for i := range section.SubSections {
+		fillSectionRequirements(&section.SubSections[i], env)
+	}
+}
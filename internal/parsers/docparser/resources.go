@@ -0,0 +1,125 @@
+package docparser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/parsers/hclparser"
+)
+
+// generatedResources is the value of the `generated` attribute of sections listing the resources
+// of the module
+const generatedResources = "resources"
+
+// parseGenerated returns the content a section generates from the .tf files of the module
+func parseGenerated(attrs hcl.Attributes, ctx *hcl.EvalContext) (string, error) {
+	attr := hclparser.GetAttribute(attrs, generatedAttributeName)
+
+	generated, err := attr.StringWithContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	switch generated {
+	case "", generatedResources:
+		return generated, nil
+	}
+
+	return "", diagnostics.New(diagnostics.CodeInvalidValue, attr.Expr.Range(),
+		"Unsupported generated content", fmt.Sprintf("%q can not be generated, the supported value is %q", generated, generatedResources))
+}
+
+// fillResources sets the resources of the sections generating them to the `resource`, `data` and
+// `module` blocks declared in the .tf files of the module
+func fillResources(doc *entities.Doc, env *environment) {
+	for i := range doc.Sections {
+		fillSectionResources(&doc.Sections[i], env)
+	}
+}
+
+func fillSectionResources(section *entities.Section, env *environment) {
+	if section.Generated == generatedResources {
+		code := env.codeContents(true)
+
+		section.Resources = make([]entities.Resource, len(code.Resources))
+
+		for i, resource := range code.Resources {
+			resource.URL = resourceURL(resource, code.Requirements)
+			section.Resources[i] = resource
+		}
+	}
+
+	for i := range section.SubSections {
+		fillSectionResources(&section.SubSections[i], env)
+	}
+}
+
+// resourceURL returns the Terraform Registry documentation of a resource type, a data source or a
+// module. Providers and modules that are not published in the public registry have no URL.
+func resourceURL(resource entities.Resource, requirements entities.Requirements) string {
+	if resource.Kind == entities.ModuleKind {
+		return moduleURL(resource.Source)
+	}
+
+	provider, ok := requirements.Provider(resource.Provider)
+	if !ok {
+		provider = entities.ProviderRequirement{Name: resource.Provider}
+	}
+
+	parts := strings.Split(provider.RegistrySource(), "/")
+	if len(parts) != 2 {
+		return ""
+	}
+
+	namespace, providerType := parts[0], parts[1]
+
+	docs := "resources"
+	if resource.Kind == entities.DataSourceKind {
+		docs = "data-sources"
+	}
+
+	return fmt.Sprintf("https://%s/providers/%s/%s/latest/docs/%s/%s",
+		entities.DefaultRegistry, namespace, providerType, docs, resourceDocName(resource.Type, providerType))
+}
+
+// resourceDocName returns the name of the documentation page of a resource type, which is the type
+// without the provider prefix, e.g. `s3_bucket` for `aws_s3_bucket`
+func resourceDocName(resourceType, providerType string) string {
+	if name := strings.TrimPrefix(resourceType, providerType+"_"); name != resourceType {
+		return name
+	}
+
+	if i := strings.Index(resourceType, "_"); i > 0 {
+		return resourceType[i+1:]
+	}
+
+	return resourceType
+}
+
+// moduleURL returns the registry page of a module source in the public registry, e.g.
+// `terraform-aws-modules/vpc/aws`
+func moduleURL(source string) string {
+	// sub-directories of a module package, e.g. `ns/name/provider//modules/x`, are documented with
+	// the package
+	if i := strings.Index(source, "//"); i >= 0 {
+		source = source[:i]
+	}
+
+	parts := strings.Split(strings.TrimPrefix(source, entities.DefaultRegistry+"/"), "/")
+	if len(parts) != 3 {
+		return ""
+	}
+
+	for _, part := range parts {
+		// local paths, hosts and go-getter sources, e.g. `./modules/x`, `github.com/org/repo` or
+		// `git::https://...`, are not registry addresses
+		if part == "" || strings.ContainsAny(part, ".:?") {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("https://%s/modules/%s/latest", entities.DefaultRegistry, strings.Join(parts, "/"))
+}
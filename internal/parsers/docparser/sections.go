@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/parsers/hclparser"
 	"github.com/mineiros-io/terradoc/internal/schemas/docschema"
@@ -13,11 +14,13 @@ const (
 	rootSectionLevel = 1
 )
 
-func parseSections(sectionBlocks hcl.Blocks) (sections []entities.Section, err error) {
+// parseSections parses the section blocks of the document. The source of the document is used to
+// keep expressions as written, e.g. the conditions of validation blocks.
+func parseSections(sectionBlocks hcl.Blocks, src []byte, ctx *hcl.EvalContext) (sections []entities.Section, err error) {
 	for _, sectionBlock := range sectionBlocks {
-		section, err := parseSection(sectionBlock, rootSectionLevel) // initial level
+		section, err := parseSection(sectionBlock, rootSectionLevel, src, ctx) // initial level
 		if err != nil {
-			return nil, fmt.Errorf("parsing sections: %s", err)
+			return nil, fmt.Errorf("parsing sections: %w", err)
 		}
 
 		sections = append(sections, section)
@@ -26,37 +29,46 @@ func parseSections(sectionBlocks hcl.Blocks) (sections []entities.Section, err e
 	return sections, nil
 }
 
-func parseSection(sectionBlock *hcl.Block, level int) (entities.Section, error) {
+func parseSection(sectionBlock *hcl.Block, level int, src []byte, ctx *hcl.EvalContext) (entities.Section, error) {
 	sectionContent, diags := sectionBlock.Body.Content(docschema.SectionSchema())
 	if diags.HasErrors() {
-		return entities.Section{}, fmt.Errorf("parsing Terradoc section: %v", diags.Errs())
+		return entities.Section{}, fmt.Errorf("parsing Terradoc section: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
 	}
 
-	section, err := createSectionFromHCLAttributes(sectionContent.Attributes, level)
+	section, err := createSectionFromHCLAttributes(sectionContent.Attributes, level, ctx)
 	if err != nil {
-		return entities.Section{}, fmt.Errorf("parsing section: %s", err)
+		return entities.Section{}, fmt.Errorf("parsing section: %w", err)
 	}
 
+	section.DefRange = sectionBlock.DefRange
+
 	// parse `variable` blocks
-	variables, err := parseVariables(sectionContent.Blocks.OfType(variableBlockName))
+	variables, err := parseVariables(sectionContent.Blocks.OfType(variableBlockName), src, ctx)
 	if err != nil {
-		return entities.Section{}, fmt.Errorf("parsing section variable: %v", err)
+		return entities.Section{}, fmt.Errorf("parsing section variable: %w", err)
 	}
 	section.Variables = variables
 
 	// parse `output` blocks
-	outputs, err := parseOutputs(sectionContent.Blocks.OfType(outputBlockName))
+	outputs, err := parseOutputs(sectionContent.Blocks.OfType(outputBlockName), ctx)
 	if err != nil {
-		return entities.Section{}, fmt.Errorf("parsing section variable: %v", err)
+		return entities.Section{}, fmt.Errorf("parsing section variable: %w", err)
 	}
 	section.Outputs = outputs
 
+	// parse `requirements` block
+	requirements, err := parseRequirements(sectionContent.Blocks.OfType(requirementsBlockName), ctx)
+	if err != nil {
+		return entities.Section{}, fmt.Errorf("parsing section requirements: %w", err)
+	}
+	section.Requirements = requirements
+
 	subSectionLevel := level + 1
 	// parse `section` blocks
 	for _, subSectionBlk := range sectionContent.Blocks.OfType(sectionBlockName) {
-		subSection, err := parseSection(subSectionBlk, subSectionLevel)
+		subSection, err := parseSection(subSectionBlk, subSectionLevel, src, ctx)
 		if err != nil {
-			return entities.Section{}, fmt.Errorf("parsing subsection: %s", err)
+			return entities.Section{}, fmt.Errorf("parsing subsection: %w", err)
 		}
 
 		section.SubSections = append(section.SubSections, subSection)
@@ -65,17 +77,17 @@ func parseSection(sectionBlock *hcl.Block, level int) (entities.Section, error)
 	return section, nil
 }
 
-func createSectionFromHCLAttributes(attrs hcl.Attributes, level int) (entities.Section, error) {
+func createSectionFromHCLAttributes(attrs hcl.Attributes, level int, ctx *hcl.EvalContext) (entities.Section, error) {
 	var err error
 
 	section := entities.Section{Level: level}
 
-	section.Title, err = hclparser.GetAttribute(attrs, titleAttributeName).String()
+	section.Title, err = hclparser.GetAttribute(attrs, titleAttributeName).StringWithContext(ctx)
 	if err != nil {
 		return entities.Section{}, err
 	}
 
-	section.Content, err = hclparser.GetAttribute(attrs, contentAttributeName).String()
+	section.Content, err = hclparser.GetAttribute(attrs, contentAttributeName).StringWithContext(ctx)
 	if err != nil {
 		return entities.Section{}, err
 	}
@@ -85,5 +97,10 @@ func createSectionFromHCLAttributes(attrs hcl.Attributes, level int) (entities.S
 		return entities.Section{}, err
 	}
 
+	section.Generated, err = parseGenerated(attrs, ctx)
+	if err != nil {
+		return entities.Section{}, err
+	}
+
 	return section, nil
 }
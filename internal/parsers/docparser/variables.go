@@ -1,20 +1,20 @@
 package docparser
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/parsers/hclparser"
 	"github.com/mineiros-io/terradoc/internal/schemas/docschema"
 )
 
-func parseVariables(variableBlocks hcl.Blocks) (variables []entities.Variable, err error) {
+func parseVariables(variableBlocks hcl.Blocks, src []byte, ctx *hcl.EvalContext) (variables []entities.Variable, err error) {
 	for _, varBlk := range variableBlocks {
-		variable, err := parseVariable(varBlk)
+		variable, err := parseVariable(varBlk, src, ctx)
 		if err != nil {
-			return nil, fmt.Errorf("parsing variable: %s", err)
+			return nil, fmt.Errorf("parsing variable: %w", err)
 		}
 
 		variables = append(variables, variable)
@@ -23,39 +23,49 @@ func parseVariables(variableBlocks hcl.Blocks) (variables []entities.Variable, e
 	return variables, nil
 }
 
-func parseVariable(variableBlock *hcl.Block) (entities.Variable, error) {
+func parseVariable(variableBlock *hcl.Block, src []byte, ctx *hcl.EvalContext) (entities.Variable, error) {
 	if len(variableBlock.Labels) != 1 {
-		return entities.Variable{}, errors.New("variable block does not have a name")
+		return entities.Variable{}, diagnostics.New(diagnostics.CodeSchema, variableBlock.DefRange, "variable block does not have a name", "")
 	}
 
 	variableContent, diags := variableBlock.Body.Content(docschema.VariableSchema())
 	if diags.HasErrors() {
-		return entities.Variable{}, fmt.Errorf("parsing variable: %v", diags.Errs())
+		return entities.Variable{}, fmt.Errorf("parsing variable: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
 	}
 
 	// variable blocks are required to have a label as defined in the schema
 	name := variableBlock.Labels[0]
-	variable, err := createVariableFromHCLAttributes(variableContent.Attributes, name)
+	variable, err := createVariableFromHCLAttributes(variableContent.Attributes, name, ctx)
 	if err != nil {
-		return entities.Variable{}, fmt.Errorf("parsing variable: %s", err)
+		return entities.Variable{}, fmt.Errorf("parsing variable: %w", err)
 	}
 
 	// variables have only `attribute` blocks
-	attributes, err := parseVariableAttributes(variableContent.Blocks.OfType(attributeBlockName))
+	attributes, err := parseVariableAttributes(variableContent.Blocks.OfType(attributeBlockName), ctx)
 	if err != nil {
-		return entities.Variable{}, fmt.Errorf("parsing variable attributes: %s", err)
+		return entities.Variable{}, fmt.Errorf("parsing variable attributes: %w", err)
 	}
 	variable.Attributes = attributes
+	variable.DefRange = variableBlock.DefRange
+
+	for _, blk := range variableContent.Blocks.OfType(validationBlockName) {
+		constraint, err := parseConstraint(blk, src)
+		if err != nil {
+			return entities.Variable{}, fmt.Errorf("parsing variable validation: %w", err)
+		}
+
+		variable.Constraints = append(variable.Constraints, constraint)
+	}
 
 	return variable, nil
 }
 
-func createVariableFromHCLAttributes(attrs hcl.Attributes, name string) (entities.Variable, error) {
+func createVariableFromHCLAttributes(attrs hcl.Attributes, name string, ctx *hcl.EvalContext) (entities.Variable, error) {
 	var err error
 
 	variable := entities.Variable{Name: name}
 
-	variable.Description, err = hclparser.GetAttribute(attrs, descriptionAttributeName).String()
+	variable.Description, err = hclparser.GetAttribute(attrs, descriptionAttributeName).StringWithContext(ctx)
 	if err != nil {
 		return entities.Variable{}, err
 	}
@@ -75,7 +85,25 @@ func createVariableFromHCLAttributes(attrs hcl.Attributes, name string) (entitie
 		return entities.Variable{}, err
 	}
 
-	variable.ReadmeExample, err = hclparser.GetAttribute(attrs, readmeExampleAttributeName).String()
+	variable.Sensitive, err = hclparser.GetAttribute(attrs, sensitiveAttributeName).Bool()
+	if err != nil {
+		return entities.Variable{}, err
+	}
+
+	variable.Nullable = true
+	if nullableAttr := hclparser.GetAttribute(attrs, nullableAttributeName); nullableAttr != nil {
+		variable.Nullable, err = nullableAttr.Bool()
+		if err != nil {
+			return entities.Variable{}, err
+		}
+	}
+
+	variable.Ephemeral, err = hclparser.GetAttribute(attrs, ephemeralAttributeName).Bool()
+	if err != nil {
+		return entities.Variable{}, err
+	}
+
+	variable.ReadmeExample, err = hclparser.GetAttribute(attrs, readmeExampleAttributeName).StringWithContext(ctx)
 	if err != nil {
 		return entities.Variable{}, err
 	}
@@ -94,3 +122,21 @@ func createVariableFromHCLAttributes(attrs hcl.Attributes, name string) (entitie
 
 	return variable, nil
 }
+
+func parseConstraint(validationBlock *hcl.Block, src []byte) (entities.Constraint, error) {
+	content, diags := validationBlock.Body.Content(docschema.ValidationSchema())
+	if diags.HasErrors() {
+		return entities.Constraint{}, fmt.Errorf("parsing validation block: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
+	}
+
+	errorMessage, err := hclparser.GetAttribute(content.Attributes, errorMessageAttributeName).Template(src)
+	if err != nil {
+		return entities.Constraint{}, err
+	}
+
+	return entities.Constraint{
+		Condition:    hclparser.GetAttribute(content.Attributes, conditionAttributeName).Source(src),
+		ErrorMessage: errorMessage,
+		DefRange:     validationBlock.DefRange,
+	}, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/convert"
@@ -17,20 +18,27 @@ type HCLAttribute struct {
 	*hcl.Attribute
 }
 
+// String returns the string value of the attribute. Interpolated references, e.g. `${ref.name}`,
+// are returned as their ReferencePlaceholder.
 func (a *HCLAttribute) String() (string, error) {
+	return a.StringWithContext(nil)
+}
+
+// StringWithContext returns the string value of the attribute evaluated with the variables of ctx
+func (a *HCLAttribute) StringWithContext(ctx *hcl.EvalContext) (string, error) {
 	if a == nil {
 		return "", nil
 	}
 
-	val, diags := a.Expr.Value(nil)
+	val, diags := a.Expr.Value(referenceContext(a.Expr, ctx))
 	if diags.HasErrors() {
-		return "", fmt.Errorf("getting string value for %q: %v", a.Name, diags.Errs())
+		return "", fmt.Errorf("getting string value for %q: %w", a.Name, diagnostics.FromHCL(diagnostics.CodeInvalidValue, diags))
 	}
 
 	// use cty's convert pkg to prevent panic if value is not a string
 	strVal, err := convert.Convert(val, cty.String)
 	if err != nil {
-		return "", fmt.Errorf("could not convert %q to string: %v", a.Name, err)
+		return "", a.invalidValue(fmt.Sprintf("could not convert %q to string", a.Name), err)
 	}
 
 	return strings.TrimSpace(strVal.AsString()), nil
@@ -43,18 +51,51 @@ func (a *HCLAttribute) Bool() (bool, error) {
 
 	val, diags := a.Expr.Value(nil)
 	if diags.HasErrors() {
-		return false, fmt.Errorf("fetching bool value for %q: %v", a.Name, diags.Errs())
+		return false, fmt.Errorf("fetching bool value for %q: %w", a.Name, diagnostics.FromHCL(diagnostics.CodeInvalidValue, diags))
 	}
 
 	// use cty's convert pkg to prevent panic if value is not a bool
 	boolVal, err := convert.Convert(val, cty.Bool)
 	if err != nil {
-		return false, fmt.Errorf("could not convert %q to bool: %s", a.Name, err)
+		return false, a.invalidValue(fmt.Sprintf("could not convert %q to bool", a.Name), err)
 	}
 
 	return boolVal.True(), nil
 }
 
+// Source returns the source text of the attribute expression in src
+func (a *HCLAttribute) Source(src []byte) string {
+	if a == nil {
+		return ""
+	}
+
+	return string(a.Expr.Range().SliceBytes(src))
+}
+
+// Template returns the value of a string template. Templates that can not be evaluated without the
+// Terraform context, e.g. `"${var.name} is invalid"`, are returned as written in src.
+func (a *HCLAttribute) Template(src []byte) (string, error) {
+	if a == nil {
+		return "", nil
+	}
+
+	if len(a.Expr.Variables()) > 0 {
+		source := strings.TrimSpace(a.Source(src))
+		if unquoted := strings.TrimSuffix(strings.TrimPrefix(source, `"`), `"`); len(unquoted) == len(source)-2 {
+			return unquoted, nil
+		}
+
+		return source, nil
+	}
+
+	return a.String()
+}
+
+// invalidValue returns a diagnostic for the value of the attribute
+func (a *HCLAttribute) invalidValue(summary string, err error) diagnostics.Diagnostic {
+	return diagnostics.New(diagnostics.CodeInvalidValue, a.Expr.Range(), summary, err.Error())
+}
+
 func (a *HCLAttribute) Keyword() string {
 	return hcl.ExprAsKeyword(a.Expr)
 }
@@ -70,7 +111,7 @@ func (a *HCLAttribute) RawJSON() (json.RawMessage, error) {
 
 	val, diags := a.Expr.Value(nil)
 	if diags.HasErrors() {
-		return nil, fmt.Errorf("could not fetch JSON value for %q: %v", a.Name, diags.Errs())
+		return nil, fmt.Errorf("could not fetch JSON value for %q: %w", a.Name, diagnostics.FromHCL(diagnostics.CodeInvalidValue, diags))
 	}
 
 	// convert cty.Value to SimpleJSONValue to get the correct decoding of its internal value
@@ -92,6 +133,26 @@ func (a *HCLAttribute) VarType() (entities.Type, error) {
 	return GetVarTypeFromExpression(a.Expr)
 }
 
+// AttributeType returns the type of a documented attribute, which may be declared as `optional(type, default)`
+func (a *HCLAttribute) AttributeType() (entities.Type, error) {
+	if a == nil {
+		return entities.Type{}, nil
+	}
+
+	return GetAttributeTypeFromExpression(a.Expr)
+}
+
+// TypeAttributes returns the attributes declared by an `object({...})` type constraint
+func (a *HCLAttribute) TypeAttributes() ([]entities.Attribute, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	const variableAttributeLevel = 1
+
+	return GetAttributesFromTypeExpression(a.Expr, variableAttributeLevel)
+}
+
 func (a *HCLAttribute) VarTypeFromString() (entities.Type, error) {
 	if a == nil {
 		return entities.Type{}, nil
@@ -99,12 +160,26 @@ func (a *HCLAttribute) VarTypeFromString() (entities.Type, error) {
 
 	val, diags := a.Expr.Value(nil)
 	if diags.HasErrors() {
-		return entities.Type{}, fmt.Errorf("could not fetch type string value for %q: %v", a.Name, diags.Errs())
+		return entities.Type{}, fmt.Errorf("could not fetch type string value for %q: %w", a.Name, diagnostics.FromHCL(diagnostics.CodeInvalidValue, diags))
 	}
 
 	return getVarTypeFromString(val.AsString(), a.Range.Start)
 }
 
+// AttributeTypeFromString returns the type of a documented attribute given as a `readme_type` string
+func (a *HCLAttribute) AttributeTypeFromString() (entities.Type, error) {
+	if a == nil {
+		return entities.Type{}, nil
+	}
+
+	val, diags := a.Expr.Value(nil)
+	if diags.HasErrors() {
+		return entities.Type{}, fmt.Errorf("could not fetch type string value for %q: %w", a.Name, diagnostics.FromHCL(diagnostics.CodeInvalidValue, diags))
+	}
+
+	return getAttributeTypeFromString(val.AsString(), a.Range.Start)
+}
+
 func getRawVariables(expr hcl.Expression) json.RawMessage {
 	var varValue []byte
 
@@ -2,205 +2,369 @@ package hclparser
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/types"
 	"github.com/zclconf/go-cty/cty"
-	"github.com/zclconf/go-cty/cty/function"
-	"github.com/zclconf/go-cty/cty/gocty"
 )
 
+// typeParser parses type expressions. The types of variables and outputs differ in the type constructors
+// they accept: only outputs can be typed as `resource(label)`.
+type typeParser struct {
+	resources bool
+}
+
 func GetVarTypeFromExpression(expr hcl.Expression) (entities.Type, error) {
-	return getTypeFromExpression(expr, varFunctions())
+	return typeParser{}.parse(expr)
 }
 
 func GetOutputTypeFromExpression(expr hcl.Expression) (entities.Type, error) {
-	return getTypeFromExpression(expr, outputFunctions())
+	return typeParser{resources: true}.parse(expr)
 }
 
-func getTypeFromExpression(expr hcl.Expression, ctxFunctions map[string]function.Function) (entities.Type, error) {
-	kw := hcl.ExprAsKeyword(expr)
+// parse returns the complete type tree of the expression, e.g. `map(list(object({ name = string })))`
+func (p typeParser) parse(expr hcl.Expression) (entities.Type, error) {
+	if kw := hcl.ExprAsKeyword(expr); kw != "" {
+		return keywordType(expr, kw)
+	}
+
+	call, diags := hcl.ExprCall(expr)
+	if diags.HasErrors() {
+		return entities.Type{}, invalidType(expr, "type must be a type keyword like string or a type constructor like list(string)")
+	}
+
+	if call.Name == "optional" {
+		return entities.Type{}, invalidType(expr, "optional is only allowed for the attributes of object types")
+	}
+
+	if len(call.Arguments) != 1 {
+		return entities.Type{}, invalidType(expr, fmt.Sprintf("type %q needs exactly one argument", call.Name))
+	}
+
+	arg := call.Arguments[0]
+
+	switch call.Name {
+	case "list", "set", "map":
+		tfType, _ := types.TerraformTypes(call.Name)
+
+		nested, err := p.element(arg)
+		if err != nil {
+			return entities.Type{}, err
+		}
+
+		return entities.Type{TFType: tfType, Nested: &nested}, nil
+	case "object":
+		if label := hcl.ExprAsKeyword(arg); label != "" {
+			return entities.Type{TFType: types.TerraformObject, Label: label}, nil
+		}
+
+		fields, err := p.fields(arg)
+		if err != nil {
+			return entities.Type{}, err
+		}
+
+		return entities.Type{TFType: types.TerraformObject, Fields: fields}, nil
+	case "tuple":
+		elements, err := p.elements(arg)
+		if err != nil {
+			return entities.Type{}, err
+		}
+
+		return entities.Type{TFType: types.TerraformTuple, Elements: elements}, nil
+	case "resource":
+		label := hcl.ExprAsKeyword(arg)
+		if !p.resources || label == "" {
+			break
+		}
+
+		return entities.Type{TFType: types.TerraformResource, Label: label}, nil
+	}
+
+	return entities.Type{}, invalidType(expr, fmt.Sprintf("type %q is invalid", call.Name))
+}
 
+func keywordType(expr hcl.Expression, kw string) (entities.Type, error) {
 	switch kw {
 	case "string", "number", "bool", "any":
 		tfType, ok := types.TerraformTypes(kw)
 		if !ok {
-			return entities.Type{}, fmt.Errorf("could not get terraform type for %q", kw)
+			return entities.Type{}, invalidType(expr, fmt.Sprintf("could not get terraform type for %q", kw))
 		}
 
 		return entities.Type{TFType: tfType}, nil
-	case "list", "object", "map", "tuple":
+	case "list", "set", "object", "map", "tuple":
 		// invalid as these types should be function calls
-		return entities.Type{}, fmt.Errorf("type %q needs an argument", kw)
+		return entities.Type{}, invalidType(expr, fmt.Sprintf("type %q needs an argument", kw))
 	}
 
-	// TODO: how to make this decent?
-	if kw != "" && !(strings.HasPrefix(kw, "list") ||
-		strings.HasPrefix(kw, "object") ||
-		strings.HasPrefix(kw, "map") ||
-		strings.HasPrefix(kw, "tuple")) {
-		return entities.Type{}, fmt.Errorf("type %q is invalid", kw)
+	return entities.Type{}, invalidType(expr, fmt.Sprintf("type %q is invalid", kw))
+}
+
+// element parses the element type of a collection. Keywords that are not types are labels of
+// documented objects, e.g. `list(rule)` is a list of `rule` objects.
+func (p typeParser) element(expr hcl.Expression) (entities.Type, error) {
+	if kw := hcl.ExprAsKeyword(expr); kw != "" && !isTypeKeyword(kw) {
+		return entities.Type{TFType: types.TerraformObject, Label: kw}, nil
 	}
 
-	return getComplexType(expr, ctxFunctions)
+	return p.parse(expr)
 }
 
-// this function exists to make it possible to parse `type` attribute expressions and `readme_type`
-// attribute strings in the same way, so they are compatible even though they have different types
-func getVarTypeFromString(str string, startRange hcl.Pos) (entities.Type, error) {
-	expr, parseDiags := hclsyntax.ParseExpression([]byte(str), "", startRange)
-	if parseDiags.HasErrors() {
-		return entities.Type{}, fmt.Errorf("parsing type string expression: %v", parseDiags.Errs())
+func isTypeKeyword(kw string) bool {
+	switch kw {
+	case "string", "number", "bool", "any", "list", "set", "object", "map", "tuple":
+		return true
 	}
 
-	return GetVarTypeFromExpression(expr)
+	return false
 }
 
-func getComplexType(expr hcl.Expression, ctxFunctions map[string]function.Function) (entities.Type, error) {
-	got, exprDiags := expr.Value(getEvalContextForExpr(expr, ctxFunctions))
-	if exprDiags.HasErrors() {
-		return entities.Type{}, fmt.Errorf("getting expression value: %v", exprDiags.Errs())
+// fields parses the attribute types of an object constructor like `{ name = string }`
+func (p typeParser) fields(expr hcl.Expression) (map[string]entities.Type, error) {
+	pairs, diags := hcl.ExprMap(expr)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("parsing object attributes: %w", diagnostics.FromHCL(diagnostics.CodeInvalidType, diags))
 	}
-	var tfType, nestedTFType types.TerraformType
 
-	err := gocty.FromCtyValue(got.GetAttr("type"), &tfType)
-	if err != nil {
-		return entities.Type{}, fmt.Errorf("getting type definition: %v", err)
+	fields := make(map[string]entities.Type, len(pairs))
+
+	for _, pair := range pairs {
+		name, err := getObjectKey(pair.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		fieldType, err := p.field(pair.Value)
+		if err != nil {
+			return nil, fmt.Errorf("parsing type of attribute %q: %w", name, err)
+		}
+
+		fields[name] = fieldType
 	}
 
-	err = gocty.FromCtyValue(got.GetAttr("nestedType"), &nestedTFType)
-	if err != nil {
-		return entities.Type{}, fmt.Errorf("getting nested type definition: %v", err)
+	return fields, nil
+}
+
+// field parses the type of an object attribute, which may be wrapped by the `optional(type, default)` modifier
+func (p typeParser) field(expr hcl.Expression) (entities.Type, error) {
+	call, diags := hcl.ExprCall(expr)
+	if diags.HasErrors() || call.Name != "optional" {
+		return p.parse(expr)
 	}
 
-	var typeLabel, nestedTypeLabel string
-	err = gocty.FromCtyValue(got.GetAttr("typeLabel"), &typeLabel)
-	if err != nil {
-		return entities.Type{}, fmt.Errorf("getting type label: %v", err)
+	if len(call.Arguments) < 1 || len(call.Arguments) > 2 {
+		return entities.Type{}, invalidType(expr, "optional needs a type and an optional default value")
 	}
 
-	err = gocty.FromCtyValue(got.GetAttr("nestedTypeLabel"), &nestedTypeLabel)
+	fieldType, err := p.parse(call.Arguments[0])
 	if err != nil {
-		return entities.Type{}, fmt.Errorf("getting nested type label: %v", err)
+		return entities.Type{}, err
 	}
 
-	typeDef := entities.Type{
-		TFType: tfType,
-		Label:  typeLabel,
-	}
+	fieldType.Optional = true
+
+	if len(call.Arguments) == 2 {
+		defaultAttr := &HCLAttribute{&hcl.Attribute{Name: "default", Expr: call.Arguments[1], Range: call.Arguments[1].Range()}}
 
-	if nestedTFType != types.TerraformEmptyType {
-		typeDef.Nested = &entities.Type{
-			TFType: nestedTFType,
-			Label:  nestedTypeLabel,
+		fieldType.Default, err = defaultAttr.RawJSON()
+		if err != nil {
+			return entities.Type{}, err
 		}
 	}
 
-	return typeDef, nil
+	return fieldType, nil
 }
 
-func nestedTypeFunc(tfType types.TerraformType) function.Function {
-	return function.New(&function.Spec{
-		Params: []function.Parameter{
-			{
-				Name:             "nestedTypeLabel",
-				Type:             cty.String,
-				AllowDynamicType: true,
-			},
-		},
-		Type: function.StaticReturnType(cty.Object(typeObj())),
-		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
-			var nestedType types.TerraformType
-			var nestedLabel string
+// elements parses the element types of a tuple like `[string, number]`
+func (p typeParser) elements(expr hcl.Expression) ([]entities.Type, error) {
+	exprs, diags := hcl.ExprList(expr)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("parsing tuple elements: %w", diagnostics.FromHCL(diagnostics.CodeInvalidType, diags))
+	}
+
+	elements := make([]entities.Type, 0, len(exprs))
+
+	for _, elemExpr := range exprs {
+		elem, err := p.parse(elemExpr)
+		if err != nil {
+			return nil, err
+		}
 
-			nestedTypeName := args[0].AsString()
+		elements = append(elements, elem)
+	}
 
-			nestedType, ok := types.TerraformTypes(nestedTypeName)
-			if !ok {
-				nestedType = types.TerraformObject
-				nestedLabel = nestedTypeName
-			}
+	return elements, nil
+}
 
-			return cty.ObjectVal(map[string]cty.Value{
-				"type":            cty.NumberIntVal(int64(tfType)),
-				"typeLabel":       cty.StringVal(""), // need to pass empty value here so cty doesn't panic
-				"nestedType":      cty.NumberIntVal(int64(nestedType)),
-				"nestedTypeLabel": cty.StringVal(nestedLabel),
-			}), nil
-		},
-	})
+func invalidType(expr hcl.Expression, summary string) diagnostics.Diagnostic {
+	return diagnostics.New(diagnostics.CodeInvalidType, expr.Range(), summary, "")
 }
 
-func complexTypeFunc(tfType types.TerraformType) function.Function {
-	return function.New(&function.Spec{
-		Params: []function.Parameter{
-			{
-				Name:             "typeLabel",
-				Type:             cty.String,
-				AllowDynamicType: true,
-			},
-		},
-		Type: function.StaticReturnType(cty.Object(typeObj())),
-		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
-			typeLabel := args[0].AsString()
+// GetAttributesFromTypeExpression returns the attributes declared in an object type constraint like
+// `object({ name = string })`. The object may be wrapped by a `list`, `set` or `map` type.
+// Attributes declared as `optional(type, default)` are not required and have the given default.
+// Types that do not declare object attributes return no attributes.
+func GetAttributesFromTypeExpression(expr hcl.Expression, level int) ([]entities.Attribute, error) {
+	obj, ok := getObjectConstructor(expr)
+	if !ok {
+		return nil, nil
+	}
+
+	pairs, diags := hcl.ExprMap(obj)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("parsing object attributes: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
+	}
+
+	var attributes []entities.Attribute
+
+	for _, pair := range pairs {
+		name, err := getObjectKey(pair.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		attrType, err := typeParser{}.field(pair.Value)
+		if err != nil {
+			return nil, fmt.Errorf("parsing type of attribute %q: %v", name, err)
+		}
+
+		nestedAttributes, err := GetAttributesFromTypeExpression(pair.Value, level+1)
+		if err != nil {
+			return nil, err
+		}
+
+		attributes = append(attributes, entities.Attribute{
+			Name:       name,
+			Type:       attrType.WithoutModifier(),
+			Required:   !attrType.Optional,
+			Default:    attrType.Default,
+			Level:      level,
+			Attributes: nestedAttributes,
+			DefRange:   pair.Key.Range(),
+		})
+	}
 
-			return cty.ObjectVal(map[string]cty.Value{
-				"type":      cty.NumberIntVal(int64(tfType)),
-				"typeLabel": cty.StringVal(typeLabel),
-				// the following empty values need to be set to the attributes
-				// otherwise cty panics
-				"nestedType":      cty.NumberIntVal(int64(types.TerraformEmptyType)),
-				"nestedTypeLabel": cty.StringVal(""), //
-			}), nil
-		},
-	})
+	return attributes, nil
 }
 
-func getVariablesMap(expr hcl.Expression) map[string]cty.Value {
-	varMap := make(map[string]cty.Value)
-	for _, variable := range expr.Variables() {
-		name := variable.RootName()
+// getObjectConstructor unwraps `object({...})`, `list(object({...}))`, `set(object({...}))`,
+// `map(object({...}))` and `optional(object({...}))` returning the object constructor expression
+func getObjectConstructor(expr hcl.Expression) (hcl.Expression, bool) {
+	call, diags := hcl.ExprCall(expr)
+	if diags.HasErrors() || len(call.Arguments) == 0 {
+		return nil, false
+	}
 
-		varMap[name] = cty.StringVal(name)
+	switch call.Name {
+	case "object":
+		if isObjectConstructor(call.Arguments[0]) {
+			return call.Arguments[0], true
+		}
+	case "list", "set", "map", "optional":
+		return getObjectConstructor(call.Arguments[0])
 	}
 
-	return varMap
+	return nil, false
+}
+
+func isObjectConstructor(expr hcl.Expression) bool {
+	_, diags := hcl.ExprMap(expr)
+
+	return !diags.HasErrors()
 }
 
-func getEvalContextForExpr(expr hcl.Expression, ctxFunctions map[string]function.Function) *hcl.EvalContext {
-	return &hcl.EvalContext{
-		Functions: ctxFunctions,
-		Variables: getVariablesMap(expr),
+func getObjectKey(expr hcl.Expression) (string, error) {
+	if kw := hcl.ExprAsKeyword(expr); kw != "" {
+		return kw, nil
+	}
+
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return "", fmt.Errorf("getting object attribute name: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
+	}
+
+	if val.Type() != cty.String {
+		return "", fmt.Errorf("object attribute name must be a string, got %s", val.Type().FriendlyName())
 	}
+
+	return val.AsString(), nil
+}
+
+// GetAttributeTypeFromExpression returns the type of a documented attribute, which may be
+// wrapped by the `optional(type, default)` modifier like the attributes of object types
+func GetAttributeTypeFromExpression(expr hcl.Expression) (entities.Type, error) {
+	return typeParser{}.field(expr)
+}
+
+// this function exists to make it possible to parse `type` attribute expressions and `readme_type`
+// attribute strings in the same way, so they are compatible even though they have different types
+func getVarTypeFromString(str string, startRange hcl.Pos) (entities.Type, error) {
+	return getTypeFromString(str, startRange, GetVarTypeFromExpression)
+}
+
+func getAttributeTypeFromString(str string, startRange hcl.Pos) (entities.Type, error) {
+	return getTypeFromString(str, startRange, GetAttributeTypeFromExpression)
 }
 
-func varFunctions() map[string]function.Function {
-	return map[string]function.Function{
-		"object": complexTypeFunc(types.TerraformObject),
-		"map":    nestedTypeFunc(types.TerraformMap),
-		"list":   nestedTypeFunc(types.TerraformList),
-		"set":    nestedTypeFunc(types.TerraformSet),
+func getTypeFromString(str string, startRange hcl.Pos, parse func(hcl.Expression) (entities.Type, error)) (entities.Type, error) {
+	expr, parseDiags := hclsyntax.ParseExpression([]byte(str), "", startRange)
+	if parseDiags.HasErrors() {
+		return entities.Type{}, fmt.Errorf("parsing type string expression: %w", diagnostics.FromHCL(diagnostics.CodeInvalidType, parseDiags))
 	}
+
+	return parse(expr)
 }
 
-func outputFunctions() map[string]function.Function {
-	return map[string]function.Function{
-		"resource": complexTypeFunc(types.TerraformResource),
-		"object":   complexTypeFunc(types.TerraformObject),
-		"map":      nestedTypeFunc(types.TerraformMap),
-		"list":     nestedTypeFunc(types.TerraformList),
-		"set":      nestedTypeFunc(types.TerraformSet),
+// GetOutputTypeFromValue infers the type of an output from its `value` expression. References to
+// resources like `aws_s3_bucket.this` or `aws_s3_bucket.this[0]` are typed as `resource(aws_s3_bucket)`,
+// literals by their value. The type of other expressions, e.g. object constructors or references to
+// resource attributes, variables or modules, can not be inferred without evaluating the module and
+// is returned empty.
+func GetOutputTypeFromValue(expr hcl.Expression) entities.Type {
+	switch e := expr.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		return traversalType(e.Traversal)
+	case *hclsyntax.TemplateExpr:
+		return entities.Type{TFType: types.TerraformString}
+	case *hclsyntax.LiteralValueExpr:
+		switch e.Val.Type() {
+		case cty.String:
+			return entities.Type{TFType: types.TerraformString}
+		case cty.Number:
+			return entities.Type{TFType: types.TerraformNumber}
+		case cty.Bool:
+			return entities.Type{TFType: types.TerraformBool}
+		}
 	}
+
+	return entities.Type{}
 }
 
-func typeObj() map[string]cty.Type {
-	return map[string]cty.Type{
-		"type":            cty.Number,
-		"typeLabel":       cty.String,
-		"nestedType":      cty.Number,
-		"nestedTypeLabel": cty.String,
+// traversalType returns the resource type of traversals referencing a resource or one of its instances
+func traversalType(traversal hcl.Traversal) entities.Type {
+	if len(traversal) < 2 || len(traversal) > 3 {
+		return entities.Type{}
+	}
+
+	root := traversal.RootName()
+
+	switch root {
+	case "var", "local", "module", "data", "path", "count", "each", "self", "terraform":
+		return entities.Type{}
 	}
+
+	if _, ok := traversal[1].(hcl.TraverseAttr); !ok {
+		return entities.Type{}
+	}
+
+	if len(traversal) == 3 {
+		if _, ok := traversal[2].(hcl.TraverseIndex); !ok {
+			return entities.Type{}
+		}
+	}
+
+	return entities.Type{TFType: types.TerraformResource, Label: root}
 }
@@ -1,6 +1,7 @@
 package hclparser
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
@@ -114,6 +115,41 @@ var varTests = []struct {
 			TFType: types.TerraformBool,
 		},
 	},
+	{
+		expression: `map(list(my_object))`,
+		want: entities.Type{
+			TFType: types.TerraformMap,
+			Nested: &entities.Type{
+				TFType: types.TerraformList,
+				Nested: &entities.Type{
+					TFType: types.TerraformObject,
+					Label:  "my_object",
+				},
+			},
+		},
+	},
+	{
+		expression: `list(list(string))`,
+		want: entities.Type{
+			TFType: types.TerraformList,
+			Nested: &entities.Type{
+				TFType: types.TerraformList,
+				Nested: &entities.Type{
+					TFType: types.TerraformString,
+				},
+			},
+		},
+	},
+	{
+		expression: `tuple([string, number])`,
+		want: entities.Type{
+			TFType: types.TerraformTuple,
+			Elements: []entities.Type{
+				{TFType: types.TerraformString},
+				{TFType: types.TerraformNumber},
+			},
+		},
+	},
 }
 
 func TestGetVarTypeFromExpression(t *testing.T) {
@@ -268,3 +304,180 @@ func TestGetOutputTypeFromExpression(t *testing.T) {
 		})
 	}
 }
+
+func TestGetOutputTypeFromValue(t *testing.T) {
+	for _, tt := range []struct {
+		expression string
+		want       entities.Type
+	}{
+		{expression: `aws_s3_bucket.this`, want: entities.Type{TFType: types.TerraformResource, Label: "aws_s3_bucket"}},
+		{expression: `aws_s3_bucket.this[0]`, want: entities.Type{TFType: types.TerraformResource, Label: "aws_s3_bucket"}},
+		{expression: `aws_s3_bucket.this["key"]`, want: entities.Type{TFType: types.TerraformResource, Label: "aws_s3_bucket"}},
+		{expression: `aws_s3_bucket.this.id`, want: entities.Type{}},
+		{expression: `var.name`, want: entities.Type{}},
+		{expression: `module.bucket.id`, want: entities.Type{}},
+		{expression: `data.aws_region.current`, want: entities.Type{}},
+		{expression: `"name"`, want: entities.Type{TFType: types.TerraformString}},
+		{expression: `"name-${var.suffix}"`, want: entities.Type{TFType: types.TerraformString}},
+		{expression: `42`, want: entities.Type{TFType: types.TerraformNumber}},
+		{expression: `true`, want: entities.Type{TFType: types.TerraformBool}},
+		{expression: `{ id = aws_s3_bucket.this.id }`, want: entities.Type{}},
+		{expression: `[aws_s3_bucket.this.id]`, want: entities.Type{}},
+	} {
+		t.Run(tt.expression, func(t *testing.T) {
+			expr, parseDiags := hclsyntax.ParseExpression([]byte(tt.expression), "", hcl.Pos{Line: 1, Column: 1, Byte: 0})
+			if parseDiags.HasErrors() {
+				t.Fatalf("Error parsing expression: %v", parseDiags.Errs())
+			}
+
+			test.AssertEqualTypes(t, tt.want, GetOutputTypeFromValue(expr))
+		})
+	}
+}
+
+func TestGetVarTypeFromObjectConstraint(t *testing.T) {
+	nameField := map[string]entities.Type{
+		"name": {TFType: types.TerraformString},
+	}
+
+	for _, tt := range []struct {
+		expression string
+		want       entities.Type
+	}{
+		{
+			expression: `object({ name = string })`,
+			want: entities.Type{
+				TFType: types.TerraformObject,
+				Fields: nameField,
+			},
+		},
+		{
+			expression: `list(object({ name = string }))`,
+			want: entities.Type{
+				TFType: types.TerraformList,
+				Nested: &entities.Type{
+					TFType: types.TerraformObject,
+					Fields: nameField,
+				},
+			},
+		},
+		{
+			expression: `map(object({ name = string }))`,
+			want: entities.Type{
+				TFType: types.TerraformMap,
+				Nested: &entities.Type{
+					TFType: types.TerraformObject,
+					Fields: nameField,
+				},
+			},
+		},
+		{
+			expression: `map(list(object({ name = string, port = optional(number, 80) })))`,
+			want: entities.Type{
+				TFType: types.TerraformMap,
+				Nested: &entities.Type{
+					TFType: types.TerraformList,
+					Nested: &entities.Type{
+						TFType: types.TerraformObject,
+						Fields: map[string]entities.Type{
+							"name": {TFType: types.TerraformString},
+							"port": {TFType: types.TerraformNumber, Optional: true, Default: json.RawMessage("80")},
+						},
+					},
+				},
+			},
+		},
+		{
+			expression: `tuple([string, list(number)])`,
+			want: entities.Type{
+				TFType: types.TerraformTuple,
+				Elements: []entities.Type{
+					{TFType: types.TerraformString},
+					{TFType: types.TerraformList, Nested: &entities.Type{TFType: types.TerraformNumber}},
+				},
+			},
+		},
+		{
+			expression: `list(list(string))`,
+			want: entities.Type{
+				TFType: types.TerraformList,
+				Nested: &entities.Type{
+					TFType: types.TerraformList,
+					Nested: &entities.Type{TFType: types.TerraformString},
+				},
+			},
+		},
+		{
+			expression: `object({ tags = optional(map(string)) })`,
+			want: entities.Type{
+				TFType: types.TerraformObject,
+				Fields: map[string]entities.Type{
+					"tags": {TFType: types.TerraformMap, Nested: &entities.Type{TFType: types.TerraformString}, Optional: true},
+				},
+			},
+		},
+	} {
+		t.Run(tt.expression, func(t *testing.T) {
+			expr, parseDiags := hclsyntax.ParseExpression([]byte(tt.expression), "", hcl.Pos{Line: 1, Column: 1, Byte: 0})
+			if parseDiags.HasErrors() {
+				t.Errorf("Error parsing expression: %v", parseDiags.Errs())
+			}
+
+			got, err := GetVarTypeFromExpression(expr)
+			assert.NoError(t, err)
+
+			test.AssertEqualTypes(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetAttributesFromTypeExpression(t *testing.T) {
+	const expression = `list(object({
+  name = string
+  "tags" = map(string)
+  rule = object({
+    ports = list(number)
+  })
+}))`
+
+	expr, parseDiags := hclsyntax.ParseExpression([]byte(expression), "", hcl.Pos{Line: 1, Column: 1, Byte: 0})
+	if parseDiags.HasErrors() {
+		t.Fatalf("Error parsing expression: %v", parseDiags.Errs())
+	}
+
+	got, err := GetAttributesFromTypeExpression(expr, 1)
+	assert.NoError(t, err)
+
+	assert.EqualInts(t, 3, len(got))
+
+	assert.EqualStrings(t, "name", got[0].Name)
+	assert.EqualStrings(t, "string", got[0].Type.AsString())
+	assert.EqualInts(t, 1, got[0].Level)
+
+	if !got[0].Required {
+		t.Errorf("Expected attribute %q to be required", got[0].Name)
+	}
+
+	assert.EqualStrings(t, "tags", got[1].Name)
+	assert.EqualStrings(t, "map(string)", got[1].Type.AsString())
+
+	assert.EqualStrings(t, "rule", got[2].Name)
+	assert.EqualStrings(t, "object({ ports = list(number) })", got[2].Type.AsString())
+	assert.EqualInts(t, 1, len(got[2].Attributes))
+
+	ports := got[2].Attributes[0]
+	assert.EqualStrings(t, "ports", ports.Name)
+	assert.EqualStrings(t, "list(number)", ports.Type.AsString())
+	assert.EqualInts(t, 2, ports.Level)
+
+	t.Run("when type has no object constraint", func(t *testing.T) {
+		expr, parseDiags := hclsyntax.ParseExpression([]byte(`list(my_object)`), "", hcl.Pos{Line: 1, Column: 1, Byte: 0})
+		if parseDiags.HasErrors() {
+			t.Fatalf("Error parsing expression: %v", parseDiags.Errs())
+		}
+
+		got, err := GetAttributesFromTypeExpression(expr, 1)
+		assert.NoError(t, err)
+		assert.EqualInts(t, 0, len(got))
+	})
+}
@@ -0,0 +1,54 @@
+package hclparser
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ReferenceVariable is the variable interpolating references in strings, e.g. `${ref.name}`
+const ReferenceVariable = "ref"
+
+// ReferencePlaceholder returns the value of the interpolation of a reference. References are only
+// known once all included documents are parsed, so their values are replaced afterwards.
+func ReferencePlaceholder(name string) string {
+	return "\x00" + ReferenceVariable + "." + name + "\x00"
+}
+
+// ReferenceName returns the name of the reference of a traversal, e.g. `name` of `ref.name`
+func ReferenceName(traversal hcl.Traversal) (string, bool) {
+	if len(traversal) != 2 || traversal.RootName() != ReferenceVariable {
+		return "", false
+	}
+
+	attr, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+
+	return attr.Name, true
+}
+
+// referenceContext returns a child of ctx evaluating the references interpolated in expr to their
+// placeholders. It returns ctx if expr interpolates no references.
+func referenceContext(expr hcl.Expression, ctx *hcl.EvalContext) *hcl.EvalContext {
+	refs := map[string]cty.Value{}
+
+	for _, traversal := range expr.Variables() {
+		if name, ok := ReferenceName(traversal); ok {
+			refs[name] = cty.StringVal(ReferencePlaceholder(name))
+		}
+	}
+
+	if len(refs) == 0 {
+		return ctx
+	}
+
+	child := &hcl.EvalContext{}
+	if ctx != nil {
+		child = ctx.NewChild()
+	}
+
+	child.Variables = map[string]cty.Value{ReferenceVariable: cty.ObjectVal(refs)}
+
+	return child
+}
@@ -0,0 +1,106 @@
+package validationparser
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/parsers/hclparser"
+	"github.com/mineiros-io/terradoc/internal/schemas/validationschema"
+)
+
+// parseRequirements returns the versions required by the `terraform` blocks of a file
+func parseRequirements(terraformBlocks hcl.Blocks) (entities.Requirements, error) {
+	result := entities.Requirements{}
+
+	for _, blk := range terraformBlocks {
+		content, _, diags := blk.Body.PartialContent(validationschema.TerraformSchema())
+		if diags.HasErrors() {
+			return entities.Requirements{}, fmt.Errorf("parsing terraform block: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
+		}
+
+		requirements := entities.Requirements{DefRange: blk.DefRange}
+
+		var err error
+
+		requirements.TerraformVersion, err = hclparser.GetAttribute(content.Attributes, "required_version").String()
+		if err != nil {
+			return entities.Requirements{}, err
+		}
+
+		for _, providersBlk := range content.Blocks.OfType("required_providers") {
+			providers, err := parseRequiredProviders(providersBlk)
+			if err != nil {
+				return entities.Requirements{}, err
+			}
+
+			requirements.Providers = append(requirements.Providers, providers...)
+		}
+
+		result = result.Merge(requirements)
+	}
+
+	return result, nil
+}
+
+// parseRequiredProviders returns the providers of a `required_providers` block. Providers are
+// required as `name = { source = "...", version = "..." }` or, in the legacy syntax, as
+// `name = "version"`.
+func parseRequiredProviders(blk *hcl.Block) ([]entities.ProviderRequirement, error) {
+	attrs, diags := blk.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("parsing required_providers: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
+	}
+
+	var providers []entities.ProviderRequirement
+
+	for _, attr := range attrs {
+		provider := entities.ProviderRequirement{Name: attr.Name, DefRange: attr.Range}
+
+		pairs, diags := hcl.ExprMap(attr.Expr)
+		if diags.HasErrors() {
+			version, err := (&hclparser.HCLAttribute{Attribute: attr}).String()
+			if err != nil {
+				return nil, err
+			}
+
+			provider.Version = version
+			providers = append(providers, provider)
+
+			continue
+		}
+
+		for _, pair := range pairs {
+			// only the source and the version are evaluated, `configuration_aliases` refer to
+			// providers that are unknown here
+			switch key := hcl.ExprAsKeyword(pair.Key); key {
+			case "source", "version":
+				value, err := (&hclparser.HCLAttribute{Attribute: &hcl.Attribute{Name: key, Expr: pair.Value, Range: pair.Value.Range()}}).String()
+				if err != nil {
+					return nil, err
+				}
+
+				if key == "source" {
+					provider.Source = value
+				} else {
+					provider.Version = value
+				}
+			}
+		}
+
+		providers = append(providers, provider)
+	}
+
+	sortProviders(providers)
+
+	return providers, nil
+}
+
+// sortProviders sorts the providers by their position, attributes are returned in random order
+func sortProviders(providers []entities.ProviderRequirement) {
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].DefRange.Start.Byte < providers[j].DefRange.Start.Byte
+	})
+}
@@ -0,0 +1,99 @@
+package validationparser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/parsers/hclparser"
+	"github.com/mineiros-io/terradoc/internal/schemas/validationschema"
+)
+
+// parseResources returns the `resource`, `data` and `module` blocks of a file in the order they are
+// declared
+func parseResources(blocks hcl.Blocks) (resources []entities.Resource, err error) {
+	for _, blk := range blocks {
+		var resource entities.Resource
+
+		switch blk.Type {
+		case entities.ResourceKind, entities.DataSourceKind:
+			resource, err = parseResource(blk)
+		case entities.ModuleKind:
+			resource, err = parseModule(blk)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+func parseResource(blk *hcl.Block) (entities.Resource, error) {
+	content, _, diags := blk.Body.PartialContent(validationschema.ResourceSchema())
+	if diags.HasErrors() {
+		return entities.Resource{}, fmt.Errorf("parsing %s block: %w", blk.Type, diagnostics.FromHCL(diagnostics.CodeSchema, diags))
+	}
+
+	// resource and data blocks are required to have two labels as defined in the schema
+	resource := entities.Resource{
+		Kind:     blk.Type,
+		Type:     blk.Labels[0],
+		Name:     blk.Labels[1],
+		Provider: defaultProvider(blk.Labels[0]),
+		Count:    content.Attributes["count"] != nil,
+		ForEach:  content.Attributes["for_each"] != nil,
+		DefRange: blk.DefRange,
+	}
+
+	if attr, ok := content.Attributes["provider"]; ok {
+		// the provider is a reference, e.g. `aws.peer`, to a provider configuration
+		traversal, diags := hcl.AbsTraversalForExpr(attr.Expr)
+		if diags.HasErrors() {
+			return entities.Resource{}, fmt.Errorf("parsing provider of %s %q: %w", blk.Type, resource.Name, diagnostics.FromHCL(diagnostics.CodeInvalidValue, diags))
+		}
+
+		resource.Provider = traversal.RootName()
+	}
+
+	return resource, nil
+}
+
+func parseModule(blk *hcl.Block) (entities.Resource, error) {
+	content, _, diags := blk.Body.PartialContent(validationschema.ModuleSchema())
+	if diags.HasErrors() {
+		return entities.Resource{}, fmt.Errorf("parsing module block: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
+	}
+
+	source, err := hclparser.GetAttribute(content.Attributes, "source").String()
+	if err != nil {
+		return entities.Resource{}, err
+	}
+
+	// module blocks are required to have a label as defined in the schema
+	return entities.Resource{
+		Kind:     blk.Type,
+		Name:     blk.Labels[0],
+		Source:   source,
+		Count:    content.Attributes["count"] != nil,
+		ForEach:  content.Attributes["for_each"] != nil,
+		DefRange: blk.DefRange,
+	}, nil
+}
+
+// defaultProvider returns the local name of the provider Terraform uses for a resource type without
+// a `provider` argument, which is the prefix of the type, e.g. `aws` for `aws_s3_bucket`
+func defaultProvider(resourceType string) string {
+	if i := strings.Index(resourceType, "_"); i > 0 {
+		return resourceType[:i]
+	}
+
+	return resourceType
+}
@@ -1,48 +1,75 @@
 package validationparser
 
 import (
-	"errors"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/parsers/hclparser"
+	"github.com/mineiros-io/terradoc/internal/schemas/outputsschema"
 	"github.com/mineiros-io/terradoc/internal/schemas/validationschema"
 	"github.com/mineiros-io/terradoc/internal/schemas/varsschema"
 )
 
-func Parse(r io.Reader, filename string, variablesEnabled bool, outputsEnabled bool) (entities.ValidationContents, error) {
+// Options selects the blocks parsed from Terraform files. Blocks that are not selected are not
+// parsed, so their problems do not fail parsing.
+type Options struct {
+	// Variables enables parsing `variable` blocks
+	Variables bool
+	// Outputs enables parsing `output` blocks
+	Outputs bool
+	// Requirements enables parsing the `required_version` and `required_providers` of `terraform`
+	// blocks
+	Requirements bool
+	// Resources enables parsing `resource`, `data` and `module` blocks
+	Resources bool
+}
+
+func Parse(r io.Reader, filename string, opts Options) (entities.ValidationContents, error) {
 	src, err := io.ReadAll(r)
 	if err != nil {
 		return entities.ValidationContents{}, err
 	}
 
-	return parseContentHCL(src, filename, variablesEnabled, outputsEnabled)
+	return parseContentHCL(src, filename, opts)
+}
+
+// ParseFile parses the Terraform file filename like Parse
+func ParseFile(filename string, opts Options) (entities.ValidationContents, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return entities.ValidationContents{}, fmt.Errorf("opening input %q: %v", filename, err)
+	}
+	defer f.Close()
+
+	return Parse(f, filename, opts)
 }
 
-func parseContentHCL(src []byte, filename string, variablesEnabled bool, outputsEnabled bool) (entities.ValidationContents, error) {
+func parseContentHCL(src []byte, filename string, opts Options) (entities.ValidationContents, error) {
 	p := hclparse.NewParser()
 	validationContents := entities.ValidationContents{}
 
 	f, diags := p.ParseHCL(src, filename)
 	if diags.HasErrors() {
 		// Only return errors relevant to parsing of variables or outputs
-		var errors []error
+		var errors hcl.Diagnostics
 		for _, e := range diags.Errs() {
-			if variablesEnabled && strings.Contains(e.Error(), "variable") {
-				errors = append(errors, e)
+			if opts.Variables && strings.Contains(e.Error(), "variable") {
+				errors = append(errors, e.(*hcl.Diagnostic))
 			}
 
-			if outputsEnabled && strings.Contains(e.Error(), "output") {
-				errors = append(errors, e)
+			if opts.Outputs && strings.Contains(e.Error(), "output") {
+				errors = append(errors, e.(*hcl.Diagnostic))
 			}
 		}
 
 		if len(errors) > 0 {
-			return entities.ValidationContents{}, fmt.Errorf("parsing HCL: %v", errors)
+			return entities.ValidationContents{}, fmt.Errorf("parsing HCL: %w", diagnostics.FromHCL(diagnostics.CodeSyntax, errors))
 		}
 
 	}
@@ -50,30 +77,46 @@ func parseContentHCL(src []byte, filename string, variablesEnabled bool, outputs
 	// Ignore errors, only focus on content to ignore non outputs/variables
 	content, _ := f.Body.Content(validationschema.RootSchema())
 
-	if variablesEnabled {
-		variables, err := parseVariables(content.Blocks.OfType("variable"))
+	if opts.Variables {
+		variables, err := parseVariables(content.Blocks.OfType("variable"), src)
 		if err != nil {
-			return entities.ValidationContents{}, fmt.Errorf("parsing variables: %v", err)
+			return entities.ValidationContents{}, fmt.Errorf("parsing variables: %w", err)
 		}
 		validationContents.Variables = variables
 	}
 
-	if outputsEnabled {
+	if opts.Outputs {
 		outputs, err := parseOutputs(content.Blocks.OfType("output"))
 		if err != nil {
-			return entities.ValidationContents{}, fmt.Errorf("parsing outputs: %v", err)
+			return entities.ValidationContents{}, fmt.Errorf("parsing outputs: %w", err)
 		}
 		validationContents.Outputs = outputs
 	}
 
+	if opts.Requirements {
+		requirements, err := parseRequirements(content.Blocks.OfType("terraform"))
+		if err != nil {
+			return entities.ValidationContents{}, fmt.Errorf("parsing requirements: %w", err)
+		}
+		validationContents.Requirements = requirements
+	}
+
+	if opts.Resources {
+		resources, err := parseResources(content.Blocks)
+		if err != nil {
+			return entities.ValidationContents{}, fmt.Errorf("parsing resources: %w", err)
+		}
+		validationContents.Resources = resources
+	}
+
 	return validationContents, nil
 }
 
-func parseVariables(variableBlocks hcl.Blocks) (variables []entities.Variable, err error) {
+func parseVariables(variableBlocks hcl.Blocks, src []byte) (variables []entities.Variable, err error) {
 	for _, varBlk := range variableBlocks {
-		variable, err := parseVariable(varBlk)
+		variable, err := parseVariable(varBlk, src)
 		if err != nil {
-			return nil, fmt.Errorf("parsing variable: %s", err)
+			return nil, fmt.Errorf("parsing variable: %w", err)
 		}
 
 		variables = append(variables, variable)
@@ -82,23 +125,23 @@ func parseVariables(variableBlocks hcl.Blocks) (variables []entities.Variable, e
 	return variables, nil
 }
 
-func parseVariable(variableBlock *hcl.Block) (entities.Variable, error) {
+func parseVariable(variableBlock *hcl.Block, src []byte) (entities.Variable, error) {
 	if len(variableBlock.Labels) != 1 {
-		return entities.Variable{}, errors.New("variable block must have a single label")
+		return entities.Variable{}, diagnostics.New(diagnostics.CodeSchema, variableBlock.DefRange, "variable block must have a single label", "")
 	}
 
 	variableContent, diags := variableBlock.Body.Content(varsschema.VariableSchema())
 	if diags.HasErrors() && len(variableContent.Attributes) != 1 {
-		var errors []error
+		var errors hcl.Diagnostics
 		for _, e := range diags.Errs() {
 			// Only return if parsing error is relevant to `type`
 			if strings.Contains(e.Error(), "type") {
-				errors = append(errors, e)
+				errors = append(errors, e.(*hcl.Diagnostic))
 			}
 		}
 
 		if len(errors) > 0 {
-			return entities.Variable{}, fmt.Errorf("parsing variable: %v", errors)
+			return entities.Variable{}, fmt.Errorf("parsing variable: %w", diagnostics.FromHCL(diagnostics.CodeSchema, errors))
 		}
 	}
 
@@ -106,24 +149,85 @@ func parseVariable(variableBlock *hcl.Block) (entities.Variable, error) {
 	name := variableBlock.Labels[0]
 	variable, err := createVariableFromHCLAttributes(variableContent.Attributes, name)
 	if err != nil {
-		return entities.Variable{}, fmt.Errorf("parsing variable: %s", err)
+		return entities.Variable{}, fmt.Errorf("parsing variable: %w", err)
+	}
+
+	variable.DefRange = variableBlock.DefRange
+
+	for _, blk := range variableContent.Blocks.OfType("validation") {
+		constraint, err := parseConstraint(blk, src)
+		if err != nil {
+			return entities.Variable{}, fmt.Errorf("parsing variable validation: %w", err)
+		}
+
+		variable.Constraints = append(variable.Constraints, constraint)
 	}
 
 	return variable, nil
 }
 
+// parseConstraint returns the condition of a `validation` block as written in src
+func parseConstraint(validationBlock *hcl.Block, src []byte) (entities.Constraint, error) {
+	content, diags := validationBlock.Body.Content(varsschema.ValidationSchema())
+	if diags.HasErrors() {
+		return entities.Constraint{}, fmt.Errorf("parsing validation block: %w", diagnostics.FromHCL(diagnostics.CodeSchema, diags))
+	}
+
+	errorMessage, err := hclparser.GetAttribute(content.Attributes, "error_message").Template(src)
+	if err != nil {
+		return entities.Constraint{}, err
+	}
+
+	return entities.Constraint{
+		Condition:    hclparser.GetAttribute(content.Attributes, "condition").Source(src),
+		ErrorMessage: errorMessage,
+		DefRange:     validationBlock.DefRange,
+	}, nil
+}
+
 func createVariableFromHCLAttributes(attrs hcl.Attributes, name string) (entities.Variable, error) {
 	var err error
 
 	variable := entities.Variable{Name: name}
 
+	variable.Description, err = hclparser.GetAttribute(attrs, "description").String()
+	if err != nil {
+		return entities.Variable{}, err
+	}
+
 	variable.Default, err = hclparser.GetAttribute(attrs, "default").RawJSON()
 	if err != nil {
 		return entities.Variable{}, err
 	}
 
+	variable.Sensitive, err = hclparser.GetAttribute(attrs, "sensitive").Bool()
+	if err != nil {
+		return entities.Variable{}, err
+	}
+
+	variable.Nullable = true
+	if nullableAttr := hclparser.GetAttribute(attrs, "nullable"); nullableAttr != nil {
+		variable.Nullable, err = nullableAttr.Bool()
+		if err != nil {
+			return entities.Variable{}, err
+		}
+	}
+
+	variable.Ephemeral, err = hclparser.GetAttribute(attrs, "ephemeral").Bool()
+	if err != nil {
+		return entities.Variable{}, err
+	}
+
 	// type definition
-	variable.Type, err = hclparser.GetAttribute(attrs, "type").VarType()
+	typeAttr := hclparser.GetAttribute(attrs, "type")
+
+	variable.Type, err = typeAttr.VarType()
+	if err != nil {
+		return entities.Variable{}, err
+	}
+
+	// attributes declared in `object({...})` type constraints
+	variable.Attributes, err = typeAttr.TypeAttributes()
 	if err != nil {
 		return entities.Variable{}, err
 	}
@@ -135,7 +239,7 @@ func parseOutputs(outputBlocks hcl.Blocks) (outputs []entities.Output, err error
 	for _, outBlk := range outputBlocks {
 		output, err := parseOutput(outBlk)
 		if err != nil {
-			return nil, fmt.Errorf("parsing output: %s", err)
+			return nil, fmt.Errorf("parsing output: %w", err)
 		}
 
 		outputs = append(outputs, output)
@@ -146,12 +250,32 @@ func parseOutputs(outputBlocks hcl.Blocks) (outputs []entities.Output, err error
 
 func parseOutput(outputBlock *hcl.Block) (entities.Output, error) {
 	if len(outputBlock.Labels) != 1 {
-		return entities.Output{}, errors.New("output block must have a single label")
+		return entities.Output{}, diagnostics.New(diagnostics.CodeSchema, outputBlock.DefRange, "output block must have a single label", "")
 	}
 
+	// Ignore errors, only focus on the attributes defined in the schema
+	outputContent, _, _ := outputBlock.Body.PartialContent(outputsschema.OutputSchema())
+
 	// output blocks are required to have a label as defined in the schema
 	name := outputBlock.Labels[0]
-	output := entities.Output{Name: name}
+	output := entities.Output{Name: name, DefRange: outputBlock.DefRange}
+
+	description, err := hclparser.GetAttribute(outputContent.Attributes, "description").String()
+	if err != nil {
+		return entities.Output{}, err
+	}
+	output.Description = description
+
+	output.Sensitive, err = hclparser.GetAttribute(outputContent.Attributes, "sensitive").Bool()
+	if err != nil {
+		return entities.Output{}, err
+	}
+
+	_, output.DependsOn = outputContent.Attributes["depends_on"]
+
+	if value, ok := outputContent.Attributes["value"]; ok {
+		output.Type = hclparser.GetOutputTypeFromValue(value.Expr)
+	}
 
 	return output, nil
 }
@@ -1,7 +1,315 @@
 package validationparser_test
 
-import "testing"
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/parsers/validationparser"
+)
 
 func TestParse(t *testing.T) {
-	t.Skip("TODO")
+	const src = `
+variable "name" {
+  type        = string
+  description = "The name of the bucket."
+  default     = "beer"
+  sensitive   = true
+}
+
+variable "tags" {
+  type      = map(string)
+  default   = { team = "infra" }
+  nullable  = false
+  ephemeral = true
+}
+
+output "id" {
+  description = "The ID of the bucket."
+  value       = "id"
+}
+`
+
+	got, err := validationparser.Parse(strings.NewReader(src), "variables.tf", validationparser.Options{Variables: true, Outputs: true})
+	assert.NoError(t, err)
+
+	assert.EqualInts(t, 2, len(got.Variables))
+	assert.EqualInts(t, 1, len(got.Outputs))
+
+	name := got.Variables[0]
+	assert.EqualStrings(t, "name", name.Name)
+	assert.EqualStrings(t, "The name of the bucket.", name.Description)
+	assert.EqualStrings(t, `"beer"`, string(name.Default))
+
+	if !name.Sensitive {
+		t.Error("wanted variable to be sensitive")
+	}
+
+	if !name.Nullable {
+		t.Error("wanted variable to be nullable by default")
+	}
+
+	tags := got.Variables[1]
+
+	var tagsDefault map[string]string
+	assert.NoError(t, json.Unmarshal(tags.Default, &tagsDefault))
+	assert.EqualStrings(t, "infra", tagsDefault["team"])
+
+	if tags.Sensitive {
+		t.Error("wanted variable not to be sensitive")
+	}
+
+	if tags.Nullable {
+		t.Error("wanted variable not to be nullable")
+	}
+
+	if !tags.Ephemeral {
+		t.Error("wanted variable to be ephemeral")
+	}
+
+	assert.EqualStrings(t, "The ID of the bucket.", got.Outputs[0].Description)
+}
+
+func TestParseOptionalAttributes(t *testing.T) {
+	const src = `
+variable "rules" {
+  type = list(object({
+    name     = string
+    port     = optional(number, 80)
+    protocol = optional(string)
+    tags     = optional(map(string), { team = "infra" })
+  }))
+}
+`
+
+	got, err := validationparser.Parse(strings.NewReader(src), "variables.tf", validationparser.Options{Variables: true})
+	assert.NoError(t, err)
+
+	attributes := got.Variables[0].Attributes
+	assert.EqualInts(t, 4, len(attributes))
+
+	for _, tt := range []struct {
+		wantName     string
+		wantType     string
+		wantRequired bool
+		wantDefault  string
+	}{
+		{wantName: "name", wantType: "string", wantRequired: true},
+		{wantName: "port", wantType: "number", wantDefault: "80"},
+		{wantName: "protocol", wantType: "string"},
+		{wantName: "tags", wantType: "map(string)", wantDefault: `{"team":"infra"}`},
+	} {
+		t.Run(tt.wantName, func(t *testing.T) {
+			var attr entities.Attribute
+			for _, a := range attributes {
+				if a.Name == tt.wantName {
+					attr = a
+				}
+			}
+
+			assert.EqualStrings(t, tt.wantName, attr.Name)
+			assert.EqualStrings(t, tt.wantType, attr.Type.AsString())
+			assert.EqualStrings(t, tt.wantDefault, string(attr.Default))
+
+			if attr.Required != tt.wantRequired {
+				t.Errorf("wanted required to be %t", tt.wantRequired)
+			}
+		})
+	}
+
+	want := "list(object({ name = string, port = optional(number, 80), protocol = optional(string), tags = optional(map(string), {\"team\":\"infra\"}) }))"
+	assert.EqualStrings(t, want, got.Variables[0].Type.AsString())
+}
+
+func TestParseConstraints(t *testing.T) {
+	const src = `
+variable "name" {
+  type = string
+
+  validation {
+    condition     = length(var.name) > 3
+    error_message = "The name must be longer than 3 characters."
+  }
+
+  validation {
+    condition = contains(
+      ["a", "b"],
+      substr(var.name, 0, 1),
+    )
+    error_message = "The name ${var.name} must start with a or b."
+  }
+}
+`
+
+	got, err := validationparser.Parse(strings.NewReader(src), "variables.tf", validationparser.Options{Variables: true})
+	assert.NoError(t, err)
+
+	constraints := got.Variables[0].Constraints
+	assert.EqualInts(t, 2, len(constraints))
+
+	assert.EqualStrings(t, "length(var.name) > 3", constraints[0].Condition)
+	assert.EqualStrings(t, "The name must be longer than 3 characters.", constraints[0].ErrorMessage)
+	assert.EqualInts(t, 5, constraints[0].DefRange.Start.Line)
+
+	assert.EqualStrings(t, "contains(\n      [\"a\", \"b\"],\n      substr(var.name, 0, 1),\n    )", constraints[1].Condition)
+	assert.EqualStrings(t, "The name ${var.name} must start with a or b.", constraints[1].ErrorMessage)
+}
+
+func TestParseOutputs(t *testing.T) {
+	const src = `
+output "bucket" {
+  description = "The S3 bucket."
+  value       = aws_s3_bucket.this
+}
+
+output "password" {
+  value      = random_password.this.result
+  sensitive  = true
+  depends_on = [aws_s3_bucket.this]
+}
+
+output "first" {
+  value = aws_s3_bucket.this[0]
+}
+
+output "greeting" {
+  value = "hello ${var.name}"
+}
+`
+
+	got, err := validationparser.Parse(strings.NewReader(src), "outputs.tf", validationparser.Options{Outputs: true})
+	assert.NoError(t, err)
+	assert.EqualInts(t, 4, len(got.Outputs))
+
+	bucket := got.Outputs[0]
+	assert.EqualStrings(t, "The S3 bucket.", bucket.Description)
+	assert.EqualStrings(t, "resource(aws_s3_bucket)", bucket.Type.AsString())
+
+	if bucket.Sensitive || bucket.DependsOn {
+		t.Errorf("wanted %q to be neither sensitive nor to have depends_on", bucket.Name)
+	}
+
+	password := got.Outputs[1]
+	assert.EqualStrings(t, "empty", password.Type.AsString())
+
+	if !password.Sensitive || !password.DependsOn {
+		t.Errorf("wanted %q to be sensitive and to have depends_on", password.Name)
+	}
+
+	assert.EqualStrings(t, "resource(aws_s3_bucket)", got.Outputs[2].Type.AsString())
+	assert.EqualStrings(t, "string", got.Outputs[3].Type.AsString())
+}
+
+func TestParseRequirements(t *testing.T) {
+	const src = `
+terraform {
+  required_version = ">= 1.0"
+
+  backend "s3" {}
+
+  required_providers {
+    aws = {
+      source                = "hashicorp/aws"
+      version               = ">= 4.0"
+      configuration_aliases = [aws.peer]
+    }
+    random = "~> 3.0"
+  }
+}
+
+terraform {
+  required_version = "< 2.0"
+}
+
+resource "aws_s3_bucket" "this" {}
+`
+
+	got, err := validationparser.Parse(strings.NewReader(src), "versions.tf", validationparser.Options{Requirements: true})
+	assert.NoError(t, err)
+
+	requirements := got.Requirements
+	assert.EqualStrings(t, ">= 1.0, < 2.0", requirements.TerraformVersion)
+	assert.EqualInts(t, 2, requirements.DefRange.Start.Line)
+	assert.EqualInts(t, 2, len(requirements.Providers))
+
+	aws := requirements.Providers[0]
+	assert.EqualStrings(t, "aws", aws.Name)
+	assert.EqualStrings(t, "hashicorp/aws", aws.Source)
+	assert.EqualStrings(t, ">= 4.0", aws.Version)
+	assert.EqualInts(t, 8, aws.DefRange.Start.Line)
+
+	random := requirements.Providers[1]
+	assert.EqualStrings(t, "random", random.Name)
+	assert.EqualStrings(t, "", random.Source)
+	assert.EqualStrings(t, "~> 3.0", random.Version)
+}
+
+func TestParseRequirementsDisabled(t *testing.T) {
+	const src = `
+terraform {
+  required_providers {
+    aws = { source = ["hashicorp/aws"] }
+  }
+}
+
+variable "name" {
+  type = string
+}
+`
+
+	got, err := validationparser.Parse(strings.NewReader(src), "main.tf", validationparser.Options{Variables: true})
+	assert.NoError(t, err)
+	assert.EqualInts(t, 1, len(got.Variables))
+	assert.EqualInts(t, 0, len(got.Requirements.Providers))
+
+	_, err = validationparser.Parse(strings.NewReader(src), "main.tf", validationparser.Options{Requirements: true})
+	assert.Error(t, err)
+}
+
+func TestParseResources(t *testing.T) {
+	const src = `
+resource "aws_s3_bucket" "this" {
+  count  = var.create ? 1 : 0
+  bucket = var.name
+}
+
+data "aws_iam_policy_document" "this" {
+  provider = aws.peer
+}
+
+module "queue" {
+  source   = "./modules/queue"
+  for_each = var.queues
+}
+
+variable "name" {}
+`
+
+	got, err := validationparser.Parse(strings.NewReader(src), "main.tf", validationparser.Options{Resources: true})
+	assert.NoError(t, err)
+	assert.EqualInts(t, 3, len(got.Resources))
+
+	bucket := got.Resources[0]
+	assert.EqualStrings(t, "aws_s3_bucket.this", bucket.Address())
+	assert.EqualStrings(t, "aws", bucket.Provider)
+	assert.EqualInts(t, 2, bucket.DefRange.Start.Line)
+
+	if !bucket.Count || bucket.ForEach {
+		t.Errorf("wanted %q to have count but not for_each", bucket.Address())
+	}
+
+	policy := got.Resources[1]
+	assert.EqualStrings(t, "data.aws_iam_policy_document.this", policy.Address())
+	assert.EqualStrings(t, "aws", policy.Provider)
+
+	queue := got.Resources[2]
+	assert.EqualStrings(t, "module.queue", queue.Address())
+	assert.EqualStrings(t, "./modules/queue", queue.Source)
+	assert.EqualStrings(t, "", queue.Provider)
+
+	if queue.Count || !queue.ForEach {
+		t.Errorf("wanted %q to have for_each but not count", queue.Address())
+	}
 }
@@ -0,0 +1,229 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+
+	"github.com/mineiros-io/terradoc"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/renderers"
+)
+
+const (
+	pageTemplateName = "page"
+
+	maxHeadingLevel = 6
+)
+
+type page struct {
+	Title    string
+	Header   entities.Header
+	Nav      []navItem
+	Sections []section
+}
+
+type navItem struct {
+	ID    string
+	Title template.HTML
+	Items []navItem
+
+	// plainTitle is the title as written in the document
+	plainTitle string
+}
+
+type section struct {
+	ID           string
+	Title        template.HTML
+	plainTitle   string
+	Level        int
+	Content      template.HTML
+	TOC          []navItem
+	Requirements *entities.Requirements
+	Resources    []entities.Resource
+	Variables    []argument
+	Outputs      []output
+	Sections     []section
+}
+
+// argument is a variable or one of its attributes
+type argument struct {
+	Anchor           string
+	Name             string
+	Type             entities.Type
+	TypeString       string
+	Required         bool
+	ForcesRecreation bool
+	Sensitive        bool
+	Ephemeral        bool
+	NonNullable      bool
+	Description      template.HTML
+	Default          string
+	ReadmeExample    string
+	Constraints      []entities.Constraint
+	Attributes       []argument
+}
+
+type output struct {
+	Anchor      string
+	Name        string
+	TypeString  string
+	Sensitive   bool
+	Description template.HTML
+}
+
+// Render writes the definition as a single self-contained HTML page
+func Render(writer io.Writer, definition entities.Doc) error {
+	const templatesPath = "templates/html/*"
+
+	t, err := template.New(pageTemplateName).Funcs(template.FuncMap(renderers.TemplatesFuncMap)).ParseFS(terradoc.TemplateFS, templatesPath)
+	if err != nil {
+		return err
+	}
+
+	pb := pageBuilder{
+		markdown: newMarkdownConverter(definition.References),
+		ids:      map[string]int{},
+	}
+
+	return t.ExecuteTemplate(writer, pageTemplateName, pb.build(definition))
+}
+
+type pageBuilder struct {
+	markdown markdownConverter
+	// ids counts the section ids in use so duplicated titles get unique ids
+	ids map[string]int
+}
+
+func (pb *pageBuilder) build(definition entities.Doc) page {
+	p := page{
+		Header:   definition.Header,
+		Sections: pb.sections(definition.Sections),
+	}
+
+	p.Nav = navItems(p.Sections)
+
+	if len(p.Nav) > 0 {
+		p.Title = p.Nav[0].plainTitle
+	}
+
+	return p
+}
+
+func (pb *pageBuilder) sections(sections []entities.Section) (result []section) {
+	for _, s := range sections {
+		sec := section{
+			Title:      template.HTML(pb.markdown.inline(s.Title)),
+			plainTitle: s.Title,
+			Level:      s.Level,
+			Content:    pb.markdown.convert(s.Content),
+		}
+
+		if sec.Level > maxHeadingLevel {
+			sec.Level = maxHeadingLevel
+		}
+
+		if s.Title != "" {
+			sec.ID = pb.sectionID(s.Title)
+		}
+
+		if s.Requirements != nil && !s.Requirements.IsEmpty() {
+			sec.Requirements = s.Requirements
+		}
+
+		sec.Resources = s.Resources
+
+		for _, v := range s.Variables {
+			sec.Variables = append(sec.Variables, pb.variable(v))
+		}
+
+		for _, o := range s.Outputs {
+			sec.Outputs = append(sec.Outputs, output{
+				Anchor:      "output-" + o.Name,
+				Name:        o.Name,
+				TypeString:  o.Type.DocString(),
+				Sensitive:   o.Sensitive,
+				Description: pb.markdown.convert(o.Description),
+			})
+		}
+
+		sec.Sections = pb.sections(s.SubSections)
+
+		if s.TOC {
+			sec.TOC = navItems(sec.Sections)
+		}
+
+		result = append(result, sec)
+	}
+
+	return result
+}
+
+// sectionID returns the URL fragment of the title as the markdown renderer does for headings.
+// Titles used more than once get a numeric suffix.
+func (pb *pageBuilder) sectionID(title string) string {
+	id := renderers.URLFragment(title)
+
+	count := pb.ids[id]
+	pb.ids[id]++
+
+	if count > 0 {
+		return fmt.Sprintf("%s-%d", id, count)
+	}
+
+	return id
+}
+
+func (pb *pageBuilder) variable(v entities.Variable) argument {
+	return argument{
+		Anchor:           "var-" + v.Name,
+		Name:             v.Name,
+		Type:             v.Type,
+		TypeString:       v.Type.DocString(),
+		Required:         v.Required,
+		ForcesRecreation: v.ForcesRecreation,
+		Sensitive:        v.Sensitive,
+		Ephemeral:        v.Ephemeral,
+		NonNullable:      !v.Nullable,
+		Description:      pb.markdown.convert(v.Description),
+		Default:          string(v.Default),
+		ReadmeExample:    v.ReadmeExample,
+		Constraints:      v.Constraints,
+		Attributes:       pb.attributes(v.Attributes, v.Name),
+	}
+}
+
+func (pb *pageBuilder) attributes(attributes []entities.Attribute, parentName string) (result []argument) {
+	for _, a := range attributes {
+		result = append(result, argument{
+			Anchor:           fmt.Sprintf("attr-%s-%s", parentName, a.Name),
+			Name:             a.Name,
+			Type:             a.Type,
+			TypeString:       a.Type.DocString(),
+			Required:         a.Required,
+			ForcesRecreation: a.ForcesRecreation,
+			Description:      pb.markdown.convert(a.Description),
+			Default:          string(a.Default),
+			ReadmeExample:    a.ReadmeExample,
+			Attributes:       pb.attributes(a.Attributes, fmt.Sprintf("%s-%s", parentName, a.Name)),
+		})
+	}
+
+	return result
+}
+
+// navItems returns the navigation items of the titled sections. Untitled sections are
+// skipped but their subsections are listed in their place.
+func navItems(sections []section) (items []navItem) {
+	for _, s := range sections {
+		if s.ID == "" {
+			items = append(items, navItems(s.Sections)...)
+
+			continue
+		}
+
+		items = append(items, navItem{ID: s.ID, Title: s.Title, Items: navItems(s.Sections), plainTitle: s.plainTitle})
+	}
+
+	return items
+}
@@ -0,0 +1,132 @@
+package html_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/parsers/docparser"
+	"github.com/mineiros-io/terradoc/internal/renderers/html"
+	"github.com/mineiros-io/terradoc/internal/types"
+	"github.com/mineiros-io/terradoc/test"
+)
+
+func TestRender(t *testing.T) {
+	doc, err := docparser.Parse(test.OpenFixture(t, "golden-input.tfdoc.hcl"), "golden-input.tfdoc.hcl")
+	assert.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	err = html.Render(buf, doc)
+	assert.NoError(t, err)
+
+	want := test.ReadFixture(t, "golden-readme.html")
+
+	if diff := cmp.Diff(string(want), buf.String()); diff != "" {
+		t.Errorf("Result is not expected (-want +got):\n%s", diff)
+	}
+}
+
+func TestRenderContent(t *testing.T) {
+	doc := entities.Doc{
+		Sections: []entities.Section{
+			{
+				Title:   "Usage",
+				Level:   1,
+				Content: "See [the docs][docs] and <script>alert(1)</script>\n\n```hcl\na = \"<b>\"\n```",
+				SubSections: []entities.Section{
+					{Title: "Usage", Level: 2},
+				},
+				Resources: []entities.Resource{
+					{
+						Kind:  entities.ResourceKind,
+						Type:  "aws_s3_bucket",
+						Name:  "this",
+						Count: true,
+						URL:   "https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/s3_bucket",
+					},
+				},
+				Requirements: &entities.Requirements{
+					TerraformVersion: ">= 1.0",
+					Providers: []entities.ProviderRequirement{
+						{Name: "aws", Source: "hashicorp/aws", Version: ">= 4.0"},
+					},
+				},
+				Variables: []entities.Variable{
+					{
+						Name:        "rule",
+						Type:        entities.Type{TFType: types.TerraformObject, Label: "rule"},
+						Description: "A *rule*.",
+						Attributes: []entities.Attribute{
+							{
+								Name:  "port",
+								Level: 1,
+								Type:  entities.Type{TFType: types.TerraformNumber},
+							},
+						},
+					},
+				},
+			},
+		},
+		References: []entities.Reference{
+			{Name: "docs", Value: "https://example.com/?a=1&b=2"},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	err := html.Render(buf, doc)
+	assert.NoError(t, err)
+
+	got := buf.String()
+
+	for _, want := range []string{
+		"<title>Usage</title>",
+		`<a href="https://example.com/?a=1&amp;b=2">the docs</a>`,
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+		`<pre><code class="language-hcl">a = &#34;&lt;b&gt;&#34;</code></pre>`,
+		`<section id="usage">`,
+		`<section id="usage-1">`,
+		`<li class="argument" id="var-rule">`,
+		`<li class="argument" id="attr-rule-port">`,
+		"<p>A <em>rule</em>.</p>",
+		`<details class="attributes" open>`,
+		"<li>Terraform: <code>&gt;= 1.0</code></li>",
+		"<li>Provider <code>aws</code> (<code>hashicorp/aws</code>): <code>&gt;= 4.0</code></li>",
+		`<li>Resource <a href="https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/s3_bucket"><code>aws_s3_bucket.this</code></a> <em>(count)</em></li>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("wanted %q in rendered page:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderUnsafeURLs(t *testing.T) {
+	doc := entities.Doc{
+		Sections: []entities.Section{
+			{
+				Title:   "Usage",
+				Level:   1,
+				Content: "[click](javascript:void) ![pwn](data:text/html,x) [ok](#usage) ![a \"`b`\"](img/b.png)",
+			},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	err := html.Render(buf, doc)
+	assert.NoError(t, err)
+
+	got := buf.String()
+
+	want := `<p>click pwn <a href="#usage">ok</a> <img src="img/b.png" alt="a &#34;b&#34;"></p>`
+	if !strings.Contains(got, want) {
+		t.Errorf("wanted %q in rendered page:\n%s", want, got)
+	}
+
+	for _, unwanted := range []string{"javascript:", "data:"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("unwanted %q in rendered page:\n%s", unwanted, got)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package html
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mineiros-io/terradoc/internal/entities"
+)
+
+// markdownConverter converts the markdown subset used in document contents and descriptions
+// to HTML: paragraphs, headings, lists, fenced code blocks, code spans, emphasis,
+// images, autolinks and inline or reference links. Everything else is escaped and rendered as text,
+// as are links and images whose URLs are not allowed by safeURL.
+type markdownConverter struct {
+	// references maps the lowercase HTML escaped reference names to their escaped URLs
+	references map[string]string
+}
+
+func newMarkdownConverter(references []entities.Reference) markdownConverter {
+	mc := markdownConverter{references: map[string]string{}}
+
+	for _, ref := range references {
+		mc.references[strings.ToLower(template.HTMLEscapeString(ref.Name))] = template.HTMLEscapeString(ref.Value)
+	}
+
+	return mc
+}
+
+var (
+	headingRegex  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	listItemRegex = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedRegex  = regexp.MustCompile(`^\s*[0-9]+[.)]\s+(.*)$`)
+	codeSpanRegex = regexp.MustCompile("`([^`]+)`")
+
+	inlineImageRegex = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	refImageRegex    = regexp.MustCompile(`!\[([^\]]*)\]\[([^\]]*)\]`)
+	inlineLinkRegex  = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]+)\)`)
+	refLinkRegex     = regexp.MustCompile(`\[([^\]]+)\]\[([^\]]*)\]`)
+	shortcutRegex    = regexp.MustCompile(`\[([^\]]+)\]`)
+	autolinkRegex    = regexp.MustCompile(`&lt;((?:https?://|mailto:)[^\s]+?)&gt;`)
+	entityRegex      = regexp.MustCompile(`&amp;([a-zA-Z]+|#[0-9]+);`)
+	strongRegex      = regexp.MustCompile(`(?s)(\*\*|__)(.+?)(\*\*|__)`)
+	emRegex          = regexp.MustCompile(`(^|[^\w*])[*_]([^*_\s][^*_]*?)[*_]($|[^\w*])`)
+
+	// placeholderRegex matches the placeholders used to protect already converted HTML.
+	// They are delimited by a control character that is not changed by HTML escaping.
+	placeholderRegex = regexp.MustCompile("\x1f([0-9]+)\x1f")
+	tagRegex         = regexp.MustCompile(`<[^>]*>`)
+)
+
+func (mc markdownConverter) convert(src string) template.HTML {
+	var (
+		buf       strings.Builder
+		paragraph []string
+		listItems []string
+		listTag   string
+	)
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			fmt.Fprintf(&buf, "<p>%s</p>\n", mc.inline(strings.Join(paragraph, "\n")))
+			paragraph = nil
+		}
+	}
+
+	flushList := func() {
+		if len(listItems) > 0 {
+			fmt.Fprintf(&buf, "<%s>\n", listTag)
+			for _, item := range listItems {
+				fmt.Fprintf(&buf, "<li>%s</li>\n", mc.inline(item))
+			}
+			fmt.Fprintf(&buf, "</%s>\n", listTag)
+			listItems = nil
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(src), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flushParagraph()
+			flushList()
+
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+
+			if lang != "" {
+				fmt.Fprintf(&buf, "<pre><code class=\"language-%s\">", template.HTMLEscapeString(lang))
+			} else {
+				buf.WriteString("<pre><code>")
+			}
+
+			buf.WriteString(template.HTMLEscapeString(strings.Join(code, "\n")))
+			buf.WriteString("</code></pre>\n")
+		case trimmed == "":
+			flushParagraph()
+			flushList()
+		case headingRegex.MatchString(trimmed):
+			flushParagraph()
+			flushList()
+
+			m := headingRegex.FindStringSubmatch(trimmed)
+			fmt.Fprintf(&buf, "<h%d>%s</h%d>\n", len(m[1]), mc.inline(m[2]), len(m[1]))
+		case listItemRegex.MatchString(line):
+			flushParagraph()
+
+			if listTag != "ul" {
+				flushList()
+				listTag = "ul"
+			}
+
+			listItems = append(listItems, listItemRegex.FindStringSubmatch(line)[1])
+		case orderedRegex.MatchString(line):
+			flushParagraph()
+
+			if listTag != "ol" {
+				flushList()
+				listTag = "ol"
+			}
+
+			listItems = append(listItems, orderedRegex.FindStringSubmatch(line)[1])
+		case len(listItems) > 0:
+			// lazy continuation of the last list item
+			listItems[len(listItems)-1] += "\n" + trimmed
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+
+	flushParagraph()
+	flushList()
+
+	return template.HTML(strings.TrimSuffix(buf.String(), "\n"))
+}
+
+// inline converts the inline elements of text to HTML
+func (mc markdownConverter) inline(text string) string {
+	var converted []string
+
+	protect := func(html string) string {
+		converted = append(converted, html)
+
+		return fmt.Sprintf("\x1f%d\x1f", len(converted)-1)
+	}
+
+	text = codeSpanRegex.ReplaceAllStringFunc(text, func(s string) string {
+		code := codeSpanRegex.FindStringSubmatch(s)[1]
+
+		return protect("<code>" + template.HTMLEscapeString(code) + "</code>")
+	})
+
+	text = template.HTMLEscapeString(text)
+	text = entityRegex.ReplaceAllString(text, "&$1;")
+
+	text = autolinkRegex.ReplaceAllStringFunc(text, func(s string) string {
+		url := autolinkRegex.FindStringSubmatch(s)[1]
+
+		return protect(fmt.Sprintf(`<a href="%s">%s</a>`, url, url))
+	})
+
+	// plain restores the placeholders of s without their markup, as needed in attribute values
+	plain := func(s string) string {
+		return placeholderRegex.ReplaceAllStringFunc(s, func(p string) string {
+			i, _ := strconv.Atoi(placeholderRegex.FindStringSubmatch(p)[1])
+
+			return tagRegex.ReplaceAllString(converted[i], "")
+		})
+	}
+
+	image := func(url, alt string) string {
+		alt = attributeValue(plain(alt))
+
+		src, ok := safeURL(url)
+		if !ok {
+			return alt
+		}
+
+		return protect(fmt.Sprintf(`<img src="%s" alt="%s">`, src, alt))
+	}
+
+	link := func(url, text string) string {
+		href, ok := safeURL(url)
+		if !ok {
+			return mc.emphasis(text)
+		}
+
+		return protect(fmt.Sprintf(`<a href="%s">%s</a>`, href, mc.emphasis(text)))
+	}
+
+	text = inlineImageRegex.ReplaceAllStringFunc(text, func(s string) string {
+		m := inlineImageRegex.FindStringSubmatch(s)
+
+		return image(m[2], m[1])
+	})
+
+	text = refImageRegex.ReplaceAllStringFunc(text, func(s string) string {
+		m := refImageRegex.FindStringSubmatch(s)
+
+		url, ok := mc.reference(m[2], m[1])
+		if !ok {
+			return s
+		}
+
+		return image(url, m[1])
+	})
+
+	text = inlineLinkRegex.ReplaceAllStringFunc(text, func(s string) string {
+		m := inlineLinkRegex.FindStringSubmatch(s)
+
+		return link(m[2], m[1])
+	})
+
+	text = refLinkRegex.ReplaceAllStringFunc(text, func(s string) string {
+		m := refLinkRegex.FindStringSubmatch(s)
+
+		url, ok := mc.reference(m[2], m[1])
+		if !ok {
+			return s
+		}
+
+		return link(url, m[1])
+	})
+
+	text = shortcutRegex.ReplaceAllStringFunc(text, func(s string) string {
+		m := shortcutRegex.FindStringSubmatch(s)
+
+		url, ok := mc.reference(m[1], m[1])
+		if !ok {
+			return s
+		}
+
+		return link(url, m[1])
+	})
+
+	text = mc.emphasis(text)
+
+	// restore the converted elements, which may contain other placeholders themselves
+	for placeholderRegex.MatchString(text) {
+		text = placeholderRegex.ReplaceAllStringFunc(text, func(s string) string {
+			i, _ := strconv.Atoi(placeholderRegex.FindStringSubmatch(s)[1])
+
+			return converted[i]
+		})
+	}
+
+	return text
+}
+
+func (mc markdownConverter) emphasis(text string) string {
+	text = strongRegex.ReplaceAllString(text, "<strong>$2</strong>")
+
+	return emRegex.ReplaceAllString(text, "$1<em>$2</em>$3")
+}
+
+// reference returns the URL of the reference with the given name. An empty name refers to the
+// reference named after the link text, as in `[text][]`.
+func (mc markdownConverter) reference(name, text string) (string, bool) {
+	if name == "" {
+		name = text
+	}
+
+	// placeholders of converted elements are not reference names
+	if placeholderRegex.MatchString(name) {
+		return "", false
+	}
+
+	url, ok := mc.references[strings.ToLower(name)]
+
+	return url, ok
+}
+
+// safeURL returns the URL of a link or image to be used in an attribute value. Only http, https
+// and mailto URLs, fragments and relative URLs are allowed, other URLs, e.g. `javascript:`, are not.
+func safeURL(escaped string) (string, bool) {
+	raw := html.UnescapeString(escaped)
+
+	if placeholderRegex.MatchString(raw) {
+		return "", false
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return attributeValue(escaped), true
+	}
+
+	return "", false
+}
+
+// attributeValue escapes text, which may contain HTML entities already, for an attribute value
+func attributeValue(text string) string {
+	return template.HTMLEscapeString(html.UnescapeString(text))
+}
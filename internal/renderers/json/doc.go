@@ -0,0 +1,92 @@
+// Package json renders a terradoc document as JSON so it can be consumed as data.
+//
+// The JSON document is versioned by its top-level `schema_version` field. Fields
+// may be added without changing the version, but fields are never removed or
+// changed in meaning without a new version. The format of version 1 is:
+//
+//	{
+//	  "schema_version": "1",
+//	  "header": {
+//	    "image": "<image url>",
+//	    "url": "<link url>",
+//	    "badges": [{"name": "", "image": "", "url": "", "text": ""}]
+//	  },
+//	  "sections": [<section>],
+//	  "references": [{"name": "", "value": ""}]
+//	}
+//
+// A section is:
+//
+//	{
+//	  "title": "",
+//	  "level": 1,
+//	  "content": "<markdown>",
+//	  "toc": false,
+//	  "requirements": {
+//	    "terraform": ">= 1.0",
+//	    "providers": [{"name": "aws", "source": "hashicorp/aws", "version": ">= 4.0"}]
+//	  },
+//	  "generated": "resources",
+//	  "resources": [<resource>],
+//	  "variables": [<variable>],
+//	  "outputs": [<output>],
+//	  "sections": [<section>]
+//	}
+//
+// A variable is:
+//
+//	{
+//	  "name": "",
+//	  "type": <type>,
+//	  "description": "",
+//	  "default": <any JSON value>,
+//	  "required": false,
+//	  "forces_recreation": false,
+//	  "sensitive": false,
+//	  "nullable": true,
+//	  "ephemeral": false,
+//	  "readme_example": "",
+//	  "constraints": [{"condition": "length(var.name) > 3", "error_message": ""}],
+//	  "attributes": [<attribute>]
+//	}
+//
+// An attribute has the same fields as a variable, except for `sensitive`, `nullable`,
+// `ephemeral` and `constraints`, plus its nesting `level`. An output is:
+//
+//	{"name": "", "type": <type>, "description": "", "sensitive": false}
+//
+// A resource is a `resource`, `data` or `module` block of the module listed by
+// sections generating "resources":
+//
+//	{
+//	  "kind": "resource",
+//	  "type": "aws_s3_bucket",
+//	  "name": "this",
+//	  "provider": "aws",
+//	  "source": "<module source>",
+//	  "count": false,
+//	  "for_each": false,
+//	  "url": "<registry documentation>"
+//	}
+//
+// A type is:
+//
+//	{
+//	  "name": "list",
+//	  "label": "",
+//	  "nested": <type>,
+//	  "fields": {"<attribute>": <type>},
+//	  "elements": [<type>],
+//	  "optional": false,
+//	  "default": null,
+//	  "expression": "list(object(beer))"
+//	}
+//
+// where `name` is one of bool, string, number, list, set, map, object, tuple,
+// any or resource, `label` names an object or resource type and `expression` is
+// the complete type as a string. `nested` is the element type of list, set and
+// map types, `fields` are the attribute types of objects declared as
+// `object({...})` and `elements` are the element types of tuples. `optional` and
+// `default` are set for object attribute types declared with `optional(...)`.
+// Optional fields are omitted when empty.
+package json
@@ -0,0 +1,301 @@
+package json
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/types"
+)
+
+// SchemaVersion is the version of the JSON format described in the package documentation
+const SchemaVersion = "1"
+
+type document struct {
+	SchemaVersion string      `json:"schema_version"`
+	Header        header      `json:"header"`
+	Sections      []section   `json:"sections"`
+	References    []reference `json:"references"`
+}
+
+type header struct {
+	Image  string  `json:"image,omitempty"`
+	URL    string  `json:"url,omitempty"`
+	Badges []badge `json:"badges,omitempty"`
+}
+
+type badge struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+	URL   string `json:"url"`
+	Text  string `json:"text"`
+}
+
+type section struct {
+	Title        string        `json:"title,omitempty"`
+	Level        int           `json:"level"`
+	Content      string        `json:"content,omitempty"`
+	TOC          bool          `json:"toc"`
+	Requirements *requirements `json:"requirements,omitempty"`
+	Generated    string        `json:"generated,omitempty"`
+	Resources    []resource    `json:"resources,omitempty"`
+	Variables    []variable    `json:"variables,omitempty"`
+	Outputs      []output      `json:"outputs,omitempty"`
+	Sections     []section     `json:"sections,omitempty"`
+}
+
+type requirements struct {
+	Terraform string     `json:"terraform,omitempty"`
+	Providers []provider `json:"providers,omitempty"`
+}
+
+type resource struct {
+	Kind     string `json:"kind"`
+	Type     string `json:"type,omitempty"`
+	Name     string `json:"name"`
+	Provider string `json:"provider,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Count    bool   `json:"count"`
+	ForEach  bool   `json:"for_each"`
+	URL      string `json:"url,omitempty"`
+}
+
+type provider struct {
+	Name    string `json:"name"`
+	Source  string `json:"source,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+type variable struct {
+	Name             string          `json:"name"`
+	Type             typeDefinition  `json:"type"`
+	Description      string          `json:"description,omitempty"`
+	Default          json.RawMessage `json:"default,omitempty"`
+	Required         bool            `json:"required"`
+	ForcesRecreation bool            `json:"forces_recreation"`
+	Sensitive        bool            `json:"sensitive"`
+	Nullable         bool            `json:"nullable"`
+	Ephemeral        bool            `json:"ephemeral"`
+	ReadmeExample    string          `json:"readme_example,omitempty"`
+	Constraints      []constraint    `json:"constraints,omitempty"`
+	Attributes       []attribute     `json:"attributes,omitempty"`
+}
+
+type constraint struct {
+	Condition    string `json:"condition"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+type attribute struct {
+	Name             string          `json:"name"`
+	Level            int             `json:"level"`
+	Type             typeDefinition  `json:"type"`
+	Description      string          `json:"description,omitempty"`
+	Default          json.RawMessage `json:"default,omitempty"`
+	Required         bool            `json:"required"`
+	ForcesRecreation bool            `json:"forces_recreation"`
+	ReadmeExample    string          `json:"readme_example,omitempty"`
+	Attributes       []attribute     `json:"attributes,omitempty"`
+}
+
+type output struct {
+	Name        string         `json:"name"`
+	Type        typeDefinition `json:"type"`
+	Description string         `json:"description,omitempty"`
+	Sensitive   bool           `json:"sensitive"`
+}
+
+type reference struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type typeDefinition struct {
+	Name       string                    `json:"name,omitempty"`
+	Label      string                    `json:"label,omitempty"`
+	Nested     *typeDefinition           `json:"nested,omitempty"`
+	Fields     map[string]typeDefinition `json:"fields,omitempty"`
+	Elements   []typeDefinition          `json:"elements,omitempty"`
+	Optional   bool                      `json:"optional,omitempty"`
+	Default    json.RawMessage           `json:"default,omitempty"`
+	Expression string                    `json:"expression,omitempty"`
+}
+
+// Render writes the definition as an indented JSON document
+func Render(writer io.Writer, definition entities.Doc) error {
+	enc := json.NewEncoder(writer)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(newDocument(definition))
+}
+
+func newDocument(definition entities.Doc) document {
+	doc := document{
+		SchemaVersion: SchemaVersion,
+		Header: header{
+			Image: definition.Header.Image,
+			URL:   definition.Header.URL,
+		},
+		Sections:   newSections(definition.Sections),
+		References: []reference{},
+	}
+
+	for _, b := range definition.Header.Badges {
+		doc.Header.Badges = append(doc.Header.Badges, badge{Name: b.Name, Image: b.Image, URL: b.URL, Text: b.Text})
+	}
+
+	for _, ref := range definition.References {
+		doc.References = append(doc.References, reference{Name: ref.Name, Value: ref.Value})
+	}
+
+	return doc
+}
+
+func newRequirements(r entities.Requirements) *requirements {
+	result := &requirements{Terraform: r.TerraformVersion}
+
+	for _, p := range r.Providers {
+		result.Providers = append(result.Providers, provider{Name: p.Name, Source: p.Source, Version: p.Version})
+	}
+
+	return result
+}
+
+func newSections(sections []entities.Section) []section {
+	result := []section{}
+
+	for _, s := range sections {
+		sec := section{
+			Title:   s.Title,
+			Level:   s.Level,
+			Content: s.Content,
+			TOC:     s.TOC,
+		}
+
+		if s.Requirements != nil && !s.Requirements.IsEmpty() {
+			sec.Requirements = newRequirements(*s.Requirements)
+		}
+
+		sec.Generated = s.Generated
+
+		for _, r := range s.Resources {
+			sec.Resources = append(sec.Resources, resource{
+				Kind:     r.Kind,
+				Type:     r.Type,
+				Name:     r.Name,
+				Provider: r.Provider,
+				Source:   r.Source,
+				Count:    r.Count,
+				ForEach:  r.ForEach,
+				URL:      r.URL,
+			})
+		}
+
+		for _, v := range s.Variables {
+			sec.Variables = append(sec.Variables, newVariable(v))
+		}
+
+		for _, o := range s.Outputs {
+			sec.Outputs = append(sec.Outputs, output{Name: o.Name, Type: newType(o.Type), Description: o.Description, Sensitive: o.Sensitive})
+		}
+
+		if len(s.SubSections) > 0 {
+			sec.Sections = newSections(s.SubSections)
+		}
+
+		result = append(result, sec)
+	}
+
+	return result
+}
+
+func newVariable(v entities.Variable) variable {
+	return variable{
+		Name:             v.Name,
+		Type:             newType(v.Type),
+		Description:      v.Description,
+		Default:          newDefault(v.Default),
+		Required:         v.Required,
+		ForcesRecreation: v.ForcesRecreation,
+		Sensitive:        v.Sensitive,
+		Nullable:         v.Nullable,
+		Ephemeral:        v.Ephemeral,
+		ReadmeExample:    v.ReadmeExample,
+		Constraints:      newConstraints(v.Constraints),
+		Attributes:       newAttributes(v.Attributes),
+	}
+}
+
+func newConstraints(constraints []entities.Constraint) (result []constraint) {
+	for _, c := range constraints {
+		result = append(result, constraint{Condition: c.Condition, ErrorMessage: c.ErrorMessage})
+	}
+
+	return result
+}
+
+func newAttributes(attributes []entities.Attribute) (result []attribute) {
+	for _, a := range attributes {
+		result = append(result, attribute{
+			Name:             a.Name,
+			Level:            a.Level,
+			Type:             newType(a.Type),
+			Description:      a.Description,
+			Default:          newDefault(a.Default),
+			Required:         a.Required,
+			ForcesRecreation: a.ForcesRecreation,
+			ReadmeExample:    a.ReadmeExample,
+			Attributes:       newAttributes(a.Attributes),
+		})
+	}
+
+	return result
+}
+
+func newType(t entities.Type) typeDefinition {
+	def := typeDefinition{
+		Label:      t.Label,
+		Expression: t.AsString(),
+	}
+
+	if t.TFType != types.TerraformEmptyType {
+		def.Name = t.TFType.String()
+	} else {
+		def.Expression = ""
+	}
+
+	if t.HasNestedType() {
+		nested := newType(*t.Nested)
+		def.Nested = &nested
+	}
+
+	if t.HasFields() {
+		def.Fields = map[string]typeDefinition{}
+		for name, field := range t.Fields {
+			def.Fields[name] = newType(field)
+		}
+	}
+
+	for _, elem := range t.Elements {
+		def.Elements = append(def.Elements, newType(elem))
+	}
+
+	if t.Optional {
+		def.Optional = true
+		def.Default = newDefault(t.Default)
+	}
+
+	return def
+}
+
+// newDefault returns the default value as JSON. Defaults that are not valid JSON, e.g. references
+// to other variables, are returned as JSON strings.
+func newDefault(src json.RawMessage) json.RawMessage {
+	if len(src) == 0 || json.Valid(src) {
+		return src
+	}
+
+	quoted, _ := json.Marshal(string(src))
+
+	return quoted
+}
@@ -0,0 +1,160 @@
+package json_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/parsers/docparser"
+	jsonrenderer "github.com/mineiros-io/terradoc/internal/renderers/json"
+	"github.com/mineiros-io/terradoc/internal/types"
+	"github.com/mineiros-io/terradoc/test"
+)
+
+func TestRender(t *testing.T) {
+	doc, err := docparser.Parse(test.OpenFixture(t, "parser-input.tfdoc.hcl"), "parser-input.tfdoc.hcl")
+	assert.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	err = jsonrenderer.Render(buf, doc)
+	assert.NoError(t, err)
+
+	want := test.ReadFixture(t, "golden-doc.json")
+
+	if diff := cmp.Diff(string(want), buf.String()); diff != "" {
+		t.Errorf("Result is not expected (-want +got):\n%s", diff)
+	}
+}
+
+func TestRenderDefaults(t *testing.T) {
+	doc := entities.Doc{
+		Sections: []entities.Section{
+			{
+				Variables: []entities.Variable{
+					{
+						Name:    "valid_json",
+						Type:    entities.Type{TFType: types.TerraformMap, Nested: &entities.Type{TFType: types.TerraformString}},
+						Default: json.RawMessage(`{"a":"b"}`),
+					},
+					{
+						Name:    "reference",
+						Type:    entities.Type{TFType: types.TerraformString},
+						Default: json.RawMessage(`var.name`),
+					},
+				},
+			},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	err := jsonrenderer.Render(buf, doc)
+	assert.NoError(t, err)
+
+	var got struct {
+		SchemaVersion string `json:"schema_version"`
+		Sections      []struct {
+			Variables []struct {
+				Default interface{} `json:"default"`
+			} `json:"variables"`
+		} `json:"sections"`
+	}
+
+	err = json.Unmarshal(buf.Bytes(), &got)
+	assert.NoError(t, err)
+
+	assert.EqualStrings(t, jsonrenderer.SchemaVersion, got.SchemaVersion)
+
+	if diff := cmp.Diff(map[string]interface{}{"a": "b"}, got.Sections[0].Variables[0].Default); diff != "" {
+		t.Errorf("Default is not expected (-want +got):\n%s", diff)
+	}
+
+	assert.EqualStrings(t, "var.name", got.Sections[0].Variables[1].Default.(string))
+}
+
+func TestRenderRequirements(t *testing.T) {
+	doc := entities.Doc{
+		Sections: []entities.Section{
+			{
+				Requirements: &entities.Requirements{
+					TerraformVersion: ">= 1.0",
+					Providers: []entities.ProviderRequirement{
+						{Name: "aws", Source: "hashicorp/aws", Version: ">= 4.0"},
+					},
+				},
+			},
+			{
+				Requirements: &entities.Requirements{},
+			},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	err := jsonrenderer.Render(buf, doc)
+	assert.NoError(t, err)
+
+	var got struct {
+		Sections []struct {
+			Requirements interface{} `json:"requirements"`
+		} `json:"sections"`
+	}
+
+	err = json.Unmarshal(buf.Bytes(), &got)
+	assert.NoError(t, err)
+
+	want := map[string]interface{}{
+		"terraform": ">= 1.0",
+		"providers": []interface{}{
+			map[string]interface{}{"name": "aws", "source": "hashicorp/aws", "version": ">= 4.0"},
+		},
+	}
+
+	if diff := cmp.Diff(want, got.Sections[0].Requirements); diff != "" {
+		t.Errorf("Requirements are not expected (-want +got):\n%s", diff)
+	}
+
+	if got.Sections[1].Requirements != nil {
+		t.Errorf("Expected empty requirements to be omitted, got %v", got.Sections[1].Requirements)
+	}
+}
+
+func TestRenderResources(t *testing.T) {
+	doc := entities.Doc{
+		Sections: []entities.Section{
+			{
+				Generated: "resources",
+				Resources: []entities.Resource{
+					{Kind: entities.ResourceKind, Type: "aws_s3_bucket", Name: "this", Provider: "aws", Count: true},
+					{Kind: entities.ModuleKind, Name: "vpc", Source: "./vpc"},
+				},
+			},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	err := jsonrenderer.Render(buf, doc)
+	assert.NoError(t, err)
+
+	var got struct {
+		Sections []struct {
+			Generated string        `json:"generated"`
+			Resources []interface{} `json:"resources"`
+		} `json:"sections"`
+	}
+
+	err = json.Unmarshal(buf.Bytes(), &got)
+	assert.NoError(t, err)
+
+	assert.EqualStrings(t, "resources", got.Sections[0].Generated)
+
+	want := []interface{}{
+		map[string]interface{}{"kind": "resource", "type": "aws_s3_bucket", "name": "this", "provider": "aws", "count": true, "for_each": false},
+		map[string]interface{}{"kind": "module", "name": "vpc", "source": "./vpc", "count": false, "for_each": false},
+	}
+
+	if diff := cmp.Diff(want, got.Sections[0].Resources); diff != "" {
+		t.Errorf("Resources are not expected (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,59 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+)
+
+const (
+	DefaultBeginMarker = "<!-- BEGIN_TFDOC -->"
+	DefaultEndMarker   = "<!-- END_TFDOC -->"
+)
+
+// Inject replaces the content between the begin and end markers in dst with content.
+// The markers are kept so injecting into the result again replaces the same content.
+// It fails if dst does not contain exactly one pair of markers.
+func Inject(dst, content []byte, beginMarker, endMarker string) ([]byte, error) {
+	begin := []byte(beginMarker)
+	end := []byte(endMarker)
+
+	beginCount := bytes.Count(dst, begin)
+	endCount := bytes.Count(dst, end)
+
+	switch {
+	case beginMarker == endMarker:
+		return nil, fmt.Errorf("begin and end markers must be different, both are %q", beginMarker)
+	case bytes.Contains(begin, end) || bytes.Contains(end, begin):
+		// the marker contained in the other would be found inside of it
+		return nil, fmt.Errorf("begin marker %q and end marker %q must not contain each other", beginMarker, endMarker)
+	case beginCount == 0 && endCount == 0:
+		return nil, fmt.Errorf("markers %q and %q not found", beginMarker, endMarker)
+	case beginCount == 0:
+		return nil, fmt.Errorf("begin marker %q not found", beginMarker)
+	case endCount == 0:
+		return nil, fmt.Errorf("end marker %q not found", endMarker)
+	case beginCount > 1 || endCount > 1:
+		return nil, fmt.Errorf("expected a single pair of markers but found %d %q and %d %q", beginCount, beginMarker, endCount, endMarker)
+	}
+
+	beginIdx := bytes.Index(dst, begin)
+	endIdx := bytes.Index(dst, end)
+
+	if endIdx < beginIdx {
+		return nil, fmt.Errorf("end marker %q found before begin marker %q", endMarker, beginMarker)
+	}
+
+	if endIdx < beginIdx+len(begin) {
+		return nil, fmt.Errorf("end marker %q overlaps begin marker %q", endMarker, beginMarker)
+	}
+
+	var result bytes.Buffer
+
+	result.Write(dst[:beginIdx+len(begin)])
+	result.WriteString("\n")
+	result.Write(bytes.Trim(content, "\n"))
+	result.WriteString("\n")
+	result.Write(dst[endIdx:])
+
+	return result.Bytes(), nil
+}
@@ -0,0 +1,115 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/internal/renderers/markdown"
+)
+
+func TestInject(t *testing.T) {
+	const (
+		begin = markdown.DefaultBeginMarker
+		end   = markdown.DefaultEndMarker
+	)
+
+	for _, tt := range []struct {
+		desc    string
+		dst     string
+		content string
+		want    string
+	}{
+		{
+			desc:    "when markers are empty",
+			dst:     "# Intro\n\n" + begin + end + "\n\nOutro\n",
+			content: "generated\n",
+			want:    "# Intro\n\n" + begin + "\ngenerated\n" + end + "\n\nOutro\n",
+		},
+		{
+			desc:    "when markers have previously generated content",
+			dst:     "# Intro\n" + begin + "\nold\ncontent\n" + end + "\n",
+			content: "\n\nnew content\n\n",
+			want:    "# Intro\n" + begin + "\nnew content\n" + end + "\n",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := markdown.Inject([]byte(tt.dst), []byte(tt.content), begin, end)
+			assert.NoError(t, err)
+			assert.EqualStrings(t, tt.want, string(got))
+
+			// injecting the same content again must not change the result
+			again, err := markdown.Inject(got, []byte(tt.content), begin, end)
+			assert.NoError(t, err)
+			assert.EqualStrings(t, string(got), string(again))
+		})
+	}
+}
+
+func TestInjectInvalidMarkers(t *testing.T) {
+	const (
+		begin = markdown.DefaultBeginMarker
+		end   = markdown.DefaultEndMarker
+	)
+
+	for _, tt := range []struct {
+		desc                 string
+		dst                  string
+		begin, end           string
+		wantErrorMsgContains string
+	}{
+		{
+			desc:                 "when markers are missing",
+			dst:                  "# README\n",
+			wantErrorMsgContains: "not found",
+		},
+		{
+			desc:                 "when end marker is missing",
+			dst:                  begin + "\n",
+			wantErrorMsgContains: "end marker",
+		},
+		{
+			desc:                 "when begin marker is missing",
+			dst:                  end + "\n",
+			wantErrorMsgContains: "begin marker",
+		},
+		{
+			desc:                 "when markers are out of order",
+			dst:                  end + "\n" + begin + "\n",
+			wantErrorMsgContains: "found before begin marker",
+		},
+		{
+			desc:                 "when there are multiple pairs of markers",
+			dst:                  begin + end + begin + end,
+			wantErrorMsgContains: "expected a single pair of markers",
+		},
+		{
+			desc:                 "when the end marker is part of the begin marker",
+			dst:                  "<!-- BEGIN -->\n<!-- END -->\n",
+			begin:                "<!-- BEGIN -->",
+			end:                  "-->",
+			wantErrorMsgContains: "must not contain each other",
+		},
+		{
+			desc:                 "when the end marker overlaps the begin marker",
+			dst:                  "<!-- BEGIN -->\n",
+			begin:                "<!-- BEGIN",
+			end:                  "BEGIN -->",
+			wantErrorMsgContains: "overlaps begin marker",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			beginMarker, endMarker := begin, end
+			if tt.begin != "" {
+				beginMarker, endMarker = tt.begin, tt.end
+			}
+
+			_, err := markdown.Inject([]byte(tt.dst), []byte("content"), beginMarker, endMarker)
+			assert.Error(t, err)
+
+			if !strings.Contains(err.Error(), tt.wantErrorMsgContains) {
+				t.Errorf("Expected error message to contain %q but got %q instead", tt.wantErrorMsgContains, err.Error())
+			}
+		})
+	}
+}
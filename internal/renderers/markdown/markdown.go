@@ -14,3 +14,14 @@ func Render(writer io.Writer, definition entities.Doc) error {
 
 	return mdWriter.writeDefinition(definition)
 }
+
+// RenderWithTemplates renders the definition like Render but with the embedded templates
+// overlaid by the templates found in templatesDir
+func RenderWithTemplates(writer io.Writer, definition entities.Doc, templatesDir string) error {
+	mdWriter, err := newMarkdownWriterWithTemplates(writer, templatesDir)
+	if err != nil {
+		return err
+	}
+
+	return mdWriter.writeDefinition(definition)
+}
@@ -2,6 +2,8 @@ package markdown_test
 
 import (
 	"bytes"
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -39,7 +41,8 @@ func TestRender(t *testing.T) {
 						},
 						Variables: []entities.Variable{
 							{
-								Name: "simple_string",
+								Name:     "simple_string",
+								Nullable: true,
 								Type: entities.Type{
 									TFType: types.TerraformString,
 								},
@@ -52,8 +55,9 @@ func TestRender(t *testing.T) {
 						Title: "SubSection 2",
 						Variables: []entities.Variable{
 							{
-								Name:    "test_objects",
-								Default: []byte("[]"),
+								Name:     "test_objects",
+								Nullable: true,
+								Default:  []byte("[]"),
 								Type: entities.Type{
 									TFType: types.TerraformList,
 									Nested: &entities.Type{
@@ -112,3 +116,58 @@ func TestRender(t *testing.T) {
 		t.Errorf("Expected golden file to match result (-want +got):\n%s", diff)
 	}
 }
+
+func TestRenderWithTemplates(t *testing.T) {
+	definition := entities.Doc{
+		Sections: []entities.Section{
+			{
+				Title: "Arguments",
+				Level: 1,
+				Variables: []entities.Variable{
+					{
+						Name:        "name",
+						Type:        entities.Type{TFType: types.TerraformString},
+						Description: "The name.",
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("OverridesTemplatesByName", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTemplate(t, dir, "variable.md", `{{define "variable"}}* {{.Name}} ({{template "variableType" .Type}}){{newline}}{{end}}`)
+
+		buf := new(bytes.Buffer)
+		err := markdown.RenderWithTemplates(buf, definition, dir)
+		assert.NoError(t, err)
+
+		want := "# Arguments\n\n* name (string)\n\n"
+
+		if diff := cmp.Diff(want, buf.String()); diff != "" {
+			t.Errorf("Result is not expected (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("MissingDefine", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTemplate(t, dir, "variable.md", `* {{.Name}}`)
+
+		err := markdown.RenderWithTemplates(new(bytes.Buffer), definition, dir)
+		if err == nil || !strings.Contains(err.Error(), `must define "variable"`) {
+			t.Errorf("expected missing define error, got: %v", err)
+		}
+	})
+
+	t.Run("MissingDirectory", func(t *testing.T) {
+		err := markdown.RenderWithTemplates(new(bytes.Buffer), definition, filepath.Join(t.TempDir(), "missing"))
+		assert.Error(t, err)
+	})
+}
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+	assert.NoError(t, err)
+}
@@ -3,6 +3,10 @@ package markdown
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"text/template"
 
 	"github.com/mineiros-io/terradoc"
@@ -21,16 +25,43 @@ const (
 	headerTemplateName          = "header"
 	tocTemplateName             = "toc"
 	outputTemplateName          = "output"
+	requirementsTemplateName    = "requirements"
+	resourcesTemplateName       = "resources"
+
+	varTypeTemplateName = "variableType"
 
 	varNestingLevel = 0
 )
 
+// overridableTemplates are the templates that can be replaced by user templates.
+// A user template file named after one of them must define it.
+var overridableTemplates = []string{
+	variableTemplateName,
+	attributeTemplateName,
+	outputTemplateName,
+	sectionTemplateName,
+	headerTemplateName,
+	tocTemplateName,
+	referencesTemplateName,
+	requirementsTemplateName,
+	resourcesTemplateName,
+	typeDescriptionTemplateName,
+	varTypeTemplateName,
+}
+
 type markdownWriter struct {
 	writer io.Writer
 	templ  *template.Template
 }
 
 func newMarkdownWriter(writer io.Writer) (*markdownWriter, error) {
+	return newMarkdownWriterWithTemplates(writer, "")
+}
+
+// newMarkdownWriterWithTemplates creates a writer using the embedded templates overlaid by the
+// `*.md` templates of templatesDir. Templates defined in user files replace the embedded
+// templates with the same name.
+func newMarkdownWriterWithTemplates(writer io.Writer, templatesDir string) (*markdownWriter, error) {
 	const templatesPath = "templates/markdown/*"
 
 	t, err := template.New(templateName).Funcs(renderers.TemplatesFuncMap).ParseFS(terradoc.TemplateFS, templatesPath)
@@ -38,9 +69,74 @@ func newMarkdownWriter(writer io.Writer) (*markdownWriter, error) {
 		return nil, err
 	}
 
+	if templatesDir != "" {
+		if err := overlayTemplates(t, templatesDir); err != nil {
+			return nil, err
+		}
+	}
+
 	return &markdownWriter{writer: writer, templ: t}, nil
 }
 
+func overlayTemplates(t *template.Template, dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return fmt.Errorf("reading templates directory: %v", err)
+		}
+
+		if !info.IsDir() {
+			return fmt.Errorf("templates directory %q is not a directory", dir)
+		}
+	}
+
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("reading template: %v", err)
+		}
+
+		name := strings.TrimSuffix(filepath.Base(file), ".md")
+
+		// parse the file on its own first so only its own definitions are checked
+		userTempl, err := template.New(filepath.Base(file)).Funcs(renderers.TemplatesFuncMap).Parse(string(content))
+		if err != nil {
+			return fmt.Errorf("parsing template %q: %v", file, err)
+		}
+
+		if isOverridable(name) && userTempl.Lookup(name) == nil {
+			return fmt.Errorf("template %q must define %q, e.g. {{define %q}}...{{end}}", file, name, name)
+		}
+
+		for _, defined := range userTempl.Templates() {
+			if defined.Tree == nil {
+				continue
+			}
+
+			if _, err := t.AddParseTree(defined.Name(), defined.Tree); err != nil {
+				return fmt.Errorf("adding template %q from %q: %v", defined.Name(), file, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isOverridable(name string) bool {
+	for _, n := range overridableTemplates {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (mw *markdownWriter) writeDefinition(definition entities.Doc) error {
 	if err := mw.writeHeader(definition.Header); err != nil {
 		return err
@@ -91,6 +187,14 @@ func (mw *markdownWriter) writeSection(section entities.Section) error {
 		}
 	}
 
+	if err := mw.writeRequirements(section.Requirements); err != nil {
+		return err
+	}
+
+	if err := mw.writeResources(section.Resources); err != nil {
+		return err
+	}
+
 	if err := mw.writeVariables(section.Variables); err != nil {
 		return err
 	}
@@ -102,6 +206,22 @@ func (mw *markdownWriter) writeSection(section entities.Section) error {
 	return mw.writeSections(section.SubSections)
 }
 
+func (mw *markdownWriter) writeRequirements(requirements *entities.Requirements) error {
+	if requirements == nil || requirements.IsEmpty() {
+		return nil
+	}
+
+	return mw.writeTemplate(requirementsTemplateName, requirements)
+}
+
+func (mw *markdownWriter) writeResources(resources []entities.Resource) error {
+	if len(resources) == 0 {
+		return nil
+	}
+
+	return mw.writeTemplate(resourcesTemplateName, resources)
+}
+
 func (mw *markdownWriter) writeTemplate(templateName string, v interface{}) error {
 	return mw.templ.ExecuteTemplate(mw.writer, templateName, v)
 }
@@ -122,7 +122,8 @@ func TestWriteVariable(t *testing.T) {
 		{
 			desc: "a required string variable with description and default that forces recreation",
 			variable: entities.Variable{
-				Name: "string_variable",
+				Name:     "string_variable",
+				Nullable: true,
 				Type: entities.Type{
 					TFType: types.TerraformString,
 				},
@@ -140,7 +141,8 @@ func TestWriteVariable(t *testing.T) {
 		{
 			desc: "an optional number variable with defaults that forces recreation",
 			variable: entities.Variable{
-				Name: "number_variable",
+				Name:     "number_variable",
+				Nullable: true,
 				Type: entities.Type{
 					TFType: types.TerraformNumber,
 				},
@@ -156,7 +158,8 @@ func TestWriteVariable(t *testing.T) {
 		{
 			desc: "a bool variable",
 			variable: entities.Variable{
-				Name: "bool_variable",
+				Name:     "bool_variable",
+				Nullable: true,
 				Type: entities.Type{
 					TFType: types.TerraformBool,
 				},
@@ -167,10 +170,26 @@ func TestWriteVariable(t *testing.T) {
 				item: "- [**`bool_variable`**](#var-bool_variable): *(Optional `bool`)*<a name=\"var-bool_variable\"></a>",
 			},
 		},
+		{
+			desc: "a sensitive, ephemeral and non-nullable variable",
+			variable: entities.Variable{
+				Name: "secret_variable",
+				Type: entities.Type{
+					TFType: types.TerraformString,
+				},
+				Required:  true,
+				Sensitive: true,
+				Ephemeral: true,
+			},
+			want: mdVariable{
+				item: "- [**`secret_variable`**](#var-secret_variable): *(**Required** `string`, Sensitive, Ephemeral, Not nullable)*<a name=\"var-secret_variable\"></a>",
+			},
+		},
 		{
 			desc: "an object variable with readme example",
 			variable: entities.Variable{
-				Name: "obj_variable",
+				Name:     "obj_variable",
+				Nullable: true,
 				Type: entities.Type{
 					TFType: types.TerraformObject,
 				},
@@ -288,6 +307,58 @@ func TestWriteAttributeWithNested(t *testing.T) {
 	t.Skip("write rendering tests for nested attributes once we know more about it")
 }
 
+func TestWriteType(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		ty   entities.Type
+		want string
+	}{
+		{
+			desc: "a labeled object",
+			ty:   entities.Type{TFType: types.TerraformObject, Label: "rule"},
+			want: "The `rule` object accepts the following attributes:",
+		},
+		{
+			desc: "a list of labeled objects",
+			ty: entities.Type{
+				TFType: types.TerraformList,
+				Nested: &entities.Type{TFType: types.TerraformObject, Label: "rule"},
+			},
+			want: "Each `rule` object in the list accepts the following attributes:",
+		},
+		{
+			desc: "a map of lists of labeled objects",
+			ty: entities.Type{
+				TFType: types.TerraformMap,
+				Nested: &entities.Type{
+					TFType: types.TerraformList,
+					Nested: &entities.Type{TFType: types.TerraformObject, Label: "rule"},
+				},
+			},
+			want: "Each `rule` object in the list in the map accepts the following attributes:",
+		},
+		{
+			desc: "a set of objects without label",
+			ty: entities.Type{
+				TFType: types.TerraformSet,
+				Nested: &entities.Type{TFType: types.TerraformObject},
+			},
+			want: "Each object in the set accepts the following attributes:",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+
+			writer := newTestWriter(t, buf)
+
+			err := writer.writeType(tt.ty, 0)
+			assert.NoError(t, err)
+
+			assert.EqualStrings(t, tt.want, strings.TrimSpace(buf.String()))
+		})
+	}
+}
+
 func TestWriteOutput(t *testing.T) {
 	for _, tt := range []struct {
 		desc   string
@@ -359,6 +430,94 @@ func TestWriteOutput(t *testing.T) {
 	}
 }
 
+func TestWriteRequirements(t *testing.T) {
+	for _, tt := range []struct {
+		desc         string
+		requirements *entities.Requirements
+		want         string
+	}{
+		{
+			desc: "terraform and providers",
+			requirements: &entities.Requirements{
+				TerraformVersion: ">= 1.0",
+				Providers: []entities.ProviderRequirement{
+					{Name: "aws", Source: "hashicorp/aws", Version: ">= 4.0"},
+					{Name: "random"},
+				},
+			},
+			want: "- Terraform: `>= 1.0`\n" +
+				"- Provider `aws` (`hashicorp/aws`): `>= 4.0`\n" +
+				"- Provider `random`\n\n",
+		},
+		{
+			desc: "only providers",
+			requirements: &entities.Requirements{
+				Providers: []entities.ProviderRequirement{
+					{Name: "google", Version: "~> 4.0"},
+				},
+			},
+			want: "- Provider `google`: `~> 4.0`\n\n",
+		},
+		{
+			desc:         "empty requirements",
+			requirements: &entities.Requirements{},
+		},
+		{
+			desc: "no requirements",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+
+			writer := newTestWriter(t, buf)
+
+			err := writer.writeRequirements(tt.requirements)
+			assert.NoError(t, err)
+
+			if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+				t.Errorf("Expected requirements markdown to match (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteResources(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	writer := newTestWriter(t, buf)
+
+	err := writer.writeResources([]entities.Resource{
+		{
+			Kind:  entities.ResourceKind,
+			Type:  "aws_s3_bucket",
+			Name:  "this",
+			Count: true,
+			URL:   "https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/s3_bucket",
+		},
+		{
+			Kind: entities.DataSourceKind,
+			Type: "acme_thing",
+			Name: "this",
+		},
+		{
+			Kind:    entities.ModuleKind,
+			Name:    "vpc",
+			Source:  "terraform-aws-modules/vpc/aws",
+			ForEach: true,
+			URL:     "https://registry.terraform.io/modules/terraform-aws-modules/vpc/aws/latest",
+		},
+	})
+	assert.NoError(t, err)
+
+	want := "- Resource [`aws_s3_bucket.this`](https://registry.terraform.io/providers/hashicorp/aws/latest/docs/resources/s3_bucket) *(count)*\n" +
+		"- Data source `data.acme_thing.this`\n" +
+		"- Module [`module.vpc`](https://registry.terraform.io/modules/terraform-aws-modules/vpc/aws/latest) (`terraform-aws-modules/vpc/aws`) *(for_each)*\n\n"
+
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("Expected resources markdown to match (-want +got):\n%s", diff)
+	}
+}
+
 // TODO: rewrite all? :D
 
 type mdSection struct {
@@ -7,17 +7,18 @@ import (
 )
 
 var TemplatesFuncMap = template.FuncMap{
-	"urlfragment": urlfragment,
+	"urlfragment": URLFragment,
 	"indent":      indent,
 	"repeat":      repeat,
 	"multiply":    func(x, y int) int { return x * y },
 	"getIndent":   GetIndent,
 	"newline":     newLine,
+	"oneline":     oneLine,
 }
 
 var urlfragmentRegex *regexp.Regexp
 
-func urlfragment(str string) string {
+func URLFragment(str string) string {
 	val := urlfragmentRegex.ReplaceAllString(str, "")
 
 	return strings.ReplaceAll(strings.ToLower(val), " ", "-")
@@ -27,6 +28,11 @@ func newLine() string {
 	return "\n\n"
 }
 
+// oneLine joins the lines of multiline expressions so they can be written as code spans
+func oneLine(str string) string {
+	return strings.Join(strings.Fields(str), " ")
+}
+
 func GetIndent(level int) int {
 	return level*2 + 2
 }
@@ -10,7 +10,7 @@ func TestURLFragment(t *testing.T) {
 	input := "Backwards compatibility in `0.0.z` and `0.y.z` version"
 	want := "backwards-compatibility-in-00z-and-0yz-version"
 
-	assert.EqualStrings(t, want, urlfragment(input))
+	assert.EqualStrings(t, want, URLFragment(input))
 }
 
 func TestNewLine(t *testing.T) {
@@ -55,6 +55,11 @@ stuff`,
 	}
 }
 
+func TestOneLine(t *testing.T) {
+	assert.EqualStrings(t, `contains([ "a", "b" ], var.name)`, oneLine("contains([\n  \"a\",\n  \"b\"\n], var.name)"))
+	assert.EqualStrings(t, "length(var.name) > 3", oneLine("length(var.name) > 3"))
+}
+
 func TestRepeat(t *testing.T) {
 	assert.EqualStrings(t, "$$$$$", repeat("$", 5))
 	assert.EqualStrings(t, "##", repeat("#", 2))
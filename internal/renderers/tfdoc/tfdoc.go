@@ -0,0 +1,304 @@
+package tfdoc
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+const heredocMarker = "END"
+
+// Render writes the definition as a formatted .tfdoc.hcl document
+func Render(writer io.Writer, definition entities.Doc) error {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+
+	body.AppendBlock(HeaderBlock(definition.Header))
+
+	for _, section := range definition.Sections {
+		body.AppendNewline()
+		body.AppendBlock(SectionBlock(section))
+	}
+
+	if len(definition.References) > 0 {
+		body.AppendNewline()
+		body.AppendBlock(ReferencesBlock(definition.References))
+	}
+
+	_, err := writer.Write(hclwrite.Format(f.Bytes()))
+
+	return err
+}
+
+// HeaderBlock returns the `header` block for the given header
+func HeaderBlock(header entities.Header) *hclwrite.Block {
+	blk := hclwrite.NewBlock("header", nil)
+	body := blk.Body()
+
+	body.SetAttributeValue("image", cty.StringVal(header.Image))
+	body.SetAttributeValue("url", cty.StringVal(header.URL))
+
+	for _, badge := range header.Badges {
+		body.AppendNewline()
+
+		badgeBlk := body.AppendNewBlock("badge", []string{badge.Name})
+		badgeBlk.Body().SetAttributeValue("image", cty.StringVal(badge.Image))
+		badgeBlk.Body().SetAttributeValue("url", cty.StringVal(badge.URL))
+		badgeBlk.Body().SetAttributeValue("text", cty.StringVal(badge.Text))
+	}
+
+	return blk
+}
+
+// SectionBlock returns the `section` block for the given section including its
+// requirements, variables, outputs and subsections
+func SectionBlock(section entities.Section) *hclwrite.Block {
+	blk := hclwrite.NewBlock("section", nil)
+	body := blk.Body()
+
+	body.SetAttributeValue("title", cty.StringVal(section.Title))
+
+	if section.Content != "" {
+		body.SetAttributeRaw("content", tokensForString(section.Content))
+	}
+
+	if section.TOC {
+		body.SetAttributeValue("toc", cty.True)
+	}
+
+	// generated content is generated again when the rendered document is parsed
+	if section.Generated != "" {
+		body.SetAttributeValue("generated", cty.StringVal(section.Generated))
+	}
+
+	if section.Requirements != nil {
+		body.AppendNewline()
+		body.AppendBlock(RequirementsBlock(*section.Requirements))
+	}
+
+	for _, variable := range section.Variables {
+		body.AppendNewline()
+		body.AppendBlock(VariableBlock(variable))
+	}
+
+	for _, output := range section.Outputs {
+		body.AppendNewline()
+		body.AppendBlock(OutputBlock(output))
+	}
+
+	for _, subSection := range section.SubSections {
+		body.AppendNewline()
+		body.AppendBlock(SectionBlock(subSection))
+	}
+
+	return blk
+}
+
+// VariableBlock returns the `variable` block for the given variable including its attributes
+func VariableBlock(variable entities.Variable) *hclwrite.Block {
+	blk := hclwrite.NewBlock("variable", []string{variable.Name})
+	body := blk.Body()
+
+	body.SetAttributeRaw("type", TypeTokens(variable.Type))
+
+	if variable.Required {
+		body.SetAttributeValue("required", cty.True)
+	}
+
+	if len(variable.Default) > 0 {
+		body.SetAttributeRaw("default", tokensForJSON(variable.Default))
+	}
+
+	if variable.ForcesRecreation {
+		body.SetAttributeValue("forces_recreation", cty.True)
+	}
+
+	if variable.Sensitive {
+		body.SetAttributeValue("sensitive", cty.True)
+	}
+
+	if !variable.Nullable {
+		body.SetAttributeValue("nullable", cty.False)
+	}
+
+	if variable.Ephemeral {
+		body.SetAttributeValue("ephemeral", cty.True)
+	}
+
+	body.SetAttributeRaw("description", tokensForString(variable.Description))
+
+	if variable.ReadmeExample != "" {
+		body.SetAttributeRaw("readme_example", tokensForString(variable.ReadmeExample))
+	}
+
+	for _, attribute := range variable.Attributes {
+		body.AppendNewline()
+		body.AppendBlock(AttributeBlock(attribute))
+	}
+
+	for _, constraint := range variable.Constraints {
+		body.AppendNewline()
+		body.AppendBlock(ValidationBlock(constraint))
+	}
+
+	return blk
+}
+
+// ValidationBlock returns the `validation` block for the given constraint
+func ValidationBlock(constraint entities.Constraint) *hclwrite.Block {
+	blk := hclwrite.NewBlock("validation", nil)
+	body := blk.Body()
+
+	body.SetAttributeRaw("condition", hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(constraint.Condition)},
+	})
+
+	if constraint.ErrorMessage != "" {
+		body.SetAttributeRaw("error_message", tokensForString(constraint.ErrorMessage))
+	}
+
+	return blk
+}
+
+// AttributeBlock returns the `attribute` block for the given attribute including its nested attributes
+func AttributeBlock(attribute entities.Attribute) *hclwrite.Block {
+	blk := hclwrite.NewBlock("attribute", []string{attribute.Name})
+	body := blk.Body()
+
+	body.SetAttributeRaw("type", TypeTokens(attribute.Type))
+
+	if attribute.Required {
+		body.SetAttributeValue("required", cty.True)
+	}
+
+	if len(attribute.Default) > 0 {
+		body.SetAttributeRaw("default", tokensForJSON(attribute.Default))
+	}
+
+	if attribute.ForcesRecreation {
+		body.SetAttributeValue("forces_recreation", cty.True)
+	}
+
+	body.SetAttributeRaw("description", tokensForString(attribute.Description))
+
+	if attribute.ReadmeExample != "" {
+		body.SetAttributeRaw("readme_example", tokensForString(attribute.ReadmeExample))
+	}
+
+	for _, nested := range attribute.Attributes {
+		body.AppendNewline()
+		body.AppendBlock(AttributeBlock(nested))
+	}
+
+	return blk
+}
+
+// OutputBlock returns the `output` block for the given output
+func OutputBlock(output entities.Output) *hclwrite.Block {
+	blk := hclwrite.NewBlock("output", []string{output.Name})
+	body := blk.Body()
+
+	body.SetAttributeRaw("type", TypeTokens(output.Type))
+
+	if output.Sensitive {
+		body.SetAttributeValue("sensitive", cty.True)
+	}
+
+	body.SetAttributeRaw("description", tokensForString(output.Description))
+
+	return blk
+}
+
+// ReferencesBlock returns the `references` block for the given references
+func ReferencesBlock(references []entities.Reference) *hclwrite.Block {
+	blk := hclwrite.NewBlock("references", nil)
+	body := blk.Body()
+
+	for i, ref := range references {
+		if i > 0 {
+			body.AppendNewline()
+		}
+
+		refBlk := body.AppendNewBlock("ref", []string{ref.Name})
+		refBlk.Body().SetAttributeValue("value", cty.StringVal(ref.Value))
+	}
+
+	return blk
+}
+
+// RequirementsBlock returns the `requirements` block for the given requirements. Requirements
+// filled from the .tf files are rendered as an empty block, so they keep being filled.
+func RequirementsBlock(requirements entities.Requirements) *hclwrite.Block {
+	blk := hclwrite.NewBlock("requirements", nil)
+	body := blk.Body()
+
+	if requirements.FromCode {
+		return blk
+	}
+
+	if requirements.TerraformVersion != "" {
+		body.SetAttributeValue("terraform", cty.StringVal(requirements.TerraformVersion))
+	}
+
+	for i, provider := range requirements.Providers {
+		if i > 0 || requirements.TerraformVersion != "" {
+			body.AppendNewline()
+		}
+
+		providerBody := body.AppendNewBlock("provider", []string{provider.Name}).Body()
+
+		if provider.Source != "" {
+			providerBody.SetAttributeValue("source", cty.StringVal(provider.Source))
+		}
+
+		if provider.Version != "" {
+			providerBody.SetAttributeValue("version", cty.StringVal(provider.Version))
+		}
+	}
+
+	return blk
+}
+
+// TypeTokens returns the tokens for a type definition as it is written in `type` attributes
+func TypeTokens(typeDef entities.Type) hclwrite.Tokens {
+	return hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(typeDef.DocString())},
+	}
+}
+
+// tokensForJSON converts a JSON value back to its HCL representation. Values that are
+// not valid JSON (e.g. references to variables) are written as they are.
+func tokensForJSON(src json.RawMessage) hclwrite.Tokens {
+	ty, err := ctyjson.ImpliedType(src)
+	if err == nil {
+		val, err := ctyjson.Unmarshal(src, ty)
+		if err == nil {
+			return hclwrite.TokensForValue(val)
+		}
+	}
+
+	return hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: src},
+	}
+}
+
+// tokensForString returns a quoted string for single line values and a heredoc for multiline values
+func tokensForString(str string) hclwrite.Tokens {
+	if !strings.Contains(str, "\n") {
+		return hclwrite.TokensForValue(cty.StringVal(str))
+	}
+
+	escaper := strings.NewReplacer("${", "$${", "%{", "%%{")
+
+	return hclwrite.Tokens{
+		{Type: hclsyntax.TokenOHeredoc, Bytes: []byte("<<" + heredocMarker + "\n")},
+		{Type: hclsyntax.TokenStringLit, Bytes: []byte(escaper.Replace(strings.TrimSuffix(str, "\n")) + "\n")},
+		{Type: hclsyntax.TokenCHeredoc, Bytes: []byte(heredocMarker)},
+	}
+}
@@ -0,0 +1,101 @@
+package tfdoc_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/internal/parsers/docparser"
+	"github.com/mineiros-io/terradoc/internal/renderers/markdown"
+	"github.com/mineiros-io/terradoc/internal/renderers/tfdoc"
+	"github.com/mineiros-io/terradoc/test"
+)
+
+func TestRenderRoundTrip(t *testing.T) {
+	doc, err := docparser.Parse(test.OpenFixture(t, "golden-input.tfdoc.hcl"), "golden-input.tfdoc.hcl")
+	assert.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	err = tfdoc.Render(buf, doc)
+	assert.NoError(t, err)
+
+	renderedDoc, err := docparser.Parse(buf, "rendered.tfdoc.hcl")
+	assert.NoError(t, err)
+
+	// the rendered document must produce the same markdown as the original one
+	md := new(bytes.Buffer)
+	err = markdown.Render(md, renderedDoc)
+	assert.NoError(t, err)
+
+	want := test.ReadFixture(t, "golden-readme.md")
+
+	if diff := cmp.Diff(string(want), md.String()); diff != "" {
+		t.Errorf("Result is not expected (-want +got):\n%s", diff)
+	}
+}
+
+func TestRenderRequirements(t *testing.T) {
+	const src = `section {
+  title = "Module"
+
+  requirements {
+    terraform = ">= 1.0"
+
+    provider "aws" {
+      source  = "hashicorp/aws"
+      version = ">= 4.0"
+    }
+
+    provider "random" {}
+  }
+}
+`
+
+	doc, err := docparser.Parse(strings.NewReader(src), "requirements.tfdoc.hcl")
+	assert.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	err = tfdoc.Render(buf, doc)
+	assert.NoError(t, err)
+
+	renderedDoc, err := docparser.Parse(buf, "rendered.tfdoc.hcl")
+	assert.NoError(t, err)
+
+	want := doc.Sections[0].Requirements
+	got := renderedDoc.Sections[0].Requirements
+
+	if got == nil {
+		t.Fatal("rendered section has no requirements")
+	}
+
+	assert.EqualStrings(t, want.TerraformVersion, got.TerraformVersion)
+	assert.EqualInts(t, len(want.Providers), len(got.Providers))
+
+	for i, provider := range want.Providers {
+		assert.EqualStrings(t, provider.Name, got.Providers[i].Name)
+		assert.EqualStrings(t, provider.Source, got.Providers[i].Source)
+		assert.EqualStrings(t, provider.Version, got.Providers[i].Version)
+	}
+}
+
+func TestRenderGenerated(t *testing.T) {
+	const src = `section {
+  title     = "Resources"
+  generated = "resources"
+}
+`
+
+	doc, err := docparser.Parse(strings.NewReader(src), "generated.tfdoc.hcl")
+	assert.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	err = tfdoc.Render(buf, doc)
+	assert.NoError(t, err)
+
+	renderedDoc, err := docparser.Parse(buf, "rendered.tfdoc.hcl")
+	assert.NoError(t, err)
+
+	assert.EqualStrings(t, "resources", renderedDoc.Sections[0].Generated)
+}
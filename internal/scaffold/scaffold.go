@@ -0,0 +1,142 @@
+package scaffold
+
+import (
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/types"
+)
+
+const (
+	descriptionSectionTitle = "Module Description"
+	gettingStartedTitle     = "Getting Started"
+	argumentsSectionTitle   = "Module Argument Reference"
+	requiredArgumentsTitle  = "Required Arguments"
+	optionalArgumentsTitle  = "Optional Arguments"
+	outputsSectionTitle     = "Module Outputs"
+)
+
+// Doc creates a starter document for a module with the given title from the
+// variables and outputs defined in its .tf files
+func Doc(title string, definitions entities.ValidationContents) entities.Doc {
+	root := entities.Section{
+		Title: title,
+		TOC:   true,
+		SubSections: []entities.Section{
+			{Title: descriptionSectionTitle},
+			{Title: gettingStartedTitle},
+		},
+	}
+
+	if len(definitions.Variables) > 0 {
+		root.SubSections = append(root.SubSections, argumentsSection(definitions.Variables))
+	}
+
+	if len(definitions.Outputs) > 0 {
+		root.SubSections = append(root.SubSections, outputsSection(definitions.Outputs))
+	}
+
+	return entities.Doc{Sections: []entities.Section{root}}
+}
+
+func argumentsSection(variables entities.VariableCollection) entities.Section {
+	section := entities.Section{Title: argumentsSectionTitle}
+
+	var required, optional []entities.Variable
+
+	for _, variable := range variables {
+		v := Variable(variable)
+
+		if v.Required {
+			required = append(required, v)
+		} else {
+			optional = append(optional, v)
+		}
+	}
+
+	if len(required) > 0 {
+		section.SubSections = append(section.SubSections, entities.Section{Title: requiredArgumentsTitle, Variables: required})
+	}
+
+	if len(optional) > 0 {
+		section.SubSections = append(section.SubSections, entities.Section{Title: optionalArgumentsTitle, Variables: optional})
+	}
+
+	return section
+}
+
+func outputsSection(outputs entities.OutputCollection) entities.Section {
+	section := entities.Section{Title: outputsSectionTitle}
+
+	for _, output := range outputs {
+		section.Outputs = append(section.Outputs, Output(output))
+	}
+
+	return section
+}
+
+// Variable returns the documentation stub for a variable defined in a .tf file.
+// Variables without a default value are required and object types without a
+// label are labeled after the variable.
+func Variable(defined entities.Variable) entities.Variable {
+	variable := entities.Variable{
+		Name:        defined.Name,
+		Type:        labelType(defined.Type, defined.Name),
+		Description: defined.Description,
+		Default:     defined.Default,
+		Required:    len(defined.Default) == 0,
+		Sensitive:   defined.Sensitive,
+		Nullable:    defined.Nullable,
+		Ephemeral:   defined.Ephemeral,
+		Constraints: defined.Constraints,
+	}
+
+	for _, attr := range defined.Attributes {
+		variable.Attributes = append(variable.Attributes, attribute(attr))
+	}
+
+	return variable
+}
+
+func attribute(defined entities.Attribute) entities.Attribute {
+	attr := defined
+	attr.Type = labelType(defined.Type, defined.Name)
+	attr.Attributes = nil
+
+	for _, nested := range defined.Attributes {
+		attr.Attributes = append(attr.Attributes, attribute(nested))
+	}
+
+	return attr
+}
+
+// Output returns the documentation stub for an output defined in a .tf file
+func Output(defined entities.Output) entities.Output {
+	output := entities.Output{
+		Name:        defined.Name,
+		Type:        defined.Type,
+		Description: defined.Description,
+		Sensitive:   defined.Sensitive,
+	}
+
+	if output.Type.TFType == types.TerraformEmptyType {
+		output.Type = entities.Type{TFType: types.TerraformAny}
+	}
+
+	return output
+}
+
+// labelType sets the label of unlabeled object types since object types are documented with a
+// label (e.g. `object(name)` or `list(name)`) and their attributes as attribute blocks
+func labelType(typeDef entities.Type, label string) entities.Type {
+	switch {
+	case typeDef.TFType == types.TerraformEmptyType:
+		return entities.Type{TFType: types.TerraformAny}
+	case typeDef.TFType == types.TerraformObject && typeDef.Label == "":
+		typeDef.Label = label
+		typeDef.Fields = nil
+	case typeDef.HasNestedType():
+		nested := labelType(*typeDef.Nested, label)
+		typeDef.Nested = &nested
+	}
+
+	return typeDef
+}
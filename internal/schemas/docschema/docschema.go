@@ -17,10 +17,23 @@ func RootSchema() *hcl.BodySchema {
 				Type:       "references",
 				LabelNames: []string{},
 			},
+			{
+				Type:       "include",
+				LabelNames: []string{"path"},
+			},
+			{
+				Type:       "locals",
+				LabelNames: []string{},
+			},
 		},
 	}
 }
 
+// IncludeSchema is the schema of `include` blocks, whose only content is the path in their label
+func IncludeSchema() *hcl.BodySchema {
+	return &hcl.BodySchema{}
+}
+
 func HeaderSchema() *hcl.BodySchema {
 	return &hcl.BodySchema{
 		Attributes: []hcl.AttributeSchema{
@@ -98,6 +111,10 @@ func SectionSchema() *hcl.BodySchema {
 				Name:     "toc",
 				Required: false,
 			},
+			{
+				Name:     "generated",
+				Required: false,
+			},
 		},
 		Blocks: []hcl.BlockHeaderSchema{
 			{
@@ -112,6 +129,42 @@ func SectionSchema() *hcl.BodySchema {
 				Type:       "output",
 				LabelNames: []string{"name"},
 			},
+			{
+				Type:       "requirements",
+				LabelNames: []string{},
+			},
+		},
+	}
+}
+
+func RequirementsSchema() *hcl.BodySchema {
+	return &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{
+				Name:     "terraform",
+				Required: false,
+			},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{
+				Type:       "provider",
+				LabelNames: []string{"name"},
+			},
+		},
+	}
+}
+
+func ProviderRequirementSchema() *hcl.BodySchema {
+	return &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{
+				Name:     "source",
+				Required: false,
+			},
+			{
+				Name:     "version",
+				Required: false,
+			},
 		},
 	}
 }
@@ -139,6 +192,18 @@ func VariableSchema() *hcl.BodySchema {
 				Name:     "forces_recreation",
 				Required: false,
 			},
+			{
+				Name:     "sensitive",
+				Required: false,
+			},
+			{
+				Name:     "nullable",
+				Required: false,
+			},
+			{
+				Name:     "ephemeral",
+				Required: false,
+			},
 			{
 				Name:     "readme_example",
 				Required: false,
@@ -153,6 +218,25 @@ func VariableSchema() *hcl.BodySchema {
 				Type:       "attribute",
 				LabelNames: []string{"name"},
 			},
+			{
+				Type:       "validation",
+				LabelNames: []string{},
+			},
+		},
+	}
+}
+
+func ValidationSchema() *hcl.BodySchema {
+	return &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{
+				Name:     "condition",
+				Required: true,
+			},
+			{
+				Name:     "error_message",
+				Required: false,
+			},
 		},
 	}
 }
@@ -168,6 +252,10 @@ func OutputSchema() *hcl.BodySchema {
 				Name:     "description",
 				Required: false,
 			},
+			{
+				Name:     "sensitive",
+				Required: false,
+			},
 		},
 	}
 }
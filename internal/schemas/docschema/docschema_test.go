@@ -27,6 +27,7 @@ func TestSectionSchema(t *testing.T) {
 	// schema attributes
 	assertHasAttribute(t, s, "title", false)
 	assertHasAttribute(t, s, "content", false)
+	assertHasAttribute(t, s, "generated", false)
 
 	// schema blocks
 	nestedSectionBlocks := getBlocks(s.Blocks, "section")
@@ -41,6 +42,29 @@ func TestSectionSchema(t *testing.T) {
 	}
 
 	assertBlockHasLabel(t, variableBlocks[0], "name")
+
+	requirementsBlocks := getBlocks(s.Blocks, "requirements")
+	if len(requirementsBlocks) != 1 {
+		t.Errorf("Expected 1 requirements block. Found %d instead", len(requirementsBlocks))
+	}
+}
+
+func TestRequirementsSchema(t *testing.T) {
+	s := docschema.RequirementsSchema()
+
+	assertHasAttribute(t, s, "terraform", false)
+
+	providerBlocks := getBlocks(s.Blocks, "provider")
+	if len(providerBlocks) != 1 {
+		t.Errorf("Expected 1 provider block. Found %d instead", len(providerBlocks))
+	}
+
+	assertBlockHasLabel(t, providerBlocks[0], "name")
+
+	p := docschema.ProviderRequirementSchema()
+
+	assertHasAttribute(t, p, "source", false)
+	assertHasAttribute(t, p, "version", false)
 }
 
 func TestVariableSchema(t *testing.T) {
@@ -54,6 +78,9 @@ func TestVariableSchema(t *testing.T) {
 	assertHasAttribute(t, s, "required", false)
 	assertHasAttribute(t, s, "forces_recreation", false)
 	assertHasAttribute(t, s, "readme_example", false)
+	assertHasAttribute(t, s, "sensitive", false)
+	assertHasAttribute(t, s, "nullable", false)
+	assertHasAttribute(t, s, "ephemeral", false)
 
 	// schema blocks
 	attrBlocks := getBlocks(s.Blocks, "attribute")
@@ -61,6 +88,19 @@ func TestVariableSchema(t *testing.T) {
 		t.Errorf("Expected 1 attribute block. Found %d instead", len(attrBlocks))
 	}
 	assertBlockHasLabel(t, attrBlocks[0], "name")
+
+	validationBlocks := getBlocks(s.Blocks, "validation")
+	if len(validationBlocks) != 1 {
+		t.Errorf("Expected 1 validation block. Found %d instead", len(validationBlocks))
+	}
+}
+
+func TestValidationSchema(t *testing.T) {
+	s := docschema.ValidationSchema()
+
+	// schema attributes
+	assertHasAttribute(t, s, "condition", true)
+	assertHasAttribute(t, s, "error_message", false)
 }
 
 func TestAttributeSchema(t *testing.T) {
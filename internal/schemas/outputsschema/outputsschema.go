@@ -15,6 +15,23 @@ func RootSchema() *hcl.BodySchema {
 
 func OutputSchema() *hcl.BodySchema {
 	return &hcl.BodySchema{
-		Attributes: []hcl.AttributeSchema{},
+		Attributes: []hcl.AttributeSchema{
+			{
+				Name:     "description",
+				Required: false,
+			},
+			{
+				Name:     "value",
+				Required: false,
+			},
+			{
+				Name:     "sensitive",
+				Required: false,
+			},
+			{
+				Name:     "depends_on",
+				Required: false,
+			},
+		},
 	}
 }
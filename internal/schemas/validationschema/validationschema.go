@@ -13,6 +13,83 @@ func RootSchema() *hcl.BodySchema {
 				Type:       "output",
 				LabelNames: []string{"name"},
 			},
+			{
+				Type:       "terraform",
+				LabelNames: []string{},
+			},
+			{
+				Type:       "resource",
+				LabelNames: []string{"type", "name"},
+			},
+			{
+				Type:       "data",
+				LabelNames: []string{"type", "name"},
+			},
+			{
+				Type:       "module",
+				LabelNames: []string{"name"},
+			},
+		},
+	}
+}
+
+// TerraformSchema is the part of the schema of `terraform` blocks declaring the required versions.
+// Other content, e.g. `backend` blocks, is ignored.
+func TerraformSchema() *hcl.BodySchema {
+	return &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{
+				Name:     "required_version",
+				Required: false,
+			},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{
+				Type:       "required_providers",
+				LabelNames: []string{},
+			},
+		},
+	}
+}
+
+// ResourceSchema is the part of the schema of `resource` and `data` blocks with the meta-arguments
+// inventoried by terradoc
+func ResourceSchema() *hcl.BodySchema {
+	return &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{
+				Name:     "count",
+				Required: false,
+			},
+			{
+				Name:     "for_each",
+				Required: false,
+			},
+			{
+				Name:     "provider",
+				Required: false,
+			},
+		},
+	}
+}
+
+// ModuleSchema is the part of the schema of `module` blocks with the arguments inventoried by
+// terradoc
+func ModuleSchema() *hcl.BodySchema {
+	return &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{
+				Name:     "source",
+				Required: false,
+			},
+			{
+				Name:     "count",
+				Required: false,
+			},
+			{
+				Name:     "for_each",
+				Required: false,
+			},
 		},
 	}
 }
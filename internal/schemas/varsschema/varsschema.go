@@ -20,6 +20,26 @@ func VariableSchema() *hcl.BodySchema {
 				Name:     "type",
 				Required: true,
 			},
+			{
+				Name:     "description",
+				Required: false,
+			},
+			{
+				Name:     "default",
+				Required: false,
+			},
+			{
+				Name:     "sensitive",
+				Required: false,
+			},
+			{
+				Name:     "nullable",
+				Required: false,
+			},
+			{
+				Name:     "ephemeral",
+				Required: false,
+			},
 		},
 		Blocks: []hcl.BlockHeaderSchema{
 			{
@@ -29,3 +49,18 @@ func VariableSchema() *hcl.BodySchema {
 		},
 	}
 }
+
+func ValidationSchema() *hcl.BodySchema {
+	return &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{
+				Name:     "condition",
+				Required: true,
+			},
+			{
+				Name:     "error_message",
+				Required: true,
+			},
+		},
+	}
+}
@@ -0,0 +1,222 @@
+package textdiff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const contextLines = 3
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	line string
+}
+
+// Unified returns the unified diff between a and b or an empty string if they are equal.
+// The names are used for the `---` and `+++` file headers.
+func Unified(aName, bName string, a, b []byte) string {
+	if bytes.Equal(a, b) {
+		return ""
+	}
+
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var buf strings.Builder
+
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", aName, bName)
+
+	for _, h := range hunks(ops) {
+		h.writeTo(&buf)
+	}
+
+	return buf.String()
+}
+
+func splitLines(src []byte) []string {
+	if len(src) == 0 {
+		return nil
+	}
+
+	lines := strings.SplitAfter(string(src), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines computes the line operations to transform a into b using the longest common subsequence
+// of the lines that remain after removing the common prefix and suffix
+func diffLines(a, b []string) []op {
+	var prefix, suffix []op
+
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		prefix = append(prefix, op{kind: opEqual, line: a[0]})
+		a, b = a[1:], b[1:]
+	}
+
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		suffix = append([]op{{kind: opEqual, line: a[len(a)-1]}}, suffix...)
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := prefix
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, op{kind: opEqual, line: a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{kind: opDelete, line: a[i]})
+			i++
+		default:
+			ops = append(ops, op{kind: opInsert, line: b[j]})
+			j++
+		}
+	}
+
+	return append(ops, suffix...)
+}
+
+type hunk struct {
+	aStart, aLines int
+	bStart, bLines int
+	ops            []op
+}
+
+// hunks groups the changed lines together with their surrounding context lines
+func hunks(ops []op) []hunk {
+	var result []hunk
+
+	// line numbers in a and b at the start of each op
+	aLine := make([]int, len(ops)+1)
+	bLine := make([]int, len(ops)+1)
+
+	for i, o := range ops {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+
+		if o.kind != opInsert {
+			aLine[i+1]++
+		}
+
+		if o.kind != opDelete {
+			bLine[i+1]++
+		}
+	}
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == opEqual {
+			i++
+			continue
+		}
+
+		start := i - contextLines
+		if start < 0 {
+			start = 0
+		}
+
+		// extend the hunk while changes are separated by less than twice the context lines
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != opEqual {
+				end++
+				continue
+			}
+
+			next := end
+			for next < len(ops) && ops[next].kind == opEqual {
+				next++
+			}
+
+			if next == len(ops) || next-end > 2*contextLines {
+				end += contextLines
+				if end > len(ops) {
+					end = len(ops)
+				}
+
+				break
+			}
+
+			end = next
+		}
+
+		h := hunk{
+			aStart: aLine[start] + 1,
+			aLines: aLine[end] - aLine[start],
+			bStart: bLine[start] + 1,
+			bLines: bLine[end] - bLine[start],
+			ops:    ops[start:end],
+		}
+
+		result = append(result, h)
+
+		i = end
+	}
+
+	return result
+}
+
+func (h hunk) writeTo(buf *strings.Builder) {
+	fmt.Fprintf(buf, "@@ -%s +%s @@\n", hunkRange(h.aStart, h.aLines), hunkRange(h.bStart, h.bLines))
+
+	for _, o := range h.ops {
+		switch o.kind {
+		case opEqual:
+			buf.WriteString(" ")
+		case opDelete:
+			buf.WriteString("-")
+		case opInsert:
+			buf.WriteString("+")
+		}
+
+		buf.WriteString(o.line)
+
+		if !strings.HasSuffix(o.line, "\n") {
+			buf.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(start, lines int) string {
+	if lines == 0 {
+		// empty ranges refer to the line before the change
+		return fmt.Sprintf("%d,0", start-1)
+	}
+
+	if lines == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, lines)
+}
@@ -0,0 +1,89 @@
+package textdiff_test
+
+import (
+	"testing"
+
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/internal/textdiff"
+)
+
+func TestUnified(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		a    string
+		b    string
+		want string
+	}{
+		{
+			desc: "when contents are equal",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+			want: "",
+		},
+		{
+			desc: "when a line changed",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:    "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: `--- a
++++ b
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+`,
+		},
+		{
+			desc: "when changes are far apart",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			b:    "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n",
+			want: `--- a
++++ b
+@@ -1,4 +1,4 @@
+-1
++one
+ 2
+ 3
+ 4
+@@ -9,4 +9,3 @@
+ 9
+ 10
+ 11
+-12
+`,
+		},
+		{
+			desc: "when a is empty",
+			a:    "",
+			b:    "new\n",
+			want: `--- a
++++ b
+@@ -0,0 +1 @@
++new
+`,
+		},
+		{
+			desc: "when there is no newline at the end",
+			a:    "a\nb",
+			b:    "a\nb\n",
+			want: `--- a
++++ b
+@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++b
+`,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := textdiff.Unified("a", "b", []byte(tt.a), []byte(tt.b))
+
+			assert.EqualStrings(t, tt.want, got)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package outputsvalidator
 
 import (
 	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/types"
 	"github.com/mineiros-io/terradoc/internal/validators"
 )
 
@@ -15,22 +16,72 @@ type outputValidation struct {
 }
 
 func Validate(doc entities.Doc, outputsFile entities.ValidationContents) validators.Summary {
+	return ValidateWithOptions(doc, outputsFile, validators.Options{})
+}
+
+// ValidateWithOptions validates the outputs with the optional checks enabled in opts.
+// Outputs have no defaults, so only descriptions are checked.
+func ValidateWithOptions(doc entities.Doc, outputsFile entities.ValidationContents, opts validators.Options) validators.Summary {
 	summary := validators.Summary{Type: CheckType}
 
 	validationResult := validateOutputs(doc.AllOutputs(), outputsFile.Outputs)
 
 	for outputName, check := range validationResult {
+		summary.Checked = append(summary.Checked, outputName)
+
 		switch {
 		case check.defined.Name == "":
-			summary.MissingDefinition = append(summary.MissingDefinition, outputName)
+			summary.AddMissingDefinition(outputName, check.documented.DefRange)
+
+			continue
 		case check.documented.Name == "":
-			summary.MissingDocumentation = append(summary.MissingDocumentation, outputName)
+			summary.AddMissingDocumentation(outputName, check.defined.DefRange)
+
+			continue
+		case !typesMatch(check.defined.Type, check.documented.Type):
+			summary.AddTypeMismatch(
+				validators.TypeMismatchResult{
+					Name:           outputName,
+					DefinedType:    check.defined.Type.AsString(),
+					DocumentedType: check.documented.Type.AsString(),
+				},
+				check.documented.DefRange,
+			)
+		}
+
+		// sensitive values must be documented so readers know which outputs carry secrets
+		if opts.Sensitive && check.defined.Sensitive && !check.documented.Sensitive {
+			summary.AddSensitiveMismatch(outputName, check.documented.DefRange)
+		}
+
+		if opts.Descriptions && check.defined.Description != "" &&
+			!validators.DescriptionsMatch(check.defined.Description, check.documented.Description) {
+			summary.AddDescriptionMismatch(
+				validators.ValueMismatchResult{
+					Name:            outputName,
+					DefinedValue:    check.defined.Description,
+					DocumentedValue: check.documented.Description,
+				},
+				check.documented.DefRange,
+			)
 		}
 	}
 
+	summary.Sort()
+
 	return summary
 }
 
+// typesMatch compares the documented type with the type inferred from the value of the output.
+// Outputs whose type can not be inferred or that are not typed in the documentation match any type.
+func typesMatch(defined, documented entities.Type) bool {
+	if defined.TFType == types.TerraformEmptyType || documented.TFType == types.TerraformEmptyType {
+		return true
+	}
+
+	return validators.TypesMatch(&defined, &documented)
+}
+
 func validateOutputs(docOutputs, outputsFileOutputs []entities.Output) outputValidationChecks {
 	result := outputValidationChecks{}
 
@@ -3,8 +3,10 @@ package outputsvalidator_test
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/types"
+	"github.com/mineiros-io/terradoc/internal/validators"
 	"github.com/mineiros-io/terradoc/internal/validators/outputsvalidator"
 	"github.com/mineiros-io/terradoc/test"
 )
@@ -87,3 +89,74 @@ func definitionFromOutputs(outputs entities.OutputCollection) entities.Doc {
 func outputFileFromOutputs(outputs entities.OutputCollection) entities.ValidationContents {
 	return entities.ValidationContents{Outputs: outputs}
 }
+
+func TestValidateDescriptions(t *testing.T) {
+	def := definitionFromOutputs(entities.OutputCollection{
+		{Name: "id", Description: "The ID of the bucket."},
+		{Name: "arn", Description: "The ARN of the bucket."},
+	})
+	of := outputFileFromOutputs(entities.OutputCollection{
+		{Name: "id", Description: "The bucket ID."},
+		{Name: "arn", Description: "The ARN of the bucket."},
+	})
+
+	got := outputsvalidator.Validate(def, of)
+	if !got.Success() {
+		t.Fatal("wanted descriptions to be ignored unless the check is enabled")
+	}
+
+	got = outputsvalidator.ValidateWithOptions(def, of, validators.Options{Descriptions: true})
+
+	want := []validators.ValueMismatchResult{
+		{Name: "id", DefinedValue: "The bucket ID.", DocumentedValue: "The ID of the bucket."},
+	}
+
+	if diff := cmp.Diff(want, got.ValueMismatches(validators.CodeDescriptionMismatch)); diff != "" {
+		t.Errorf("Description mismatches are not expected (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateTypes(t *testing.T) {
+	bucket := entities.Type{TFType: types.TerraformResource, Label: "aws_s3_bucket"}
+
+	def := definitionFromOutputs(entities.OutputCollection{
+		{Name: "bucket", Type: bucket},
+		{Name: "policy", Type: entities.Type{TFType: types.TerraformResource, Label: "aws_s3_bucket"}},
+		{Name: "id", Type: entities.Type{TFType: types.TerraformString}},
+		{Name: "name", Type: entities.Type{TFType: types.TerraformNumber}},
+	})
+	of := outputFileFromOutputs(entities.OutputCollection{
+		{Name: "bucket", Type: bucket},
+		{Name: "policy", Type: entities.Type{TFType: types.TerraformResource, Label: "aws_s3_bucket_policy"}},
+		{Name: "id"},
+		{Name: "name", Type: entities.Type{TFType: types.TerraformString}},
+	})
+
+	got := outputsvalidator.Validate(def, of)
+
+	test.AssertHasTypeMismatches(t, []validators.TypeMismatchResult{
+		{Name: "name", DefinedType: "string", DocumentedType: "number"},
+		{Name: "policy", DefinedType: "resource(aws_s3_bucket_policy)", DocumentedType: "resource(aws_s3_bucket)"},
+	}, got.TypeMismatch)
+}
+
+func TestValidateSensitive(t *testing.T) {
+	def := definitionFromOutputs(entities.OutputCollection{
+		{Name: "password"},
+		{Name: "token", Sensitive: true},
+		{Name: "id", Sensitive: true},
+	})
+	of := outputFileFromOutputs(entities.OutputCollection{
+		{Name: "password", Sensitive: true},
+		{Name: "token", Sensitive: true},
+		{Name: "id"},
+	})
+
+	got := outputsvalidator.Validate(def, of)
+	if len(got.Names(validators.CodeSensitiveMismatch)) > 0 {
+		t.Errorf("wanted sensitive outputs to be checked only with the option, got %v", got.Names(validators.CodeSensitiveMismatch))
+	}
+
+	got = outputsvalidator.ValidateWithOptions(def, of, validators.Options{Sensitive: true})
+	test.AssertHasStrings(t, []string{"password"}, got.Names(validators.CodeSensitiveMismatch))
+}
@@ -0,0 +1,64 @@
+package refsvalidator
+
+import (
+	"strings"
+
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/validators"
+)
+
+const CheckType = "reference"
+
+// Validate checks the references used in the content and the descriptions of the document against
+// the references defined in its `references` block. Shortcut links, e.g. `[name]`, are only links if
+// the reference is defined, so they are not reported as undefined.
+func Validate(doc entities.Doc) validators.Summary {
+	summary := validators.Summary{Type: CheckType}
+
+	used := map[string]bool{}
+	undefined := map[string]bool{}
+
+	for _, use := range doc.ReferenceUses {
+		ref, ok := findReference(doc.References, use.Name)
+		if ok {
+			used[ref.Name] = true
+			continue
+		}
+
+		if use.Shortcut {
+			continue
+		}
+
+		// undefined references are checked, too, so they are reported as failed test cases
+		if !undefined[use.Name] {
+			undefined[use.Name] = true
+			summary.Checked = append(summary.Checked, use.Name)
+		}
+
+		summary.AddUndefinedReference(use.Name, use.Range)
+	}
+
+	for _, ref := range doc.References {
+		summary.Checked = append(summary.Checked, ref.Name)
+
+		if !used[ref.Name] {
+			summary.AddUnusedReference(ref.Name, ref.DefRange)
+		}
+	}
+
+	summary.Sort()
+
+	return summary
+}
+
+// findReference returns the reference of a name. Names are matched case-insensitively like the
+// labels of markdown links.
+func findReference(refs []entities.Reference, name string) (entities.Reference, bool) {
+	for _, ref := range refs {
+		if strings.EqualFold(ref.Name, name) {
+			return ref, true
+		}
+	}
+
+	return entities.Reference{}, false
+}
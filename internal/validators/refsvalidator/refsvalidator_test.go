@@ -0,0 +1,84 @@
+package refsvalidator_test
+
+import (
+	"testing"
+
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/validators"
+	"github.com/mineiros-io/terradoc/internal/validators/refsvalidator"
+	"github.com/mineiros-io/terradoc/test"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		desc          string
+		refs          []string
+		uses          []entities.ReferenceUse
+		wantUndefined []string
+		wantUnused    []string
+	}{
+		{
+			desc: "when all references are used",
+			refs: []string{"homepage", "terraform"},
+			uses: []entities.ReferenceUse{
+				{Name: "homepage"},
+				{Name: "Terraform", Shortcut: true},
+			},
+		},
+		{
+			desc: "when a reference is not defined",
+			refs: []string{"homepage"},
+			uses: []entities.ReferenceUse{
+				{Name: "homepage"},
+				{Name: "guide"},
+			},
+			wantUndefined: []string{"guide"},
+		},
+		{
+			desc: "when a shortcut link is not defined",
+			refs: []string{"homepage"},
+			uses: []entities.ReferenceUse{
+				{Name: "homepage"},
+				{Name: "optional", Shortcut: true},
+			},
+		},
+		{
+			desc:       "when a reference is not used",
+			refs:       []string{"homepage", "guide"},
+			uses:       []entities.ReferenceUse{{Name: "guide"}},
+			wantUnused: []string{"homepage"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			doc := entities.Doc{ReferenceUses: tt.uses}
+			for _, name := range tt.refs {
+				doc.References = append(doc.References, entities.Reference{Name: name})
+			}
+
+			summary := refsvalidator.Validate(doc)
+
+			assert.EqualInts(t, len(tt.wantUndefined), len(summary.Names(validators.CodeUndefinedReference)))
+			test.AssertHasStrings(t, tt.wantUndefined, summary.Names(validators.CodeUndefinedReference))
+			assert.EqualInts(t, len(tt.wantUnused), len(summary.Names(validators.CodeUnusedReference)))
+			test.AssertHasStrings(t, tt.wantUnused, summary.Names(validators.CodeUnusedReference))
+			test.AssertHasStrings(t, tt.refs, summary.Checked)
+			test.AssertHasStrings(t, tt.wantUndefined, summary.Checked)
+
+			if summary.Success() != (len(tt.wantUndefined) == 0) {
+				t.Errorf("wanted success to be %t, got %t", len(tt.wantUndefined) == 0, summary.Success())
+			}
+		})
+	}
+}
+
+func TestUnusedReferenceIsWarning(t *testing.T) {
+	doc := entities.Doc{References: []entities.Reference{{Name: "homepage"}}}
+
+	diags := refsvalidator.Validate(doc).Diagnostics()
+	assert.EqualInts(t, 1, len(diags))
+	assert.EqualStrings(t, string(diagnostics.Warning), string(diags[0].Severity))
+}
@@ -0,0 +1,88 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/mineiros-io/terradoc/internal/validators"
+)
+
+type jsonReport struct {
+	Success bool         `json:"success"`
+	Results []jsonResult `json:"results"`
+}
+
+type jsonResult struct {
+	Module         string `json:"module,omitempty"`
+	Type           string `json:"type"`
+	Code           string `json:"code"`
+	Name           string `json:"name"`
+	Severity       string `json:"severity"`
+	Message        string `json:"message"`
+	File           string `json:"file,omitempty"`
+	Line           int    `json:"line,omitempty"`
+	Column         int    `json:"column,omitempty"`
+	DefinedType    string `json:"defined_type,omitempty"`
+	DocumentedType string `json:"documented_type,omitempty"`
+	// DefinedValue and DocumentedValue are set for default and description mismatches
+	DefinedValue    string `json:"defined_value,omitempty"`
+	DocumentedValue string `json:"documented_value,omitempty"`
+}
+
+// JSON writes the summaries as an indented JSON document:
+//
+//	{
+//	  "success": false,
+//	  "results": [
+//	    {
+//	      "module": "modules/bucket",
+//	      "type": "variable",
+//	      "code": "type-mismatch",
+//	      "name": "person",
+//	      "severity": "error",
+//	      "message": "Type mismatch for variable: ...",
+//	      "file": "README.tfdoc.hcl",
+//	      "line": 12,
+//	      "column": 3,
+//	      "defined_type": "string",
+//	      "documented_type": "object(person)"
+//	    }
+//	  ]
+//	}
+//
+// Default and description mismatches have `defined_value` and `documented_value` fields instead of
+// the types, where defaults are compact JSON. The module is only set when the summaries of several
+// modules are reported.
+func JSON(w io.Writer, summaries []validators.Summary) error {
+	report := jsonReport{
+		Success: success(summaries),
+		Results: []jsonResult{},
+	}
+
+	for _, s := range summaries {
+		for _, r := range s.Results {
+			diag := r.Diagnostic(s.Type)
+
+			report.Results = append(report.Results, jsonResult{
+				Module:          s.Module,
+				Type:            s.Type,
+				Code:            r.Code,
+				Name:            r.Name,
+				Severity:        string(diag.Severity),
+				Message:         diag.Message(),
+				File:            r.Range.Filename,
+				Line:            r.Range.Start.Line,
+				Column:          r.Range.Start.Column,
+				DefinedType:     r.DefinedType,
+				DocumentedType:  r.DocumentedType,
+				DefinedValue:    r.DefinedValue,
+				DocumentedValue: r.DocumentedValue,
+			})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(report)
+}
@@ -0,0 +1,123 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+	"path"
+	"path/filepath"
+
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+	"github.com/mineiros-io/terradoc/internal/validators"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string         `xml:"classname,attr"`
+	Name      string         `xml:"name,attr"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	// Text is the position of the problem as `file:line:col`
+	Text string `xml:",chardata"`
+}
+
+// JUnit writes the summaries as JUnit XML. Each summary is a test suite and each checked
+// name is a test case which fails with one failure per error reported for it. Names with
+// errors that were not checked, e.g. attribute paths, get test cases of their own. Suites are
+// named after the checked type prefixed by the module, if any, e.g. `modules/bucket/variable`.
+func JUnit(w io.Writer, summaries []validators.Summary) error {
+	suites := junitTestSuites{Name: toolName}
+
+	for _, s := range summaries {
+		suite := junitTestSuite{Name: suiteName(s)}
+
+		failures := map[string][]junitFailure{}
+		// failed are the names with failures in the order of the results
+		var failed []string
+
+		for _, r := range s.Results {
+			diag := r.Diagnostic(s.Type)
+			if diag.Severity != diagnostics.Error {
+				continue
+			}
+
+			if _, ok := failures[r.Name]; !ok {
+				failed = append(failed, r.Name)
+			}
+
+			failures[r.Name] = append(failures[r.Name], junitFailure{
+				Type:    r.Code,
+				Message: diag.Message(),
+				Text:    diag.Pos(),
+			})
+		}
+
+		names := append([]string{}, s.Checked...)
+
+		checked := map[string]bool{}
+		for _, name := range s.Checked {
+			checked[name] = true
+		}
+
+		for _, name := range failed {
+			if !checked[name] {
+				names = append(names, name)
+			}
+		}
+
+		for _, name := range names {
+			tc := junitTestCase{ClassName: suite.Name, Name: name, Failures: failures[name]}
+
+			suite.Tests++
+			if len(tc.Failures) > 0 {
+				suite.Failures++
+			}
+
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+func suiteName(s validators.Summary) string {
+	if s.Module == "" {
+		return s.Type
+	}
+
+	return path.Join(filepath.ToSlash(s.Module), s.Type)
+}
@@ -0,0 +1,42 @@
+// Package report writes validation summaries in machine-readable formats so they can be
+// consumed by CI systems: a terradoc specific JSON document, SARIF 2.1.0 for code scanning
+// tools and JUnit XML for test dashboards.
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mineiros-io/terradoc/internal/validators"
+)
+
+// Formats supported by Write
+const (
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+	FormatJUnit = "junit"
+)
+
+// Write writes the summaries in the given format
+func Write(w io.Writer, format string, summaries []validators.Summary) error {
+	switch format {
+	case FormatJSON:
+		return JSON(w, summaries)
+	case FormatSARIF:
+		return SARIF(w, summaries)
+	case FormatJUnit:
+		return JUnit(w, summaries)
+	}
+
+	return fmt.Errorf("unknown report format %q", format)
+}
+
+func success(summaries []validators.Summary) bool {
+	for _, s := range summaries {
+		if !s.Success() {
+			return false
+		}
+	}
+
+	return true
+}
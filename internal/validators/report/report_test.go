@@ -0,0 +1,222 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/internal/validators"
+	"github.com/mineiros-io/terradoc/internal/validators/report"
+)
+
+func testSummaries() []validators.Summary {
+	vars := validators.Summary{Type: "variable", Checked: []string{"age", "name", "person"}}
+	vars.AddMissingDefinition("name", testRange("README.tfdoc.hcl", 10, 3))
+	vars.AddMissingDocumentation("age", testRange("variables.tf", 1, 1))
+	vars.AddTypeMismatch(validators.TypeMismatchResult{
+		Name:           "person",
+		DefinedType:    "string",
+		DocumentedType: "object(person)",
+	}, testRange("README.tfdoc.hcl", 20, 5))
+	vars.Sort()
+
+	outputs := validators.Summary{Type: "output", Checked: []string{"id"}}
+
+	return []validators.Summary{vars, outputs}
+}
+
+func testRange(filename string, line, column int) hcl.Range {
+	return hcl.Range{
+		Filename: filename,
+		Start:    hcl.Pos{Line: line, Column: column, Byte: line * 10},
+		End:      hcl.Pos{Line: line, Column: column + 4, Byte: line*10 + 4},
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	assert.NoError(t, report.Write(&buf, report.FormatJSON, testSummaries()))
+
+	var got struct {
+		Success bool
+		Results []map[string]interface{}
+	}
+
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+	want := []map[string]interface{}{
+		{
+			"type":     "variable",
+			"code":     validators.CodeMissingDefinition,
+			"name":     "name",
+			"severity": "error",
+			"message":  `Unknown variable documented: "name" is not defined in any .tf files`,
+			"file":     "README.tfdoc.hcl",
+			"line":     float64(10),
+			"column":   float64(3),
+		},
+		{
+			"type":            "variable",
+			"code":            validators.CodeTypeMismatch,
+			"name":            "person",
+			"severity":        "error",
+			"message":         `Type mismatch for variable: "person" is documented as "object(person)" but defined as "string" in .tf files`,
+			"file":            "README.tfdoc.hcl",
+			"line":            float64(20),
+			"column":          float64(5),
+			"defined_type":    "string",
+			"documented_type": "object(person)",
+		},
+		{
+			"type":     "variable",
+			"code":     validators.CodeMissingDocumentation,
+			"name":     "age",
+			"severity": "error",
+			"message":  `Missing variable documentation: "age" is not documented`,
+			"file":     "variables.tf",
+			"line":     float64(1),
+			"column":   float64(1),
+		},
+	}
+
+	if got.Success {
+		t.Error("expected report to be unsuccessful")
+	}
+
+	if diff := cmp.Diff(want, got.Results); diff != "" {
+		t.Errorf("Result is not expected (-want +got):\n%s", diff)
+	}
+}
+
+func TestSARIF(t *testing.T) {
+	var buf bytes.Buffer
+
+	assert.NoError(t, report.Write(&buf, report.FormatSARIF, testSummaries()))
+
+	type location struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI string
+			}
+			Region struct {
+				StartLine   int
+				StartColumn int
+			}
+		}
+	}
+
+	var got struct {
+		Version string
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string
+					Rules []struct {
+						ID string
+					}
+				}
+			}
+			Results []struct {
+				RuleID    string
+				Level     string
+				Locations []location
+			}
+		}
+	}
+
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+	assert.EqualStrings(t, "2.1.0", got.Version)
+	assert.EqualInts(t, 1, len(got.Runs))
+
+	run := got.Runs[0]
+
+	assert.EqualStrings(t, "terradoc", run.Tool.Driver.Name)
+	assert.EqualInts(t, 15, len(run.Tool.Driver.Rules))
+	assert.EqualInts(t, 3, len(run.Results))
+
+	result := run.Results[2]
+
+	assert.EqualStrings(t, validators.CodeMissingDocumentation, result.RuleID)
+	assert.EqualStrings(t, "error", result.Level)
+	assert.EqualInts(t, 1, len(result.Locations))
+	assert.EqualStrings(t, "variables.tf", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.EqualInts(t, 1, result.Locations[0].PhysicalLocation.Region.StartLine)
+	assert.EqualInts(t, 1, result.Locations[0].PhysicalLocation.Region.StartColumn)
+}
+
+func TestJUnit(t *testing.T) {
+	var buf bytes.Buffer
+
+	assert.NoError(t, report.Write(&buf, report.FormatJUnit, testSummaries()))
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="terradoc" tests="4" failures="3">
+  <testsuite name="variable" tests="3" failures="3">
+    <testcase classname="variable" name="age">
+      <failure type="missing-documentation" message="Missing variable documentation: &#34;age&#34; is not documented">variables.tf:1:1</failure>
+    </testcase>
+    <testcase classname="variable" name="name">
+      <failure type="missing-definition" message="Unknown variable documented: &#34;name&#34; is not defined in any .tf files">README.tfdoc.hcl:10:3</failure>
+    </testcase>
+    <testcase classname="variable" name="person">
+      <failure type="type-mismatch" message="Type mismatch for variable: &#34;person&#34; is documented as &#34;object(person)&#34; but defined as &#34;string&#34; in .tf files">README.tfdoc.hcl:20:5</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="output" tests="1" failures="0">
+    <testcase classname="output" name="id"></testcase>
+  </testsuite>
+</testsuites>
+`
+
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("Result is not expected (-want +got):\n%s", diff)
+	}
+}
+
+func TestJUnitUncheckedResults(t *testing.T) {
+	var buf bytes.Buffer
+
+	vars := validators.Summary{Type: "variable", Checked: []string{"rules"}}
+	vars.AddMissingAttributeDocumentation("var.rules[*].ports", testRange("variables.tf", 3, 5))
+
+	assert.NoError(t, report.JUnit(&buf, []validators.Summary{vars}))
+
+	want := `<testsuite name="variable" tests="2" failures="1">
+    <testcase classname="variable" name="rules"></testcase>
+    <testcase classname="variable" name="var.rules[*].ports">
+      <failure type="missing-attribute-documentation" message="Missing attribute documentation: &#34;var.rules[*].ports&#34; is not documented">variables.tf:3:5</failure>
+    </testcase>`
+
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("wanted a test case for the unchecked result but got:\n%s", buf.String())
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+
+	assert.Error(t, report.Write(&buf, "yaml", testSummaries()))
+}
+
+func TestJUnitModules(t *testing.T) {
+	var buf bytes.Buffer
+
+	summaries := []validators.Summary{
+		{Type: "variable", Module: "modules/bucket", Checked: []string{"name"}},
+	}
+
+	assert.NoError(t, report.JUnit(&buf, summaries))
+
+	want := `<testsuite name="modules/bucket/variable" tests="1" failures="0">
+    <testcase classname="modules/bucket/variable" name="name"></testcase>`
+
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("wanted suite named after the module but got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,146 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
+	"github.com/mineiros-io/terradoc/internal/validators"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	toolName = "terradoc"
+	toolURI  = "https://github.com/mineiros-io/terradoc"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// rules describes the checks a result can refer to by its code
+func rules() []sarifRule {
+	rules := make([]sarifRule, len(validators.Rules))
+	for i, r := range validators.Rules {
+		rules[i] = sarifRule{ID: r.Code, ShortDescription: sarifMessage{Text: r.Description}}
+	}
+
+	return rules
+}
+
+// SARIF writes the summaries as a SARIF 2.1.0 log with a single run. File names are written
+// as relative URIs, so they should be relative to the repository root.
+func SARIF(w io.Writer, summaries []validators.Summary) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           toolName,
+				InformationURI: toolURI,
+				Rules:          rules(),
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	for _, s := range summaries {
+		for _, r := range s.Results {
+			run.Results = append(run.Results, newSARIFResult(r.Diagnostic(s.Type)))
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	})
+}
+
+func newSARIFResult(diag diagnostics.Diagnostic) sarifResult {
+	result := sarifResult{
+		RuleID:  diag.Code,
+		Level:   string(diag.Severity),
+		Message: sarifMessage{Text: diag.Message()},
+	}
+
+	if diag.Range.Filename == "" {
+		return result
+	}
+
+	location := sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(diag.Range.Filename)},
+		},
+	}
+
+	if diag.HasPos() {
+		location.PhysicalLocation.Region = &sarifRegion{
+			StartLine:   diag.Range.Start.Line,
+			StartColumn: diag.Range.Start.Column,
+			EndLine:     diag.Range.End.Line,
+			EndColumn:   diag.Range.End.Column,
+		}
+	}
+
+	result.Locations = []sarifLocation{location}
+
+	return result
+}
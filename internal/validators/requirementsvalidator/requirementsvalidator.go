@@ -0,0 +1,136 @@
+package requirementsvalidator
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/validators"
+)
+
+const CheckType = "requirement"
+
+const (
+	terraformVersionName = "terraform.required_version"
+	providersName        = "required_providers"
+)
+
+// Validate checks the `requirements` blocks of the document against the `terraform` blocks of the
+// .tf files. Blocks that are empty in the document are filled from the .tf files, so only blocks
+// listing requirements are checked.
+func Validate(doc entities.Doc, tfContent entities.ValidationContents) validators.Summary {
+	summary := validators.Summary{Type: CheckType}
+
+	defined := tfContent.Requirements
+
+	for _, documented := range doc.AllRequirements() {
+		if documented.FromCode || documented.IsEmpty() {
+			continue
+		}
+
+		validateTerraformVersion(&summary, documented, defined)
+		validateProviders(&summary, documented, defined)
+	}
+
+	summary.Sort()
+
+	return summary
+}
+
+func validateTerraformVersion(summary *validators.Summary, documented, defined entities.Requirements) {
+	if documented.TerraformVersion == "" {
+		return
+	}
+
+	summary.Checked = append(summary.Checked, terraformVersionName)
+
+	if !versionsMatch(documented.TerraformVersion, defined.TerraformVersion) {
+		summary.AddRequirementMismatch(
+			validators.ValueMismatchResult{
+				Name:            terraformVersionName,
+				DefinedValue:    valueString(defined.TerraformVersion),
+				DocumentedValue: valueString(documented.TerraformVersion),
+			},
+			documented.DefRange,
+		)
+	}
+}
+
+func validateProviders(summary *validators.Summary, documented, defined entities.Requirements) {
+	for _, provider := range documented.Providers {
+		name := providersName + "." + provider.Name
+
+		summary.Checked = append(summary.Checked, name)
+
+		definedProvider, ok := defined.Provider(provider.Name)
+		if !ok {
+			summary.AddMissingDefinition(name, provider.DefRange)
+
+			continue
+		}
+
+		if provider.Source != "" && !sourcesMatch(provider, definedProvider) {
+			summary.AddRequirementMismatch(
+				validators.ValueMismatchResult{
+					Name:            name + ".source",
+					DefinedValue:    valueString(definedProvider.Source),
+					DocumentedValue: valueString(provider.Source),
+				},
+				provider.DefRange,
+			)
+		}
+
+		if provider.Version != "" && !versionsMatch(provider.Version, definedProvider.Version) {
+			summary.AddRequirementMismatch(
+				validators.ValueMismatchResult{
+					Name:            name + ".version",
+					DefinedValue:    valueString(definedProvider.Version),
+					DocumentedValue: valueString(provider.Version),
+				},
+				provider.DefRange,
+			)
+		}
+	}
+
+	for _, provider := range defined.Providers {
+		if _, ok := documented.Provider(provider.Name); !ok {
+			name := providersName + "." + provider.Name
+
+			summary.Checked = append(summary.Checked, name)
+			summary.AddMissingDocumentation(name, provider.DefRange)
+		}
+	}
+}
+
+// versionsMatch reports whether two version constraints are the same, ignoring whitespace and the
+// order of the constraints, e.g. ">=1.0, <2.0" matches "< 2.0, >= 1.0"
+func versionsMatch(a, b string) bool {
+	return normalizeVersion(a) == normalizeVersion(b)
+}
+
+func normalizeVersion(version string) string {
+	constraints := strings.Split(version, ",")
+	for i, c := range constraints {
+		constraints[i] = strings.Join(strings.Fields(c), "")
+	}
+
+	sort.Strings(constraints)
+
+	return strings.Join(constraints, ",")
+}
+
+// sourcesMatch reports whether the documented source is the source of the defined provider. Sources
+// may leave out the default registry and providers without a source are hashicorp providers.
+func sourcesMatch(documented, defined entities.ProviderRequirement) bool {
+	return documented.RegistrySource() == defined.RegistrySource()
+}
+
+// valueString quotes a value for validation results
+func valueString(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+
+	return strconv.Quote(value)
+}
@@ -0,0 +1,116 @@
+package requirementsvalidator_test
+
+import (
+	"testing"
+
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/validators"
+	"github.com/mineiros-io/terradoc/internal/validators/requirementsvalidator"
+	"github.com/mineiros-io/terradoc/test"
+)
+
+func TestValidate(t *testing.T) {
+	defined := entities.Requirements{
+		TerraformVersion: ">= 1.0, < 2.0",
+		Providers: []entities.ProviderRequirement{
+			{Name: "aws", Source: "hashicorp/aws", Version: ">= 4.0"},
+			{Name: "random"},
+		},
+	}
+
+	tests := []struct {
+		desc                     string
+		documented               *entities.Requirements
+		wantMismatch             []validators.ValueMismatchResult
+		wantMissingDefinition    []string
+		wantMissingDocumentation []string
+	}{
+		{
+			desc: "when the requirements match",
+			documented: &entities.Requirements{
+				TerraformVersion: "<2.0,>=1.0",
+				Providers: []entities.ProviderRequirement{
+					{Name: "aws", Source: "registry.terraform.io/hashicorp/aws", Version: ">=4.0"},
+					{Name: "random", Source: "hashicorp/random"},
+				},
+			},
+		},
+		{
+			desc: "when the versions differ",
+			documented: &entities.Requirements{
+				TerraformVersion: ">= 0.15",
+				Providers: []entities.ProviderRequirement{
+					{Name: "aws", Version: "~> 3.0"},
+					{Name: "random", Version: ">= 3.0"},
+				},
+			},
+			wantMismatch: []validators.ValueMismatchResult{
+				{Name: "required_providers.aws.version", DefinedValue: `">= 4.0"`, DocumentedValue: `"~> 3.0"`},
+				{Name: "required_providers.random.version", DefinedValue: "(none)", DocumentedValue: `">= 3.0"`},
+				{Name: "terraform.required_version", DefinedValue: `">= 1.0, < 2.0"`, DocumentedValue: `">= 0.15"`},
+			},
+		},
+		{
+			desc: "when a source differs",
+			documented: &entities.Requirements{
+				Providers: []entities.ProviderRequirement{
+					{Name: "aws", Source: "acme/aws"},
+					{Name: "random"},
+				},
+			},
+			wantMismatch: []validators.ValueMismatchResult{
+				{Name: "required_providers.aws.source", DefinedValue: `"hashicorp/aws"`, DocumentedValue: `"acme/aws"`},
+			},
+		},
+		{
+			desc: "when providers are missing",
+			documented: &entities.Requirements{
+				Providers: []entities.ProviderRequirement{
+					{Name: "aws"},
+					{Name: "google"},
+				},
+			},
+			wantMissingDefinition:    []string{"required_providers.google"},
+			wantMissingDocumentation: []string{"required_providers.random"},
+		},
+		{
+			desc:       "when the requirements are filled from the .tf files",
+			documented: &entities.Requirements{TerraformVersion: ">= 0.15", FromCode: true},
+		},
+		{
+			desc:       "when the requirements are empty",
+			documented: &entities.Requirements{},
+		},
+		{
+			desc: "when no requirements are documented",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			doc := entities.Doc{Sections: []entities.Section{{Requirements: tt.documented}}}
+
+			summary := requirementsvalidator.Validate(doc, entities.ValidationContents{Requirements: defined})
+
+			assert.EqualInts(t, len(tt.wantMismatch), len(summary.ValueMismatches(validators.CodeRequirementMismatch)))
+			for i, want := range tt.wantMismatch {
+				got := summary.ValueMismatches(validators.CodeRequirementMismatch)[i]
+
+				assert.EqualStrings(t, want.Name, got.Name)
+				assert.EqualStrings(t, want.DefinedValue, got.DefinedValue)
+				assert.EqualStrings(t, want.DocumentedValue, got.DocumentedValue)
+			}
+
+			assert.EqualInts(t, len(tt.wantMissingDefinition), len(summary.MissingDefinition))
+			test.AssertHasStrings(t, tt.wantMissingDefinition, summary.MissingDefinition)
+			assert.EqualInts(t, len(tt.wantMissingDocumentation), len(summary.MissingDocumentation))
+			test.AssertHasStrings(t, tt.wantMissingDocumentation, summary.MissingDocumentation)
+
+			wantSuccess := len(tt.wantMismatch) == 0 && len(tt.wantMissingDefinition) == 0 && len(tt.wantMissingDocumentation) == 0
+			if summary.Success() != wantSuccess {
+				t.Errorf("wanted success to be %t, got %t", wantSuccess, summary.Success())
+			}
+		})
+	}
+}
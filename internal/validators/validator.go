@@ -1,34 +1,515 @@
 package validators
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/mineiros-io/terradoc/internal/diagnostics"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/types"
 )
 
+// Codes of the diagnostics reported by the validators
+const (
+	CodeMissingDefinition    = "missing-definition"
+	CodeMissingDocumentation = "missing-documentation"
+	CodeTypeMismatch         = "type-mismatch"
+	// Codes of the optional checks enabled by Options
+	CodeRequiredWithDefault = "required-with-default"
+	CodeDefaultMismatch     = "default-mismatch"
+	CodeDescriptionMismatch = "description-mismatch"
+	// CodeSensitiveMismatch is reported for variables and outputs that are sensitive in .tf files but
+	// not documented as sensitive
+	CodeSensitiveMismatch = "sensitive-mismatch"
+	// Codes of the checks of the attributes declared in object type constraints
+	CodeMissingAttributeDefinition    = "missing-attribute-definition"
+	CodeMissingAttributeDocumentation = "missing-attribute-documentation"
+	CodeAttributeTypeMismatch         = "attribute-type-mismatch"
+	// Codes of the optional checks of the attributes declared with `optional(type, default)`
+	CodeAttributeRequiredMismatch = "attribute-required-mismatch"
+	CodeAttributeDefaultMismatch  = "attribute-default-mismatch"
+	// Codes of the checks of the references used in the content of the document
+	CodeUndefinedReference = diagnostics.CodeUndefinedReference
+	CodeUnusedReference    = "unused-reference"
+	// CodeRequirementMismatch is reported by the optional check of the documented Terraform and
+	// provider requirements
+	CodeRequirementMismatch = "requirement-mismatch"
+)
+
+// Options enables optional checks of the validators
+type Options struct {
+	// RequiredDefault reports variables documented as required that have a default in the .tf files
+	// and attributes documented as required that are declared as optional or vice versa
+	RequiredDefault bool
+	// Defaults reports documented defaults of variables and attributes that differ from the defaults
+	// in the .tf files
+	Defaults bool
+	// Descriptions reports documented descriptions that differ from the descriptions in the .tf files
+	Descriptions bool
+	// Requirements reports documented Terraform and provider requirements that differ from the
+	// `terraform` blocks in the .tf files
+	Requirements bool
+	// Sensitive reports variables and outputs that are sensitive in the .tf files but not documented
+	// as sensitive
+	Sensitive bool
+}
+
 type TypeMismatchResult struct {
 	Name           string
 	DefinedType    string
 	DocumentedType string
 }
 
+// ValueMismatchResult is a value, e.g. a default, that differs between the documentation and the .tf files
+type ValueMismatchResult struct {
+	Name            string
+	DefinedValue    string
+	DocumentedValue string
+}
+
 type Summary struct {
-	Type                 string
+	Type string
+	// Module is the directory of the validated module. It is only set when several modules are validated.
+	Module               string
 	MissingDefinition    []string
 	MissingDocumentation []string
 	TypeMismatch         []TypeMismatchResult
+	// Results has the results of all checks with the source positions they refer to. The results of
+	// a single check are returned by Names, TypeMismatches and ValueMismatches.
+	Results []Result
+	// Checked has the names of all documented and defined blocks that were checked
+	Checked []string
+}
+
+// Result is a single problem found by a validator
+type Result struct {
+	// Code identifies the check that failed, e.g. CodeMissingDefinition
+	Code string
+	Name string
+	// Range is the source range of the documented or defined block the result refers to
+	Range hcl.Range
+	// DefinedType and DocumentedType are only set for type mismatches
+	DefinedType    string
+	DocumentedType string
+	// DefinedValue and DocumentedValue are only set for required, default and description mismatches
+	DefinedValue    string
+	DocumentedValue string
+}
+
+// Rule describes the check that reports the results with its code
+type Rule struct {
+	Code     string
+	Severity diagnostics.Severity
+	// Description describes the check independently of the checked blocks, e.g. for SARIF logs
+	Description string
+	// message returns the summary and the detail of the diagnostic of a result. The check type is the
+	// type of the checked blocks, e.g. "variable".
+	message func(checkType string, r Result) (string, string)
+}
+
+// Rules are the rules of all checks of the validators
+var Rules = []Rule{
+	{
+		Code:        CodeMissingDefinition,
+		Severity:    diagnostics.Error,
+		Description: "Documented block is not defined in any .tf files",
+		message: func(checkType string, r Result) (string, string) {
+			return fmt.Sprintf("Unknown %s documented", checkType),
+				fmt.Sprintf("%q is not defined in any .tf files", r.Name)
+		},
+	},
+	{
+		Code:        CodeMissingDocumentation,
+		Severity:    diagnostics.Error,
+		Description: "Defined block is not documented",
+		message: func(checkType string, r Result) (string, string) {
+			return fmt.Sprintf("Missing %s documentation", checkType),
+				fmt.Sprintf("%q is not documented", r.Name)
+		},
+	},
+	{
+		Code:        CodeTypeMismatch,
+		Severity:    diagnostics.Error,
+		Description: "Documented type does not match the type defined in .tf files",
+		message: func(checkType string, r Result) (string, string) {
+			return fmt.Sprintf("Type mismatch for %s", checkType),
+				fmt.Sprintf("%q is documented as %q but defined as %q in .tf files", r.Name, r.DocumentedType, r.DefinedType)
+		},
+	},
+	{
+		Code:        CodeSensitiveMismatch,
+		Severity:    diagnostics.Error,
+		Description: "Sensitive block in .tf files is not documented as sensitive",
+		message: func(checkType string, r Result) (string, string) {
+			return fmt.Sprintf("Sensitive %s not documented as sensitive", checkType),
+				fmt.Sprintf("%q is sensitive in .tf files but not documented as sensitive", r.Name)
+		},
+	},
+	{
+		Code:        CodeMissingAttributeDefinition,
+		Severity:    diagnostics.Error,
+		Description: "Documented attribute is not declared in the type in .tf files",
+		message: func(checkType string, r Result) (string, string) {
+			return "Unknown attribute documented",
+				fmt.Sprintf("%q is not declared in the %s type in .tf files", r.Name, checkType)
+		},
+	},
+	{
+		Code:        CodeMissingAttributeDocumentation,
+		Severity:    diagnostics.Error,
+		Description: "Attribute declared in the type in .tf files is not documented",
+		message: func(checkType string, r Result) (string, string) {
+			return "Missing attribute documentation",
+				fmt.Sprintf("%q is not documented", r.Name)
+		},
+	},
+	{
+		Code:        CodeAttributeTypeMismatch,
+		Severity:    diagnostics.Error,
+		Description: "Documented attribute type does not match the type declared in .tf files",
+		message: func(checkType string, r Result) (string, string) {
+			return "Type mismatch for attribute",
+				fmt.Sprintf("%q is documented as %q but declared as %q in .tf files", r.Name, r.DocumentedType, r.DefinedType)
+		},
+	},
+	{
+		Code:        CodeAttributeRequiredMismatch,
+		Severity:    diagnostics.Error,
+		Description: "Attribute documented as required is declared as optional in .tf files or vice versa",
+		message: func(checkType string, r Result) (string, string) {
+			return "Required mismatch for attribute",
+				fmt.Sprintf("%q is documented as %s but declared as %s in .tf files", r.Name, r.DocumentedValue, r.DefinedValue)
+		},
+	},
+	{
+		Code:        CodeAttributeDefaultMismatch,
+		Severity:    diagnostics.Error,
+		Description: "Documented attribute default does not match the optional default declared in .tf files",
+		message: func(checkType string, r Result) (string, string) {
+			return "Default mismatch for attribute",
+				fmt.Sprintf("%q is documented with default %s but declared with default %s in .tf files", r.Name, r.DocumentedValue, r.DefinedValue)
+		},
+	},
+	{
+		Code:        CodeRequiredWithDefault,
+		Severity:    diagnostics.Error,
+		Description: "Variable documented as required has a default in .tf files",
+		message: func(checkType string, r Result) (string, string) {
+			return fmt.Sprintf("Required %s has a default", checkType),
+				fmt.Sprintf("%q is documented as required but has a default in .tf files", r.Name)
+		},
+	},
+	{
+		Code:        CodeDefaultMismatch,
+		Severity:    diagnostics.Error,
+		Description: "Documented default does not match the default defined in .tf files",
+		message: func(checkType string, r Result) (string, string) {
+			return fmt.Sprintf("Default mismatch for %s", checkType),
+				fmt.Sprintf("%q is documented with default %s but defined with default %s in .tf files", r.Name, r.DocumentedValue, r.DefinedValue)
+		},
+	},
+	{
+		Code:        CodeDescriptionMismatch,
+		Severity:    diagnostics.Error,
+		Description: "Documented description does not match the description defined in .tf files",
+		message: func(checkType string, r Result) (string, string) {
+			return fmt.Sprintf("Description mismatch for %s", checkType),
+				fmt.Sprintf("%q is documented with description %q but defined with description %q in .tf files", r.Name, r.DocumentedValue, r.DefinedValue)
+		},
+	},
+	{
+		Code:        CodeUndefinedReference,
+		Severity:    diagnostics.Error,
+		Description: "Reference used in a link is not defined",
+		message: func(checkType string, r Result) (string, string) {
+			return "Undefined reference",
+				fmt.Sprintf("%q is not defined in the `references` block", r.Name)
+		},
+	},
+	{
+		// unused references do not break the document, so they are only warnings
+		Code:        CodeUnusedReference,
+		Severity:    diagnostics.Warning,
+		Description: "Defined reference is not used",
+		message: func(checkType string, r Result) (string, string) {
+			return "Unused reference",
+				fmt.Sprintf("%q is not used in any content or description", r.Name)
+		},
+	},
+	{
+		Code:        CodeRequirementMismatch,
+		Severity:    diagnostics.Error,
+		Description: "Documented Terraform or provider requirement does not match the terraform blocks in .tf files",
+		message: func(checkType string, r Result) (string, string) {
+			return "Requirement mismatch",
+				fmt.Sprintf("%q is documented as %s but declared as %s in .tf files", r.Name, r.DocumentedValue, r.DefinedValue)
+		},
+	},
+}
+
+// rule returns the rule of the code
+func rule(code string) (Rule, bool) {
+	for _, r := range Rules {
+		if r.Code == code {
+			return r, true
+		}
+	}
+
+	return Rule{}, false
+}
+
+// AddMissingDefinition adds a documented name that is not defined in any .tf files
+func (vs *Summary) AddMissingDefinition(name string, documented hcl.Range) {
+	vs.MissingDefinition = append(vs.MissingDefinition, name)
+	vs.Results = append(vs.Results, Result{Code: CodeMissingDefinition, Name: name, Range: documented})
+}
+
+// AddMissingDocumentation adds a defined name that is not documented
+func (vs *Summary) AddMissingDocumentation(name string, defined hcl.Range) {
+	vs.MissingDocumentation = append(vs.MissingDocumentation, name)
+	vs.Results = append(vs.Results, Result{Code: CodeMissingDocumentation, Name: name, Range: defined})
+}
+
+// AddTypeMismatch adds a name whose documented type does not match its defined type
+func (vs *Summary) AddTypeMismatch(mismatch TypeMismatchResult, documented hcl.Range) {
+	vs.TypeMismatch = append(vs.TypeMismatch, mismatch)
+	vs.Results = append(vs.Results, mismatch.result(CodeTypeMismatch, documented))
+}
+
+// AddSensitiveMismatch adds a name that is sensitive in the .tf files but not documented as sensitive
+func (vs *Summary) AddSensitiveMismatch(name string, documented hcl.Range) {
+	vs.Results = append(vs.Results, Result{Code: CodeSensitiveMismatch, Name: name, Range: documented})
+}
+
+// AddMissingAttributeDefinition adds the path of a documented attribute that is not declared in the
+// type constraint in the .tf files
+func (vs *Summary) AddMissingAttributeDefinition(path string, documented hcl.Range) {
+	vs.Results = append(vs.Results, Result{Code: CodeMissingAttributeDefinition, Name: path, Range: documented})
+}
+
+// AddMissingAttributeDocumentation adds the path of an attribute declared in the type constraint
+// in the .tf files that is not documented
+func (vs *Summary) AddMissingAttributeDocumentation(path string, defined hcl.Range) {
+	vs.Results = append(vs.Results, Result{Code: CodeMissingAttributeDocumentation, Name: path, Range: defined})
+}
+
+// AddAttributeTypeMismatch adds an attribute whose documented type does not match its declared type.
+// The name of the mismatch is the path of the attribute.
+func (vs *Summary) AddAttributeTypeMismatch(mismatch TypeMismatchResult, documented hcl.Range) {
+	vs.Results = append(vs.Results, mismatch.result(CodeAttributeTypeMismatch, documented))
+}
+
+// AddAttributeRequiredMismatch adds an attribute documented as required that is declared as
+// optional or vice versa. The values of the mismatch are "required" or "optional".
+func (vs *Summary) AddAttributeRequiredMismatch(mismatch ValueMismatchResult, documented hcl.Range) {
+	vs.Results = append(vs.Results, mismatch.result(CodeAttributeRequiredMismatch, documented))
+}
+
+// AddAttributeDefaultMismatch adds an attribute whose documented default does not match the default
+// of its `optional(type, default)` declaration
+func (vs *Summary) AddAttributeDefaultMismatch(mismatch ValueMismatchResult, documented hcl.Range) {
+	vs.Results = append(vs.Results, mismatch.result(CodeAttributeDefaultMismatch, documented))
+}
+
+// AddRequiredWithDefault adds a name documented as required that has a default in the .tf files
+func (vs *Summary) AddRequiredWithDefault(name string, documented hcl.Range) {
+	vs.Results = append(vs.Results, Result{Code: CodeRequiredWithDefault, Name: name, Range: documented})
+}
+
+// AddDefaultMismatch adds a name whose documented default does not match its defined default
+func (vs *Summary) AddDefaultMismatch(mismatch ValueMismatchResult, documented hcl.Range) {
+	vs.Results = append(vs.Results, mismatch.result(CodeDefaultMismatch, documented))
+}
+
+// AddDescriptionMismatch adds a name whose documented description does not match its defined description
+func (vs *Summary) AddDescriptionMismatch(mismatch ValueMismatchResult, documented hcl.Range) {
+	vs.Results = append(vs.Results, mismatch.result(CodeDescriptionMismatch, documented))
+}
+
+// AddUndefinedReference adds a reference that is used in a link but not defined
+func (vs *Summary) AddUndefinedReference(name string, used hcl.Range) {
+	vs.Results = append(vs.Results, Result{Code: CodeUndefinedReference, Name: name, Range: used})
+}
+
+// AddUnusedReference adds a defined reference that is not used anywhere in the document
+func (vs *Summary) AddUnusedReference(name string, defined hcl.Range) {
+	vs.Results = append(vs.Results, Result{Code: CodeUnusedReference, Name: name, Range: defined})
+}
+
+// AddRequirementMismatch adds a requirement whose documented value does not match the value declared
+// in the `terraform` blocks
+func (vs *Summary) AddRequirementMismatch(mismatch ValueMismatchResult, documented hcl.Range) {
+	vs.Results = append(vs.Results, mismatch.result(CodeRequirementMismatch, documented))
+}
+
+func (m TypeMismatchResult) result(code string, rng hcl.Range) Result {
+	return Result{
+		Code:           code,
+		Name:           m.Name,
+		Range:          rng,
+		DefinedType:    m.DefinedType,
+		DocumentedType: m.DocumentedType,
+	}
+}
+
+func (m ValueMismatchResult) result(code string, rng hcl.Range) Result {
+	return Result{
+		Code:            code,
+		Name:            m.Name,
+		Range:           rng,
+		DefinedValue:    m.DefinedValue,
+		DocumentedValue: m.DocumentedValue,
+	}
+}
+
+// Names returns the names of the results of the check with the code
+func (vs Summary) Names(code string) []string {
+	var names []string
+	for _, r := range vs.Results {
+		if r.Code == code {
+			names = append(names, r.Name)
+		}
+	}
+
+	return names
+}
+
+// TypeMismatches returns the type mismatches of the check with the code
+func (vs Summary) TypeMismatches(code string) []TypeMismatchResult {
+	var mismatches []TypeMismatchResult
+	for _, r := range vs.Results {
+		if r.Code == code {
+			mismatches = append(mismatches, TypeMismatchResult{
+				Name:           r.Name,
+				DefinedType:    r.DefinedType,
+				DocumentedType: r.DocumentedType,
+			})
+		}
+	}
+
+	return mismatches
 }
 
+// ValueMismatches returns the value mismatches of the check with the code
+func (vs Summary) ValueMismatches(code string) []ValueMismatchResult {
+	var mismatches []ValueMismatchResult
+	for _, r := range vs.Results {
+		if r.Code == code {
+			mismatches = append(mismatches, ValueMismatchResult{
+				Name:            r.Name,
+				DefinedValue:    r.DefinedValue,
+				DocumentedValue: r.DocumentedValue,
+			})
+		}
+	}
+
+	return mismatches
+}
+
+// Sort sorts the checked names and the results by their position so they are
+// always reported in the same order
+func (vs *Summary) Sort() {
+	sort.Strings(vs.Checked)
+	sort.SliceStable(vs.Results, func(i, j int) bool {
+		a, b := vs.Results[i], vs.Results[j]
+
+		if a.Range.Filename != b.Range.Filename {
+			return a.Range.Filename < b.Range.Filename
+		}
+
+		if a.Range.Start.Byte != b.Range.Start.Byte {
+			return a.Range.Start.Byte < b.Range.Start.Byte
+		}
+
+		return a.Name < b.Name
+	})
+}
+
+// Diagnostics returns the results as diagnostics
+func (vs Summary) Diagnostics() diagnostics.Diagnostics {
+	diags := make(diagnostics.Diagnostics, len(vs.Results))
+	for i, r := range vs.Results {
+		diags[i] = r.Diagnostic(vs.Type)
+	}
+
+	return diags
+}
+
+// Diagnostic returns the result as a diagnostic. The check type is the type of the
+// checked blocks, e.g. "variable".
+func (r Result) Diagnostic(checkType string) diagnostics.Diagnostic {
+	rl, ok := rule(r.Code)
+	if !ok {
+		return diagnostics.New(r.Code, r.Range, fmt.Sprintf("Invalid %s %q", checkType, r.Name), "")
+	}
+
+	summary, detail := rl.message(checkType, r)
+
+	diag := diagnostics.New(r.Code, r.Range, summary, detail)
+	diag.Severity = rl.Severity
+
+	return diag
+}
+
+// Success reports whether no errors were found. Results of rules with warning severity, e.g. unused
+// references, do not fail the validation.
 func (vs Summary) Success() bool {
-	return len(vs.MissingDocumentation) == 0 &&
-		len(vs.MissingDefinition) == 0 &&
-		len(vs.TypeMismatch) == 0
+	for _, r := range vs.Results {
+		if rl, ok := rule(r.Code); !ok || rl.Severity == diagnostics.Error {
+			return false
+		}
+	}
+
+	return true
 }
 
+// DefaultsMatch reports whether two defaults are the same JSON value. Defaults that are not valid JSON,
+// e.g. references to other variables, must be equal as text.
+func DefaultsMatch(a, b json.RawMessage) bool {
+	var valueA, valueB interface{}
+
+	if json.Unmarshal(a, &valueA) != nil || json.Unmarshal(b, &valueB) != nil {
+		return bytes.Equal(bytes.TrimSpace(a), bytes.TrimSpace(b))
+	}
+
+	return reflect.DeepEqual(valueA, valueB)
+}
+
+// DefaultString returns the default as compact JSON for validation results
+func DefaultString(value json.RawMessage) string {
+	if len(value) == 0 {
+		return "(none)"
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, value); err != nil {
+		return string(bytes.TrimSpace(value))
+	}
+
+	return buf.String()
+}
+
+// DescriptionsMatch reports whether two descriptions are equal ignoring differences in whitespace
+func DescriptionsMatch(a, b string) bool {
+	return strings.Join(strings.Fields(a), " ") == strings.Join(strings.Fields(b), " ")
+}
+
+// TypesMatch reports whether two types are compatible, comparing the full type trees. Labeled objects
+// match any object since their attributes are documented separately.
 func TypesMatch(typeA, typeB *entities.Type) bool {
 	if typeA == nil && typeB == nil {
 		return true
 	}
 
+	if typeA == nil || typeB == nil {
+		return false
+	}
+
 	if typeA.TFType == types.TerraformAny || typeB.TFType == types.TerraformAny {
 		if typeA.TFType == types.TerraformObject || typeB.TFType == types.TerraformObject {
 			return true
@@ -44,9 +525,56 @@ func TypesMatch(typeA, typeB *entities.Type) bool {
 	}
 
 	if typeA.TFType == types.TerraformObject && typeB.TFType == types.TerraformObject {
-		return true
+		return fieldsMatch(typeA, typeB)
+	}
+
+	if typeA.TFType == types.TerraformTuple && typeB.TFType == types.TerraformTuple {
+		return elementsMatch(typeA, typeB)
+	}
+
+	if typeA.TFType == types.TerraformResource && typeB.TFType == types.TerraformResource {
+		return typeA.Label == typeB.Label
 	}
 
 	return (typeA.TFType == typeB.TFType) &&
 		TypesMatch(typeA.Nested, typeB.Nested)
 }
+
+// fieldsMatch compares the attribute types of two object types if both declare them
+func fieldsMatch(typeA, typeB *entities.Type) bool {
+	if !typeA.HasFields() || !typeB.HasFields() {
+		return true
+	}
+
+	if len(typeA.Fields) != len(typeB.Fields) {
+		return false
+	}
+
+	for name, fieldA := range typeA.Fields {
+		fieldB, ok := typeB.Fields[name]
+		if !ok || !TypesMatch(&fieldA, &fieldB) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// elementsMatch compares the element types of two tuple types if both declare them
+func elementsMatch(typeA, typeB *entities.Type) bool {
+	if len(typeA.Elements) == 0 || len(typeB.Elements) == 0 {
+		return true
+	}
+
+	if len(typeA.Elements) != len(typeB.Elements) {
+		return false
+	}
+
+	for i := range typeA.Elements {
+		if !TypesMatch(&typeA.Elements[i], &typeB.Elements[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,7 +1,9 @@
 package varsvalidator
 
 import (
+	"github.com/hashicorp/hcl/v2"
 	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/types"
 	"github.com/mineiros-io/terradoc/internal/validators"
 )
 
@@ -15,31 +17,229 @@ type variableValidation struct {
 }
 
 func Validate(doc entities.Doc, varsFile entities.ValidationContents) validators.Summary {
+	return ValidateWithOptions(doc, varsFile, validators.Options{})
+}
+
+// ValidateWithOptions validates the variables with the optional checks enabled in opts
+func ValidateWithOptions(doc entities.Doc, varsFile entities.ValidationContents, opts validators.Options) validators.Summary {
 	summary := validators.Summary{Type: CheckType}
 
 	validationResult := validateVariables(doc.AllVariables(), varsFile.Variables)
 
 	for varName, check := range validationResult {
+		summary.Checked = append(summary.Checked, varName)
+
 		switch {
 		case check.defined.Name == "":
-			summary.MissingDefinition = append(summary.MissingDefinition, varName)
+			summary.AddMissingDefinition(varName, check.documented.DefRange)
+
+			continue
 		case check.documented.Name == "":
-			summary.MissingDocumentation = append(summary.MissingDocumentation, varName)
+			summary.AddMissingDocumentation(varName, check.defined.DefRange)
+
+			continue
 		case !validators.TypesMatch(&check.defined.Type, &check.documented.Type):
-			summary.TypeMismatch = append(
-				summary.TypeMismatch,
+			summary.AddTypeMismatch(
 				validators.TypeMismatchResult{
 					Name:           varName,
 					DefinedType:    check.defined.Type.AsString(),
 					DocumentedType: check.documented.Type.AsString(),
 				},
+				check.documented.DefRange,
 			)
+		default:
+			documented := check.documented.Attributes
+			if len(documented) == 0 {
+				documented = fieldAttributes(check.documented.Type, check.documented.DefRange)
+			}
+
+			validateAttributes(&summary, "var."+varName, check.documented.Type, documented, check.defined.Attributes, opts)
+		}
+
+		// sensitive values must be documented so readers know which inputs carry secrets
+		if opts.Sensitive && check.defined.Sensitive && !check.documented.Sensitive {
+			summary.AddSensitiveMismatch(varName, check.documented.DefRange)
 		}
+
+		validateOptional(&summary, check, opts)
 	}
 
+	summary.Sort()
+
 	return summary
 }
 
+// validateAttributes compares the documented attributes with the attributes declared by the object type
+// constraint in the .tf files, recursing into the attributes found in both. Types without declared
+// attributes, e.g. `any` or labeled objects, are not checked. The paths of the attributes are added to
+// the checked names.
+func validateAttributes(summary *validators.Summary, parentPath string, parentType entities.Type, documented, defined []entities.Attribute, opts validators.Options) {
+	if len(defined) == 0 {
+		return
+	}
+
+	path := parentPath + collectionPath(parentType)
+
+	definedByName := map[string]entities.Attribute{}
+	for _, attr := range defined {
+		definedByName[attr.Name] = attr
+	}
+
+	documentedNames := map[string]bool{}
+
+	for _, doc := range documented {
+		documentedNames[doc.Name] = true
+		attrPath := path + "." + doc.Name
+
+		summary.Checked = append(summary.Checked, attrPath)
+
+		def, ok := definedByName[doc.Name]
+		if !ok {
+			summary.AddMissingAttributeDefinition(attrPath, doc.DefRange)
+
+			continue
+		}
+
+		if !validators.TypesMatch(&def.Type, &doc.Type) {
+			summary.AddAttributeTypeMismatch(
+				validators.TypeMismatchResult{
+					Name:           attrPath,
+					DefinedType:    def.Type.AsString(),
+					DocumentedType: doc.Type.AsString(),
+				},
+				doc.DefRange,
+			)
+
+			continue
+		}
+
+		nested := doc.Attributes
+		if len(nested) == 0 {
+			nested = fieldAttributes(doc.Type, doc.DefRange)
+		}
+
+		validateOptionalAttribute(summary, attrPath, doc, def, opts)
+		validateAttributes(summary, attrPath, doc.Type, nested, def.Attributes, opts)
+	}
+
+	for _, def := range defined {
+		if !documentedNames[def.Name] {
+			attrPath := path + "." + def.Name
+
+			summary.Checked = append(summary.Checked, attrPath)
+			summary.AddMissingAttributeDocumentation(attrPath, def.DefRange)
+		}
+	}
+}
+
+// fieldAttributes returns the attributes documented by the fields of a documented `object({...})`
+// type, which may be wrapped by collection types. The attributes refer to the documented block at
+// rng since fields have no source range of their own.
+func fieldAttributes(t entities.Type, rng hcl.Range) []entities.Attribute {
+	obj := &t
+	for obj.TFType != types.TerraformObject && obj.Nested != nil {
+		obj = obj.Nested
+	}
+
+	var attributes []entities.Attribute
+
+	for _, name := range obj.FieldNames() {
+		field := obj.Fields[name]
+
+		attributes = append(attributes, entities.Attribute{
+			Name:       name,
+			Type:       field.WithoutModifier(),
+			Required:   !field.Optional,
+			Default:    field.Default,
+			Attributes: fieldAttributes(field, rng),
+			DefRange:   rng,
+		})
+	}
+
+	return attributes
+}
+
+// collectionPath returns the path of the elements of collection types, e.g. `[*]` for `list(object)`
+// and `[*][*]` for `list(map(object))`
+func collectionPath(t entities.Type) string {
+	var path string
+
+	for current := &t; current != nil; current = current.Nested {
+		switch current.TFType {
+		case types.TerraformList, types.TerraformSet, types.TerraformMap:
+			path += "[*]"
+		}
+	}
+
+	return path
+}
+
+// validateOptional runs the optional checks on a variable that is both documented and defined
+func validateOptional(summary *validators.Summary, check variableValidation, opts validators.Options) {
+	name := check.documented.Name
+
+	if opts.RequiredDefault && check.documented.Required && len(check.defined.Default) > 0 {
+		summary.AddRequiredWithDefault(name, check.documented.DefRange)
+	}
+
+	// only documented defaults are compared, a missing default may be described in the description
+	if opts.Defaults && len(check.documented.Default) > 0 && !validators.DefaultsMatch(check.defined.Default, check.documented.Default) {
+		summary.AddDefaultMismatch(
+			validators.ValueMismatchResult{
+				Name:            name,
+				DefinedValue:    validators.DefaultString(check.defined.Default),
+				DocumentedValue: validators.DefaultString(check.documented.Default),
+			},
+			check.documented.DefRange,
+		)
+	}
+
+	if opts.Descriptions && check.defined.Description != "" && !validators.DescriptionsMatch(check.defined.Description, check.documented.Description) {
+		summary.AddDescriptionMismatch(
+			validators.ValueMismatchResult{
+				Name:            name,
+				DefinedValue:    check.defined.Description,
+				DocumentedValue: check.documented.Description,
+			},
+			check.documented.DefRange,
+		)
+	}
+}
+
+// validateOptionalAttribute runs the optional checks on an attribute that is both documented and
+// declared. Attributes are declared as optional and with a default by the `optional(type, default)` modifier.
+func validateOptionalAttribute(summary *validators.Summary, path string, documented, defined entities.Attribute, opts validators.Options) {
+	if opts.RequiredDefault && documented.Required != defined.Required {
+		summary.AddAttributeRequiredMismatch(
+			validators.ValueMismatchResult{
+				Name:            path,
+				DefinedValue:    requiredString(defined.Required),
+				DocumentedValue: requiredString(documented.Required),
+			},
+			documented.DefRange,
+		)
+	}
+
+	if opts.Defaults && len(documented.Default) > 0 && !validators.DefaultsMatch(defined.Default, documented.Default) {
+		summary.AddAttributeDefaultMismatch(
+			validators.ValueMismatchResult{
+				Name:            path,
+				DefinedValue:    validators.DefaultString(defined.Default),
+				DocumentedValue: validators.DefaultString(documented.Default),
+			},
+			documented.DefRange,
+		)
+	}
+}
+
+func requiredString(required bool) string {
+	if required {
+		return "required"
+	}
+
+	return "optional"
+}
+
 func validateVariables(docVars, varFileVars []entities.Variable) variableValidationChecks {
 	result := variableValidationChecks{}
 
@@ -1,8 +1,12 @@
 package varsvalidator_test
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/madlambda/spells/assert"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/types"
 	"github.com/mineiros-io/terradoc/internal/validators"
@@ -87,6 +91,63 @@ func TestValidate(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "when a variable has deeply nested types that differ on doc and variables file",
+			variablesFileVariables: entities.VariableCollection{
+				{
+					Name: "matrix",
+					Type: entities.Type{
+						TFType: types.TerraformList,
+						Nested: &entities.Type{
+							TFType: types.TerraformList,
+							Nested: &entities.Type{TFType: types.TerraformNumber},
+						},
+					},
+				},
+				{
+					Name: "beers",
+					Type: entities.Type{
+						TFType: types.TerraformMap,
+						Nested: &entities.Type{
+							TFType: types.TerraformList,
+							Nested: &entities.Type{
+								TFType: types.TerraformObject,
+								Fields: map[string]entities.Type{"name": {TFType: types.TerraformString}},
+							},
+						},
+					},
+				},
+			},
+			docVariables: entities.VariableCollection{
+				{
+					Name: "matrix",
+					Type: entities.Type{
+						TFType: types.TerraformList,
+						Nested: &entities.Type{
+							TFType: types.TerraformList,
+							Nested: &entities.Type{TFType: types.TerraformString},
+						},
+					},
+				},
+				{
+					Name: "beers",
+					Type: entities.Type{
+						TFType: types.TerraformMap,
+						Nested: &entities.Type{
+							TFType: types.TerraformList,
+							Nested: &entities.Type{TFType: types.TerraformObject, Label: "beer"},
+						},
+					},
+				},
+			},
+			wantTypeMismatch: []validators.TypeMismatchResult{
+				{
+					Name:           "matrix",
+					DefinedType:    "list(list(number))",
+					DocumentedType: "list(list(string))",
+				},
+			},
+		},
 		{
 			desc: "when a variable is missing from variables file, another missing from doc and another with type mismatch",
 			docVariables: entities.VariableCollection{
@@ -144,3 +205,359 @@ func definitionFromVariables(variables entities.VariableCollection) entities.Doc
 func variableFileFromVariables(variables entities.VariableCollection) entities.ValidationContents {
 	return entities.ValidationContents{Variables: variables}
 }
+
+func TestValidateDiagnostics(t *testing.T) {
+	docRange := hcl.Range{Filename: "README.tfdoc.hcl", Start: hcl.Pos{Line: 10, Column: 3, Byte: 120}}
+	tfRange := hcl.Range{Filename: "variables.tf", Start: hcl.Pos{Line: 1, Column: 1}}
+
+	def := definitionFromVariables(entities.VariableCollection{
+		{Name: "name", Type: entities.Type{TFType: types.TerraformString}, DefRange: docRange},
+	})
+	of := variableFileFromVariables(entities.VariableCollection{
+		{Name: "age", Type: entities.Type{TFType: types.TerraformNumber}, DefRange: tfRange},
+	})
+
+	got := varsvalidator.Validate(def, of)
+	diags := got.Diagnostics()
+
+	assert.EqualInts(t, 2, len(diags))
+
+	assert.EqualStrings(t, "README.tfdoc.hcl:10:3", diags[0].Pos())
+	assert.EqualStrings(t, validators.CodeMissingDefinition, diags[0].Code)
+	assert.EqualStrings(t, `Unknown variable documented: "name" is not defined in any .tf files`, diags[0].Message())
+
+	assert.EqualStrings(t, "variables.tf:1:1", diags[1].Pos())
+	assert.EqualStrings(t, validators.CodeMissingDocumentation, diags[1].Code)
+	assert.EqualStrings(t, `Missing variable documentation: "age" is not documented`, diags[1].Message())
+}
+
+func TestValidateWithOptions(t *testing.T) {
+	stringType := entities.Type{TFType: types.TerraformString}
+
+	tests := []struct {
+		desc                    string
+		opts                    validators.Options
+		docVariable             entities.Variable
+		definedVariable         entities.Variable
+		wantRequiredWithDefault []string
+		wantDefaultMismatch     []validators.ValueMismatchResult
+		wantDescriptionMismatch []validators.ValueMismatchResult
+	}{
+		{
+			desc:                    "when a required variable has a default",
+			opts:                    validators.Options{RequiredDefault: true},
+			docVariable:             entities.Variable{Name: "name", Type: stringType, Required: true},
+			definedVariable:         entities.Variable{Name: "name", Type: stringType, Default: []byte(`"beer"`)},
+			wantRequiredWithDefault: []string{"name"},
+		},
+		{
+			desc:            "when a required variable has a default and the check is disabled",
+			docVariable:     entities.Variable{Name: "name", Type: stringType, Required: true},
+			definedVariable: entities.Variable{Name: "name", Type: stringType, Default: []byte(`"beer"`)},
+		},
+		{
+			desc:            "when defaults are the same JSON value",
+			opts:            validators.Options{Defaults: true},
+			docVariable:     entities.Variable{Name: "tags", Type: stringType, Default: []byte(`{"a": 1, "b": [true]}`)},
+			definedVariable: entities.Variable{Name: "tags", Type: stringType, Default: []byte(`{"b":[true],"a":1}`)},
+		},
+		{
+			desc:            "when defaults differ",
+			opts:            validators.Options{Defaults: true},
+			docVariable:     entities.Variable{Name: "tags", Type: stringType, Default: []byte(`{"a": 1}`)},
+			definedVariable: entities.Variable{Name: "tags", Type: stringType, Default: []byte(`{"a": 2}`)},
+			wantDefaultMismatch: []validators.ValueMismatchResult{
+				{Name: "tags", DefinedValue: `{"a":2}`, DocumentedValue: `{"a":1}`},
+			},
+		},
+		{
+			desc:            "when a documented default is not defined",
+			opts:            validators.Options{Defaults: true},
+			docVariable:     entities.Variable{Name: "tags", Type: stringType, Default: []byte(`{}`)},
+			definedVariable: entities.Variable{Name: "tags", Type: stringType},
+			wantDefaultMismatch: []validators.ValueMismatchResult{
+				{Name: "tags", DefinedValue: "(none)", DocumentedValue: "{}"},
+			},
+		},
+		{
+			desc:            "when only the defined variable has a default",
+			opts:            validators.Options{Defaults: true},
+			docVariable:     entities.Variable{Name: "tags", Type: stringType},
+			definedVariable: entities.Variable{Name: "tags", Type: stringType, Default: []byte(`{}`)},
+		},
+		{
+			desc:            "when descriptions differ only in whitespace",
+			opts:            validators.Options{Descriptions: true},
+			docVariable:     entities.Variable{Name: "name", Type: stringType, Description: "The name\nof the bucket."},
+			definedVariable: entities.Variable{Name: "name", Type: stringType, Description: "The name of the bucket."},
+		},
+		{
+			desc:            "when descriptions differ",
+			opts:            validators.Options{Descriptions: true},
+			docVariable:     entities.Variable{Name: "name", Type: stringType, Description: "The name of the bucket."},
+			definedVariable: entities.Variable{Name: "name", Type: stringType, Description: "The bucket name."},
+			wantDescriptionMismatch: []validators.ValueMismatchResult{
+				{Name: "name", DefinedValue: "The bucket name.", DocumentedValue: "The name of the bucket."},
+			},
+		},
+		{
+			desc:            "when the defined variable has no description",
+			opts:            validators.Options{Descriptions: true},
+			docVariable:     entities.Variable{Name: "name", Type: stringType, Description: "The name of the bucket."},
+			definedVariable: entities.Variable{Name: "name", Type: stringType},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			def := definitionFromVariables(entities.VariableCollection{tt.docVariable})
+			of := variableFileFromVariables(entities.VariableCollection{tt.definedVariable})
+
+			got := varsvalidator.ValidateWithOptions(def, of, tt.opts)
+
+			test.AssertHasStrings(t, tt.wantRequiredWithDefault, got.Names(validators.CodeRequiredWithDefault))
+
+			if diff := cmp.Diff(tt.wantDefaultMismatch, got.ValueMismatches(validators.CodeDefaultMismatch)); diff != "" {
+				t.Errorf("Default mismatches are not expected (-want +got):\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tt.wantDescriptionMismatch, got.ValueMismatches(validators.CodeDescriptionMismatch)); diff != "" {
+				t.Errorf("Description mismatches are not expected (-want +got):\n%s", diff)
+			}
+
+			wantSuccess := len(tt.wantRequiredWithDefault)+len(tt.wantDefaultMismatch)+len(tt.wantDescriptionMismatch) == 0
+			if got.Success() != wantSuccess {
+				t.Errorf("wanted success to be %t", wantSuccess)
+			}
+		})
+	}
+}
+
+func TestValidateAttributes(t *testing.T) {
+	docRange := func(line int) hcl.Range {
+		return hcl.Range{Filename: "README.tfdoc.hcl", Start: hcl.Pos{Line: line, Column: 1, Byte: line}}
+	}
+
+	numberType := entities.Type{TFType: types.TerraformNumber}
+	stringType := entities.Type{TFType: types.TerraformString}
+	listOfObjects := entities.Type{TFType: types.TerraformList, Nested: &entities.Type{TFType: types.TerraformObject}}
+
+	docVariable := entities.Variable{
+		Name: "rules",
+		Type: entities.Type{TFType: types.TerraformList, Nested: &entities.Type{TFType: types.TerraformObject, Label: "rule"}},
+		Attributes: []entities.Attribute{
+			{Name: "name", Type: stringType, DefRange: docRange(1)},
+			{Name: "ports", Type: entities.Type{TFType: types.TerraformList, Nested: &stringType}, DefRange: docRange(2)},
+			{Name: "priority", Type: numberType, DefRange: docRange(3)},
+			{
+				Name:     "targets",
+				Type:     entities.Type{TFType: types.TerraformList, Nested: &entities.Type{TFType: types.TerraformObject, Label: "target"}},
+				DefRange: docRange(4),
+				Attributes: []entities.Attribute{
+					{Name: "id", Type: stringType, DefRange: docRange(5)},
+				},
+			},
+		},
+	}
+
+	definedVariable := entities.Variable{
+		Name: "rules",
+		Type: listOfObjects,
+		Attributes: []entities.Attribute{
+			{Name: "name", Type: stringType},
+			{Name: "ports", Type: entities.Type{TFType: types.TerraformList, Nested: &numberType}},
+			{Name: "protocol", Type: stringType},
+			{
+				Name: "targets",
+				Type: listOfObjects,
+				Attributes: []entities.Attribute{
+					{Name: "id", Type: stringType},
+					{Name: "weight", Type: numberType},
+				},
+			},
+		},
+	}
+
+	got := varsvalidator.Validate(
+		definitionFromVariables(entities.VariableCollection{docVariable}),
+		variableFileFromVariables(entities.VariableCollection{definedVariable}),
+	)
+
+	test.AssertHasStrings(t, []string{"var.rules[*].priority"}, got.Names(validators.CodeMissingAttributeDefinition))
+	test.AssertHasStrings(t, []string{"var.rules[*].protocol", "var.rules[*].targets[*].weight"}, got.Names(validators.CodeMissingAttributeDocumentation))
+	test.AssertHasTypeMismatches(t, []validators.TypeMismatchResult{
+		{Name: "var.rules[*].ports", DefinedType: "list(number)", DocumentedType: "list(string)"},
+	}, got.TypeMismatches(validators.CodeAttributeTypeMismatch))
+
+	// attribute results are reported as checked names so reports list them
+	test.AssertHasStrings(t, []string{
+		"rules",
+		"var.rules[*].name",
+		"var.rules[*].ports",
+		"var.rules[*].priority",
+		"var.rules[*].protocol",
+		"var.rules[*].targets",
+		"var.rules[*].targets[*].id",
+		"var.rules[*].targets[*].weight",
+	}, got.Checked)
+
+	if got.Success() {
+		t.Error("wanted attribute mismatches to fail the validation")
+	}
+}
+
+func TestValidateAttributesFromTypeFields(t *testing.T) {
+	stringType := entities.Type{TFType: types.TerraformString}
+	numberType := entities.Type{TFType: types.TerraformNumber}
+
+	optionalName := stringType
+	optionalName.Optional = true
+	optionalName.Default = json.RawMessage(`"x"`)
+
+	// type = object({ name = optional(string, "x"), n = number }) without attribute blocks
+	docVariable := entities.Variable{
+		Name: "a",
+		Type: entities.Type{
+			TFType: types.TerraformObject,
+			Fields: map[string]entities.Type{"name": optionalName, "n": numberType},
+		},
+	}
+
+	definedVariable := entities.Variable{
+		Name: "a",
+		Type: docVariable.Type,
+		Attributes: []entities.Attribute{
+			{Name: "name", Type: stringType, Default: json.RawMessage(`"x"`)},
+			{Name: "n", Type: numberType, Required: true},
+		},
+	}
+
+	got := varsvalidator.ValidateWithOptions(
+		definitionFromVariables(entities.VariableCollection{docVariable}),
+		variableFileFromVariables(entities.VariableCollection{definedVariable}),
+		validators.Options{RequiredDefault: true, Defaults: true},
+	)
+
+	if !got.Success() {
+		t.Errorf("wanted the fields of the documented type to document the attributes, got %v", got.Results)
+	}
+
+	test.AssertHasStrings(t, []string{"var.a.name", "var.a.n"}, got.Checked)
+}
+
+func TestValidateAttributesWithoutDeclaredAttributes(t *testing.T) {
+	docVariable := entities.Variable{
+		Name: "person",
+		Type: entities.Type{TFType: types.TerraformObject, Label: "person"},
+		Attributes: []entities.Attribute{
+			{Name: "name", Type: entities.Type{TFType: types.TerraformString}},
+		},
+	}
+
+	definedVariable := entities.Variable{Name: "person", Type: entities.Type{TFType: types.TerraformAny}}
+
+	got := varsvalidator.Validate(
+		definitionFromVariables(entities.VariableCollection{docVariable}),
+		variableFileFromVariables(entities.VariableCollection{definedVariable}),
+	)
+
+	if !got.Success() {
+		t.Errorf("wanted attributes of variables without declared attributes to be ignored, got %v", got.Results)
+	}
+}
+
+func TestValidateOptionalAttributes(t *testing.T) {
+	stringType := entities.Type{TFType: types.TerraformString}
+	numberType := entities.Type{TFType: types.TerraformNumber}
+
+	docVariable := entities.Variable{
+		Name: "rule",
+		Type: entities.Type{TFType: types.TerraformObject, Label: "rule"},
+		Attributes: []entities.Attribute{
+			{Name: "name", Type: stringType},
+			{Name: "port", Type: numberType, Required: true},
+			{Name: "protocol", Type: stringType, Default: json.RawMessage(`"udp"`)},
+			{Name: "retries", Type: numberType, Default: json.RawMessage("3")},
+		},
+	}
+
+	definedVariable := entities.Variable{
+		Name: "rule",
+		Type: entities.Type{TFType: types.TerraformObject},
+		Attributes: []entities.Attribute{
+			{Name: "name", Type: stringType, Required: true},
+			{Name: "port", Type: numberType, Default: json.RawMessage("80")},
+			{Name: "protocol", Type: stringType, Default: json.RawMessage(`"tcp"`)},
+			{Name: "retries", Type: numberType, Default: json.RawMessage("3")},
+		},
+	}
+
+	def := definitionFromVariables(entities.VariableCollection{docVariable})
+	varsFile := variableFileFromVariables(entities.VariableCollection{definedVariable})
+
+	t.Run("Disabled", func(t *testing.T) {
+		got := varsvalidator.Validate(def, varsFile)
+
+		if !got.Success() {
+			t.Errorf("wanted optional attribute checks to be disabled, got %v", got.Results)
+		}
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		got := varsvalidator.ValidateWithOptions(def, varsFile, validators.Options{RequiredDefault: true, Defaults: true})
+
+		wantRequiredMismatch := []validators.ValueMismatchResult{
+			{Name: "var.rule.name", DefinedValue: "required", DocumentedValue: "optional"},
+			{Name: "var.rule.port", DefinedValue: "optional", DocumentedValue: "required"},
+		}
+
+		if diff := cmp.Diff(wantRequiredMismatch, got.ValueMismatches(validators.CodeAttributeRequiredMismatch)); diff != "" {
+			t.Errorf("Expected required mismatches to match (-want +got):\n%s", diff)
+		}
+
+		wantDefaultMismatch := []validators.ValueMismatchResult{
+			{Name: "var.rule.protocol", DefinedValue: `"tcp"`, DocumentedValue: `"udp"`},
+		}
+
+		if diff := cmp.Diff(wantDefaultMismatch, got.ValueMismatches(validators.CodeAttributeDefaultMismatch)); diff != "" {
+			t.Errorf("Expected default mismatches to match (-want +got):\n%s", diff)
+		}
+
+		if got.Success() {
+			t.Error("wanted optional attribute mismatches to fail the validation")
+		}
+	})
+}
+
+func TestValidateSensitive(t *testing.T) {
+	stringType := entities.Type{TFType: types.TerraformString}
+
+	docVariables := entities.VariableCollection{
+		{Name: "password", Type: stringType},
+		{Name: "token", Type: stringType, Sensitive: true},
+		{Name: "name", Type: stringType, Sensitive: true},
+	}
+
+	definedVariables := entities.VariableCollection{
+		{Name: "password", Type: stringType, Sensitive: true},
+		{Name: "token", Type: stringType, Sensitive: true},
+		{Name: "name", Type: stringType},
+	}
+
+	got := varsvalidator.Validate(definitionFromVariables(docVariables), variableFileFromVariables(definedVariables))
+
+	if !got.Success() {
+		t.Errorf("wanted sensitive variables to be checked only with the option, got %v", got.Names(validators.CodeSensitiveMismatch))
+	}
+
+	got = varsvalidator.ValidateWithOptions(definitionFromVariables(docVariables), variableFileFromVariables(definedVariables),
+		validators.Options{Sensitive: true})
+
+	if diff := cmp.Diff([]string{"password"}, got.Names(validators.CodeSensitiveMismatch)); diff != "" {
+		t.Errorf("Expected sensitive mismatches to match (-want +got):\n%s", diff)
+	}
+
+	if got.Success() {
+		t.Error("wanted undocumented sensitive variables to fail the validation")
+	}
+}
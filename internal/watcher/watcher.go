@@ -0,0 +1,76 @@
+// Package watcher reports changes of the files in a set of directories. Directories are watched
+// instead of files so changes of editors that save by writing a new file and renaming it over the
+// old one are reported, too.
+package watcher
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// Debounce collects the changed files sent on events until no change happens for the given delay
+// and sends them as one batch. The returned channel is closed when events is closed.
+func Debounce(events <-chan string, delay time.Duration) <-chan []string {
+	batches := make(chan []string)
+
+	go func() {
+		defer close(batches)
+
+		var (
+			pending []string
+			timer   *time.Timer
+			fire    <-chan time.Time
+		)
+
+		seen := map[string]bool{}
+
+		for {
+			select {
+			case name, ok := <-events:
+				if !ok {
+					if timer != nil {
+						timer.Stop()
+					}
+
+					return
+				}
+
+				if !seen[name] {
+					seen[name] = true
+					pending = append(pending, name)
+				}
+
+				if timer != nil {
+					timer.Stop()
+				}
+
+				timer = time.NewTimer(delay)
+				fire = timer.C
+			case <-fire:
+				batches <- pending
+
+				pending = nil
+				seen = map[string]bool{}
+				fire = nil
+			}
+		}
+	}()
+
+	return batches
+}
+
+// cleanDirs returns the absolute paths of the directories
+func cleanDirs(dirs []string) ([]string, error) {
+	result := make([]string, 0, len(dirs))
+
+	for _, dir := range dirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, abs)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,159 @@
+package watcher
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// watchMask are the inotify events reported as changes. Renames into and out of a directory are
+// reported for both names.
+const watchMask = syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY | syscall.IN_CREATE | syscall.IN_DELETE |
+	syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM
+
+// Watcher watches directories with inotify
+type Watcher struct {
+	fd     int
+	file   *os.File
+	mu     sync.Mutex
+	dirs   map[int32]string
+	events chan string
+	errors chan error
+	done   chan struct{}
+	once   sync.Once
+}
+
+// New returns a watcher reporting the changed files of the given directories but not of their
+// sub-directories
+func New(dirs ...string) (*Watcher, error) {
+	dirs, err := cleanDirs(dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	// a non-blocking descriptor is read through the runtime poller so Close stops a pending read
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("initializing inotify: %v", err)
+	}
+
+	w := &Watcher{
+		fd:     fd,
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		dirs:   map[int32]string{},
+		events: make(chan string),
+		errors: make(chan error),
+		done:   make(chan struct{}),
+	}
+
+	for _, dir := range dirs {
+		if err := w.add(dir); err != nil {
+			w.file.Close()
+			return nil, err
+		}
+	}
+
+	go w.read()
+
+	return w, nil
+}
+
+// Add starts watching the changed files of the given directories, too. Directories that are
+// already watched are ignored.
+func (w *Watcher) Add(dirs ...string) error {
+	dirs, err := cleanDirs(dirs)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range dirs {
+		if err := w.add(dir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *Watcher) add(dir string) error {
+	// adding a watched directory again returns its existing watch descriptor
+	wd, err := syscall.InotifyAddWatch(w.fd, dir, watchMask)
+	if err != nil {
+		return fmt.Errorf("watching %q: %v", dir, err)
+	}
+
+	w.mu.Lock()
+	w.dirs[int32(wd)] = dir
+	w.mu.Unlock()
+
+	return nil
+}
+
+// Events returns the channel on which the paths of changed files are sent
+func (w *Watcher) Events() <-chan string {
+	return w.events
+}
+
+// Errors returns the channel on which errors reading the changes are sent
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Close stops watching. The events channel is closed once the watcher stopped.
+func (w *Watcher) Close() error {
+	var err error
+
+	w.once.Do(func() {
+		close(w.done)
+		err = w.file.Close()
+	})
+
+	return err
+}
+
+func (w *Watcher) read() {
+	defer close(w.events)
+
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				select {
+				case w.errors <- fmt.Errorf("reading inotify events: %v", err):
+				case <-w.done:
+				}
+			}
+
+			return
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameStart := offset + syscall.SizeofInotifyEvent
+			name := strings.TrimRight(string(buf[nameStart:nameStart+int(event.Len)]), "\x00")
+
+			offset = nameStart + int(event.Len)
+
+			w.mu.Lock()
+			dir, ok := w.dirs[event.Wd]
+			w.mu.Unlock()
+
+			if !ok || name == "" || event.Mask&syscall.IN_ISDIR != 0 {
+				continue
+			}
+
+			select {
+			case w.events <- filepath.Join(dir, name):
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,203 @@
+//go:build !linux
+// +build !linux
+
+package watcher
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// pollInterval is the interval in which the directories are scanned for changes
+const pollInterval = 250 * time.Millisecond
+
+// Watcher watches directories by comparing the modification times and sizes of their files
+type Watcher struct {
+	mu     sync.Mutex
+	dirs   []string
+	added  map[string]fileState
+	events chan string
+	errors chan error
+	done   chan struct{}
+	once   sync.Once
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// New returns a watcher reporting the changed files of the given directories but not of their
+// sub-directories
+func New(dirs ...string) (*Watcher, error) {
+	dirs, err := cleanDirs(dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		dirs:   dirs,
+		events: make(chan string),
+		errors: make(chan error),
+		done:   make(chan struct{}),
+	}
+
+	state, err := w.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	go w.poll(state)
+
+	return w, nil
+}
+
+// Add starts watching the changed files of the given directories, too. Directories that are
+// already watched are ignored.
+func (w *Watcher) Add(dirs ...string) error {
+	dirs, err := cleanDirs(dirs)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, dir := range dirs {
+		if containsDir(w.dirs, dir) {
+			continue
+		}
+
+		// the files existing when the directory is added are not reported as changes
+		if w.added == nil {
+			w.added = map[string]fileState{}
+		}
+
+		if err := scanDir(dir, w.added); err != nil {
+			return err
+		}
+
+		w.dirs = append(w.dirs, dir)
+	}
+
+	return nil
+}
+
+// Events returns the channel on which the paths of changed files are sent
+func (w *Watcher) Events() <-chan string {
+	return w.events
+}
+
+// Errors returns the channel on which errors reading the changes are sent
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Close stops watching. The events channel is closed once the watcher stopped.
+func (w *Watcher) Close() error {
+	w.once.Do(func() { close(w.done) })
+	return nil
+}
+
+func (w *Watcher) poll(state map[string]fileState) {
+	defer close(w.events)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+
+		w.mu.Lock()
+		for path, s := range w.added {
+			state[path] = s
+		}
+		w.added = nil
+		w.mu.Unlock()
+
+		current, err := w.scan()
+		if err != nil {
+			select {
+			case w.errors <- err:
+			case <-w.done:
+				return
+			}
+
+			continue
+		}
+
+		var changed []string
+
+		for path, s := range current {
+			if old, ok := state[path]; !ok || old != s {
+				changed = append(changed, path)
+			}
+		}
+
+		for path := range state {
+			if _, ok := current[path]; !ok {
+				changed = append(changed, path)
+			}
+		}
+
+		state = current
+
+		for _, path := range changed {
+			select {
+			case w.events <- path:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+func (w *Watcher) scan() (map[string]fileState, error) {
+	state := map[string]fileState{}
+
+	w.mu.Lock()
+	dirs := w.dirs
+	w.mu.Unlock()
+
+	for _, dir := range dirs {
+		if err := scanDir(dir, state); err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
+}
+
+// scanDir adds the state of the files of dir to state
+func scanDir(dir string, state map[string]fileState) error {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+
+		state[filepath.Join(dir, info.Name())] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	return nil
+}
+
+func containsDir(dirs []string, dir string) bool {
+	for _, d := range dirs {
+		if d == dir {
+			return true
+		}
+	}
+
+	return false
+}
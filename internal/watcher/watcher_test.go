@@ -0,0 +1,108 @@
+package watcher_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/internal/watcher"
+)
+
+func TestWatchRename(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "README.tfdoc.hcl")
+	assert.NoError(t, ioutil.WriteFile(target, []byte("section {}"), 0644))
+
+	w, err := watcher.New(dir)
+	assert.NoError(t, err)
+	defer w.Close()
+
+	// editors like vim save by writing a new file and renaming it over the old one
+	tmp := filepath.Join(dir, ".README.tfdoc.hcl.tmp")
+	assert.NoError(t, ioutil.WriteFile(tmp, []byte("section { title = \"a\" }"), 0644))
+	assert.NoError(t, os.Rename(tmp, target))
+
+	timeout := time.After(5 * time.Second)
+
+	for {
+		select {
+		case name := <-w.Events():
+			if name == target {
+				return
+			}
+		case err := <-w.Errors():
+			t.Fatal(err)
+		case <-timeout:
+			t.Fatalf("timeout waiting for a change of %q", target)
+		}
+	}
+}
+
+func TestWatchAdd(t *testing.T) {
+	w, err := watcher.New(t.TempDir())
+	assert.NoError(t, err)
+	defer w.Close()
+
+	dir := t.TempDir()
+	assert.NoError(t, w.Add(dir))
+
+	target := filepath.Join(dir, "inputs.tfdoc.hcl")
+	assert.NoError(t, ioutil.WriteFile(target, []byte("section {}"), 0644))
+
+	timeout := time.After(5 * time.Second)
+
+	for {
+		select {
+		case name := <-w.Events():
+			if name == target {
+				return
+			}
+		case err := <-w.Errors():
+			t.Fatal(err)
+		case <-timeout:
+			t.Fatalf("timeout waiting for a change of %q", target)
+		}
+	}
+}
+
+func TestWatchClose(t *testing.T) {
+	w, err := watcher.New(t.TempDir())
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	select {
+	case _, ok := <-w.Events():
+		if ok {
+			t.Error("wanted no events after closing the watcher")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the events channel to be closed")
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	events := make(chan string)
+	batches := watcher.Debounce(events, 50*time.Millisecond)
+
+	events <- "a.tf"
+	events <- "b.tf"
+	events <- "a.tf"
+
+	select {
+	case batch := <-batches:
+		assert.EqualInts(t, 2, len(batch))
+		assert.EqualStrings(t, "a.tf", batch[0])
+		assert.EqualStrings(t, "b.tf", batch[1])
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the batch")
+	}
+
+	close(events)
+
+	if _, ok := <-batches; ok {
+		t.Error("wanted batches to be closed after events")
+	}
+}
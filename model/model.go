@@ -0,0 +1,79 @@
+// Package model exposes the terradoc document model.
+//
+// The types in this package are the stable, public counterparts of the
+// entities used internally by terradoc. They follow the module's semantic
+// versioning: fields may be added in minor releases but are never removed or
+// renamed outside of a major release. The fields of the entities are frozen
+// by the tests of this package, so internal refactors cannot change them.
+package model
+
+import (
+	"github.com/mineiros-io/terradoc/internal/entities"
+	"github.com/mineiros-io/terradoc/internal/types"
+)
+
+type (
+	// Doc represents a parsed .tfdoc.hcl document.
+	Doc = entities.Doc
+	// Header represents the `header` block of a document.
+	Header = entities.Header
+	// Badge represents a `badge` block inside the document header.
+	Badge = entities.Badge
+	// Section represents a `section` block of a document.
+	Section = entities.Section
+	// Variable represents a documented or defined Terraform variable.
+	Variable = entities.Variable
+	// VariableCollection is a list of variables that can be searched by name.
+	VariableCollection = entities.VariableCollection
+	// Attribute represents an `attribute` block of a variable or of another attribute.
+	Attribute = entities.Attribute
+	// Output represents a documented or defined Terraform output.
+	Output = entities.Output
+	// OutputCollection is a list of outputs that can be searched by name.
+	OutputCollection = entities.OutputCollection
+	// Reference represents a `ref` block inside the `references` block.
+	Reference = entities.Reference
+	// ReferenceUse is a use of a reference in the content or the descriptions of a document.
+	ReferenceUse = entities.ReferenceUse
+	// Constraint represents a `validation` block of a variable.
+	Constraint = entities.Constraint
+	// Requirements are the versions of Terraform and the providers required by a module.
+	Requirements = entities.Requirements
+	// ProviderRequirement is a provider required by a module.
+	ProviderRequirement = entities.ProviderRequirement
+	// Resource represents a `resource`, `data` or `module` block defined in Terraform files.
+	Resource = entities.Resource
+	// Type represents the type definition of a variable, attribute or output.
+	Type = entities.Type
+	// TerraformType identifies a Terraform type such as `string` or `list`.
+	TerraformType = types.TerraformType
+	// Definitions holds the variables, outputs, requirements and resources defined in Terraform files.
+	Definitions = entities.ValidationContents
+)
+
+// Terraform types that can be used in a Type definition.
+const (
+	TerraformEmptyType = types.TerraformEmptyType
+	TerraformBool      = types.TerraformBool
+	TerraformString    = types.TerraformString
+	TerraformNumber    = types.TerraformNumber
+	TerraformList      = types.TerraformList
+	TerraformSet       = types.TerraformSet
+	TerraformMap       = types.TerraformMap
+	TerraformObject    = types.TerraformObject
+	TerraformTuple     = types.TerraformTuple
+	TerraformAny       = types.TerraformAny
+	TerraformResource  = types.TerraformResource
+)
+
+// Kinds of the blocks a Resource can be.
+const (
+	ResourceKind   = entities.ResourceKind
+	DataSourceKind = entities.DataSourceKind
+	ModuleKind     = entities.ModuleKind
+)
+
+// ParseTerraformType returns the TerraformType for the given type name.
+func ParseTerraformType(typename string) (TerraformType, bool) {
+	return types.TerraformTypes(typename)
+}
@@ -0,0 +1,194 @@
+package model_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mineiros-io/terradoc/model"
+)
+
+// TestFieldsFrozen fails when a field of a public type is removed, renamed or changes its type.
+// Fields may only be added, see the package documentation.
+func TestFieldsFrozen(t *testing.T) {
+	for _, tc := range []struct {
+		value  interface{}
+		fields map[string]string
+	}{
+		{
+			value: model.Doc{},
+			fields: map[string]string{
+				"Header":        "entities.Header",
+				"Sections":      "[]entities.Section",
+				"References":    "[]entities.Reference",
+				"ReferenceUses": "[]entities.ReferenceUse",
+			},
+		},
+		{
+			value: model.Header{},
+			fields: map[string]string{
+				"Image":  "string",
+				"URL":    "string",
+				"Badges": "[]entities.Badge",
+			},
+		},
+		{
+			value: model.Badge{},
+			fields: map[string]string{
+				"Image": "string",
+				"URL":   "string",
+				"Text":  "string",
+				"Name":  "string",
+			},
+		},
+		{
+			value: model.Section{},
+			fields: map[string]string{
+				"Title":        "string",
+				"Content":      "string",
+				"Variables":    "[]entities.Variable",
+				"Outputs":      "[]entities.Output",
+				"Requirements": "*entities.Requirements",
+				"Generated":    "string",
+				"Resources":    "[]entities.Resource",
+				"SubSections":  "[]entities.Section",
+				"Level":        "int",
+				"TOC":          "bool",
+				"DefRange":     "hcl.Range",
+			},
+		},
+		{
+			value: model.Variable{},
+			fields: map[string]string{
+				"Name":             "string",
+				"Type":             "entities.Type",
+				"Description":      "string",
+				"Default":          "jsontext.Value",
+				"Required":         "bool",
+				"Sensitive":        "bool",
+				"Nullable":         "bool",
+				"Ephemeral":        "bool",
+				"ForcesRecreation": "bool",
+				"ReadmeExample":    "string",
+				"Attributes":       "[]entities.Attribute",
+				"Constraints":      "[]entities.Constraint",
+				"DefRange":         "hcl.Range",
+			},
+		},
+		{
+			value: model.Attribute{},
+			fields: map[string]string{
+				"Name":             "string",
+				"Type":             "entities.Type",
+				"Default":          "jsontext.Value",
+				"Description":      "string",
+				"ForcesRecreation": "bool",
+				"ReadmeExample":    "string",
+				"Required":         "bool",
+				"Attributes":       "[]entities.Attribute",
+				"Level":            "int",
+				"DefRange":         "hcl.Range",
+			},
+		},
+		{
+			value: model.Output{},
+			fields: map[string]string{
+				"Name":        "string",
+				"Type":        "entities.Type",
+				"Description": "string",
+				"Sensitive":   "bool",
+				"DependsOn":   "bool",
+				"DefRange":    "hcl.Range",
+			},
+		},
+		{
+			value: model.Reference{},
+			fields: map[string]string{
+				"Name":     "string",
+				"Value":    "string",
+				"DefRange": "hcl.Range",
+			},
+		},
+		{
+			value: model.ReferenceUse{},
+			fields: map[string]string{
+				"Name":     "string",
+				"Shortcut": "bool",
+				"Range":    "hcl.Range",
+			},
+		},
+		{
+			value: model.Constraint{},
+			fields: map[string]string{
+				"Condition":    "string",
+				"ErrorMessage": "string",
+				"DefRange":     "hcl.Range",
+			},
+		},
+		{
+			value: model.Requirements{},
+			fields: map[string]string{
+				"TerraformVersion": "string",
+				"Providers":        "[]entities.ProviderRequirement",
+				"FromCode":         "bool",
+				"DefRange":         "hcl.Range",
+			},
+		},
+		{
+			value: model.ProviderRequirement{},
+			fields: map[string]string{
+				"Name":     "string",
+				"Source":   "string",
+				"Version":  "string",
+				"DefRange": "hcl.Range",
+			},
+		},
+		{
+			value: model.Resource{},
+			fields: map[string]string{
+				"Kind":     "string",
+				"Type":     "string",
+				"Name":     "string",
+				"Provider": "string",
+				"Source":   "string",
+				"Count":    "bool",
+				"ForEach":  "bool",
+				"URL":      "string",
+				"DefRange": "hcl.Range",
+			},
+		},
+		{
+			value: model.Type{},
+			fields: map[string]string{
+				"TFType":   "types.TerraformType",
+				"Label":    "string",
+				"Nested":   "*entities.Type",
+				"Fields":   "map[string]entities.Type",
+				"Elements": "[]entities.Type",
+				"Optional": "bool",
+				"Default":  "jsontext.Value",
+			},
+		},
+		{
+			value: model.Definitions{},
+			fields: map[string]string{
+				"Variables":    "entities.VariableCollection",
+				"Outputs":      "entities.OutputCollection",
+				"Requirements": "entities.Requirements",
+				"Resources":    "[]entities.Resource",
+			},
+		},
+	} {
+		typ := reflect.TypeOf(tc.value)
+		for name, want := range tc.fields {
+			field, ok := typ.FieldByName(name)
+			if !ok {
+				t.Errorf("%s: field %s was removed", typ.Name(), name)
+				continue
+			}
+
+			if got := field.Type.String(); got != want {
+				t.Errorf("%s: field %s has type %s, want %s", typ.Name(), name, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Package parse reads terradoc documents and Terraform files into the types
+// defined by the model package.
+package parse
+
+import (
+	"io"
+
+	"github.com/mineiros-io/terradoc/internal/detect"
+	"github.com/mineiros-io/terradoc/internal/parsers/docparser"
+	"github.com/mineiros-io/terradoc/internal/parsers/validationparser"
+	"github.com/mineiros-io/terradoc/model"
+)
+
+// DocOptions sets the values of `module.version`, `module.source` and the .tf
+// files of the module used by the expressions of a document.
+type DocOptions = docparser.Options
+
+// DetectDocOptions returns opts with the values that are not set detected for
+// the documents in the module directory dir. It runs git and the returned
+// options read the .tf files of dir, so parsing the documents of a module
+// repeatedly detects the values once.
+func DetectDocOptions(dir string, opts DocOptions) DocOptions {
+	return detect.Options(dir, opts)
+}
+
+// Doc parses a .tfdoc.hcl document read from r. Included files are read
+// relative to the directory of filename. Git and the .tf files of the module
+// are not used, see DocWithOptions and DetectDocOptions.
+func Doc(r io.Reader, filename string) (model.Doc, error) {
+	return docparser.Parse(r, filename)
+}
+
+// DocWithOptions parses a .tfdoc.hcl document like Doc with the module values
+// set in opts.
+func DocWithOptions(r io.Reader, filename string, opts DocOptions) (model.Doc, error) {
+	return docparser.ParseWithOptions(r, filename, opts)
+}
+
+// TerraformOptions selects the blocks parsed from a Terraform file.
+type TerraformOptions struct {
+	// Variables enables parsing the `variable` blocks
+	Variables bool
+	// Outputs enables parsing the `output` blocks
+	Outputs bool
+	// Requirements enables parsing the `required_version` and `required_providers` of `terraform`
+	// blocks
+	Requirements bool
+	// Resources enables parsing the `resource`, `data` and `module` blocks
+	Resources bool
+}
+
+// Terraform parses the blocks of a Terraform file read from r that are
+// enabled in opts.
+func Terraform(r io.Reader, filename string, opts TerraformOptions) (model.Definitions, error) {
+	return validationparser.Parse(r, filename, validationparser.Options{
+		Variables:    opts.Variables,
+		Outputs:      opts.Outputs,
+		Requirements: opts.Requirements,
+		Resources:    opts.Resources,
+	})
+}
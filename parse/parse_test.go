@@ -0,0 +1,44 @@
+package parse_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/model"
+	"github.com/mineiros-io/terradoc/parse"
+	"github.com/mineiros-io/terradoc/test"
+)
+
+func TestDoc(t *testing.T) {
+	r := test.OpenFixture(t, "parser-input.tfdoc.hcl")
+
+	doc, err := parse.Doc(r, "parser-input.tfdoc.hcl")
+	assert.NoError(t, err)
+
+	variables := model.VariableCollection(doc.AllVariables())
+
+	beers, ok := variables.VarByName("beers")
+	if !ok {
+		t.Fatalf("Expected variable %q to be parsed", "beers")
+	}
+
+	assert.EqualStrings(t, "list(object(beer))", beers.Type.AsString())
+}
+
+func TestTerraform(t *testing.T) {
+	content := test.ReadFixture(t, "validate/variables/complete-variables.tf")
+
+	defs, err := parse.Terraform(bytes.NewBuffer(content), "variables.tf", parse.TerraformOptions{Variables: true})
+	assert.NoError(t, err)
+
+	assert.EqualInts(t, 4, len(defs.Variables))
+	assert.EqualInts(t, 0, len(defs.Outputs))
+
+	number, ok := defs.Variables.VarByName("number")
+	if !ok {
+		t.Fatalf("Expected variable %q to be parsed", "number")
+	}
+
+	assert.EqualStrings(t, model.TerraformNumber.String(), number.Type.TFType.String())
+}
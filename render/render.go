@@ -0,0 +1,36 @@
+// Package render writes terradoc documents in the supported output formats.
+package render
+
+import (
+	"io"
+
+	"github.com/mineiros-io/terradoc/internal/renderers/html"
+	jsonrenderer "github.com/mineiros-io/terradoc/internal/renderers/json"
+	"github.com/mineiros-io/terradoc/internal/renderers/markdown"
+	"github.com/mineiros-io/terradoc/model"
+)
+
+// Markdown renders doc as markdown into w using the embedded templates.
+func Markdown(w io.Writer, doc model.Doc) error {
+	return markdown.Render(w, doc)
+}
+
+// MarkdownWithTemplates renders doc as markdown into w using the embedded templates overlaid by
+// the `*.md` templates in templatesDir. A template file must define the template it is named after,
+// e.g. `variable.md` must define "variable".
+func MarkdownWithTemplates(w io.Writer, doc model.Doc, templatesDir string) error {
+	return markdown.RenderWithTemplates(w, doc, templatesDir)
+}
+
+// JSON renders doc into w as a JSON document whose format is identified by JSONSchemaVersion.
+func JSON(w io.Writer, doc model.Doc) error {
+	return jsonrenderer.Render(w, doc)
+}
+
+// HTML renders doc into w as a single self-contained HTML page.
+func HTML(w io.Writer, doc model.Doc) error {
+	return html.Render(w, doc)
+}
+
+// JSONSchemaVersion is the version of the JSON format written by JSON.
+const JSONSchemaVersion = jsonrenderer.SchemaVersion
@@ -0,0 +1,42 @@
+package render_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/madlambda/spells/assert"
+	"github.com/mineiros-io/terradoc/parse"
+	"github.com/mineiros-io/terradoc/render"
+	"github.com/mineiros-io/terradoc/test"
+)
+
+func TestMarkdown(t *testing.T) {
+	doc, err := parse.Doc(test.OpenFixture(t, "golden-input.tfdoc.hcl"), "golden-input.tfdoc.hcl")
+	assert.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	err = render.Markdown(buf, doc)
+	assert.NoError(t, err)
+
+	want := test.ReadFixture(t, "golden-readme.md")
+
+	if diff := cmp.Diff(want, buf.Bytes()); diff != "" {
+		t.Errorf("Result is not expected (-want +got):\n%s", diff)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	doc, err := parse.Doc(test.OpenFixture(t, "golden-input.tfdoc.hcl"), "golden-input.tfdoc.hcl")
+	assert.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+	err = render.HTML(buf, doc)
+	assert.NoError(t, err)
+
+	want := test.ReadFixture(t, "golden-readme.html")
+
+	if diff := cmp.Diff(want, buf.Bytes()); diff != "" {
+		t.Errorf("Result is not expected (-want +got):\n%s", diff)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io/fs"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/madlambda/spells/assert"
 	"github.com/mineiros-io/terradoc/internal/entities"
 	"github.com/mineiros-io/terradoc/internal/validators"
@@ -30,12 +31,8 @@ func OpenFixture(t *testing.T, filename string) fs.File {
 func AssertEqualTypes(t *testing.T, want, got entities.Type) {
 	t.Helper()
 
-	assert.EqualStrings(t, want.TFType.String(), got.TFType.String())
-	assert.EqualStrings(t, want.Label, got.Label)
-
-	if want.Nested != nil {
-		assert.EqualStrings(t, want.Nested.TFType.String(), got.Nested.TFType.String())
-		assert.EqualStrings(t, want.Nested.Label, got.Nested.Label)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Expected type to match (-want +got):\n%s", diff)
 	}
 }
 
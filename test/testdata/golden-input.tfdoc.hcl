@@ -120,6 +120,11 @@ END
         variable "role" {
           type        = string
           description = "The role that should be applied. Note that custom roles must be of the format `[projects|organizations]/{parent-name}/roles/{role-name}`."
+
+          validation {
+            condition     = can(regex("^(roles|projects|organizations)/", var.role))
+            error_message = "The role must start with roles/, projects/ or organizations/."
+          }
         }
 
         variable "project" {
@@ -0,0 +1,88 @@
+header {
+  image = ""
+  url   = ""
+}
+
+section {
+  title = "terraform-aws-s3-bucket"
+  toc   = true
+
+  section {
+    title = "Module Description"
+  }
+
+  section {
+    title = "Getting Started"
+  }
+
+  section {
+    title = "Module Argument Reference"
+
+    section {
+      title = "Required Arguments"
+
+      variable "name" {
+        type        = string
+        required    = true
+        description = "The name of the bucket."
+      }
+    }
+
+    section {
+      title = "Optional Arguments"
+
+      variable "tags" {
+        type        = map(string)
+        default     = {}
+        description = "A map of tags to apply to the bucket."
+      }
+
+      variable "lifecycle_rules" {
+        type        = list(lifecycle_rules)
+        default     = []
+        description = <<END
+A list of lifecycle rules.
+Each rule is applied to the bucket.
+END
+
+        attribute "id" {
+          type        = string
+          required    = true
+          description = ""
+        }
+
+        attribute "enabled" {
+          type        = bool
+          required    = true
+          description = ""
+        }
+
+        attribute "expiration" {
+          type        = object(expiration)
+          required    = true
+          description = ""
+
+          attribute "days" {
+            type        = number
+            required    = true
+            description = ""
+          }
+        }
+      }
+    }
+  }
+
+  section {
+    title = "Module Outputs"
+
+    output "id" {
+      type        = any
+      description = "The ID of the bucket."
+    }
+
+    output "bucket" {
+      type        = any
+      description = "The bucket name and ARN."
+    }
+  }
+}
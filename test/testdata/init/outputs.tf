@@ -0,0 +1,12 @@
+output "id" {
+  description = "The ID of the bucket."
+  value       = aws_s3_bucket.bucket.id
+}
+
+output "bucket" {
+  description = "The bucket name and ARN."
+  value = {
+    name = aws_s3_bucket.bucket.bucket
+    arn  = aws_s3_bucket.bucket.arn
+  }
+}
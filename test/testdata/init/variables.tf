@@ -0,0 +1,25 @@
+variable "name" {
+  type        = string
+  description = "The name of the bucket."
+}
+
+variable "tags" {
+  type        = map(string)
+  description = "A map of tags to apply to the bucket."
+  default     = {}
+}
+
+variable "lifecycle_rules" {
+  type = list(object({
+    id      = string
+    enabled = bool
+    expiration = object({
+      days = number
+    })
+  }))
+  description = <<-EOT
+    A list of lifecycle rules.
+    Each rule is applied to the bucket.
+  EOT
+  default     = []
+}
@@ -0,0 +1,45 @@
+# this comment must be kept
+section {
+  title = "Module"
+
+  section {
+    title = "Arguments"
+
+    # hand-written notes about the name variable
+    variable "name" {
+      type        = string
+      description = "The name of the bucket."
+    }
+
+    variable "count" {
+      type        = number
+      description = "Documented with the wrong type."
+    }
+
+    variable "removed" {
+      # terradoc:stale: "removed" is not defined in any .tf files
+      type        = bool
+      description = "Not defined anymore."
+    }
+
+    variable "tags" {
+      type        = map(string)
+      default     = {}
+      description = "A map of tags."
+    }
+  }
+
+  section {
+    title = "Outputs"
+
+    output "id" {
+      type        = string
+      description = "The ID of the bucket."
+    }
+
+    output "arn" {
+      type        = any
+      description = "The ARN of the bucket."
+    }
+  }
+}
@@ -0,0 +1,33 @@
+# this comment must be kept
+section {
+  title = "Module"
+
+  section {
+    title = "Arguments"
+
+    # hand-written notes about the name variable
+    variable "name" {
+      type        = string
+      description = "The name of the bucket."
+    }
+
+    variable "count" {
+      type        = string
+      description = "Documented with the wrong type."
+    }
+
+    variable "removed" {
+      type        = bool
+      description = "Not defined anymore."
+    }
+  }
+
+  section {
+    title = "Outputs"
+
+    output "id" {
+      type        = string
+      description = "The ID of the bucket."
+    }
+  }
+}
@@ -0,0 +1,22 @@
+variable "name" {
+  type = string
+}
+
+variable "count" {
+  type = number
+}
+
+variable "tags" {
+  type        = map(string)
+  description = "A map of tags."
+  default     = {}
+}
+
+output "id" {
+  value = aws_s3_bucket.bucket.id
+}
+
+output "arn" {
+  description = "The ARN of the bucket."
+  value       = aws_s3_bucket.bucket.arn
+}
@@ -0,0 +1,7 @@
+section {
+  title = "Example Module"
+}
+
+include "inputs.tfdoc.hcl" {}
+
+include "outputs.tfdoc.hcl" {}
@@ -0,0 +1,8 @@
+section {
+  title = "Inputs"
+
+  variable "name" {
+    type        = number
+    description = "The name of the bucket."
+  }
+}
@@ -0,0 +1,9 @@
+variable "name" {
+  type        = string
+  description = "The name of the bucket."
+}
+
+output "id" {
+  description = "The ID of the bucket."
+  value       = "bucket"
+}
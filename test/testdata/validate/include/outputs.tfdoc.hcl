@@ -0,0 +1,8 @@
+section {
+  title = "Outputs"
+
+  output "id" {
+    type        = string
+    description = "The ID of the bucket."
+  }
+}
@@ -10,7 +10,8 @@ section {
   }
 
   output "beer" {
-    type = string
+    type      = string
+    sensitive = true
   }
 
   section {
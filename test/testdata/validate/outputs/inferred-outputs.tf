@@ -0,0 +1,21 @@
+output "bucket" {
+  description = "The S3 bucket."
+  value       = aws_s3_bucket.this
+}
+
+output "policy" {
+  description = "The S3 bucket policy."
+  value       = aws_s3_bucket_policy.this
+}
+
+output "id" {
+  description = "The ID of the S3 bucket."
+  value       = aws_s3_bucket.this.id
+}
+
+output "password" {
+  description = "The generated password."
+  value       = random_password.this.result
+  sensitive   = true
+  depends_on  = [aws_s3_bucket_policy.this]
+}
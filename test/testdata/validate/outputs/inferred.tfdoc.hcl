@@ -0,0 +1,21 @@
+section {
+  output "bucket" {
+    type        = resource(aws_s3_bucket)
+    description = "The S3 bucket."
+  }
+
+  output "policy" {
+    type        = resource(aws_s3_bucket)
+    description = "The S3 bucket policy."
+  }
+
+  output "id" {
+    type        = number
+    description = "The ID of the S3 bucket."
+  }
+
+  output "password" {
+    type        = string
+    description = "The generated password."
+  }
+}
@@ -0,0 +1,26 @@
+section {
+  title   = "references"
+  content = <<-END
+    Read the [documentation][docs] and the [changelog][].
+    Releases are published at ${ref.releases}.
+  END
+
+  variable "name" {
+    type        = string
+    description = "The name, see [naming rules][naming]."
+  }
+}
+
+references {
+  ref "docs" {
+    value = "https://example.com/docs"
+  }
+
+  ref "releases" {
+    value = "https://example.com/releases"
+  }
+
+  ref "slack" {
+    value = "https://example.com/slack"
+  }
+}
@@ -0,0 +1,16 @@
+section {
+  title = "Requirements"
+
+  requirements {
+    terraform = ">= 0.15"
+
+    provider "aws" {
+      source  = "hashicorp/aws"
+      version = ">= 4.0"
+    }
+
+    provider "google" {
+      version = "~> 4.0"
+    }
+  }
+}
@@ -0,0 +1,8 @@
+section {
+  title = "Example Module"
+
+  variable "name" {
+    type        = string
+    description = "The name of the bucket."
+  }
+}
@@ -0,0 +1,15 @@
+terraform {
+  required_providers {
+    aws = { source = ["hashicorp/aws"] }
+  }
+}
+
+variable "name" {
+  type        = string
+  description = "The name of the bucket."
+}
+
+resource "aws_s3_bucket" "this" {
+  provider = "aws"
+  bucket   = var.name
+}
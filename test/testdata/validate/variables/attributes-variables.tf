@@ -0,0 +1,7 @@
+variable "rules" {
+  type = list(object({
+    name     = string
+    ports    = list(number)
+    protocol = string
+  }))
+}
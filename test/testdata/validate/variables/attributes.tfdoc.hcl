@@ -0,0 +1,17 @@
+section {
+  variable "rules" {
+    type = list(rule)
+
+    attribute "name" {
+      type = string
+    }
+
+    attribute "ports" {
+      type = list(string)
+    }
+
+    attribute "priority" {
+      type = number
+    }
+  }
+}
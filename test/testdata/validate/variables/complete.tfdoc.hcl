@@ -10,7 +10,8 @@ section {
   }
 
   variable "beer" {
-    type = string
+    type      = string
+    sensitive = true
   }
 
   section {
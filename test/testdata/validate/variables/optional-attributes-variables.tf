@@ -0,0 +1,7 @@
+variable "rules" {
+  type = list(object({
+    name     = string
+    port     = optional(number, 80)
+    protocol = optional(string, "tcp")
+  }))
+}
@@ -0,0 +1,19 @@
+section {
+  variable "rules" {
+    type = list(rule)
+
+    attribute "name" {
+      type     = string
+      required = true
+    }
+
+    attribute "port" {
+      type     = number
+      required = true
+    }
+
+    attribute "protocol" {
+      type = optional(string, "udp")
+    }
+  }
+}
@@ -0,0 +1,10 @@
+variable "name" {
+  type        = string
+  description = "The bucket name."
+  default     = "beer"
+}
+
+variable "retries" {
+  type    = number
+  default = 3
+}
@@ -0,0 +1,12 @@
+section {
+  variable "name" {
+    type        = string
+    required    = true
+    description = "The name of the bucket."
+  }
+
+  variable "retries" {
+    type    = number
+    default = 5
+  }
+}
@@ -0,0 +1,64 @@
+// Package validate checks that a terradoc document is synchronized with the
+// variables, outputs and requirements defined in Terraform files.
+package validate
+
+import (
+	"github.com/mineiros-io/terradoc/internal/validators"
+	"github.com/mineiros-io/terradoc/internal/validators/outputsvalidator"
+	"github.com/mineiros-io/terradoc/internal/validators/refsvalidator"
+	"github.com/mineiros-io/terradoc/internal/validators/requirementsvalidator"
+	"github.com/mineiros-io/terradoc/internal/validators/varsvalidator"
+	"github.com/mineiros-io/terradoc/model"
+)
+
+type (
+	// Summary holds the result of a validation.
+	Summary = validators.Summary
+	// TypeMismatchResult describes a type that differs between document and definition.
+	TypeMismatchResult = validators.TypeMismatchResult
+	// ValueMismatchResult describes a default or description that differs between document and definition.
+	ValueMismatchResult = validators.ValueMismatchResult
+	// Options enables optional checks, e.g. of defaults and descriptions.
+	Options = validators.Options
+	// Result is a single problem found by a validation and its source position.
+	Result = validators.Result
+)
+
+// Variables validates the variables documented in doc against defs.
+func Variables(doc model.Doc, defs model.Definitions) Summary {
+	return varsvalidator.Validate(doc, defs)
+}
+
+// VariablesWithOptions validates the variables documented in doc against defs with the optional
+// checks enabled in opts.
+func VariablesWithOptions(doc model.Doc, defs model.Definitions, opts Options) Summary {
+	return varsvalidator.ValidateWithOptions(doc, defs, opts)
+}
+
+// Outputs validates the outputs documented in doc against defs.
+func Outputs(doc model.Doc, defs model.Definitions) Summary {
+	return outputsvalidator.Validate(doc, defs)
+}
+
+// OutputsWithOptions validates the outputs documented in doc against defs with the optional
+// checks enabled in opts.
+func OutputsWithOptions(doc model.Doc, defs model.Definitions, opts Options) Summary {
+	return outputsvalidator.ValidateWithOptions(doc, defs, opts)
+}
+
+// References validates that the references used in the content and the descriptions of doc are
+// defined and that all defined references are used.
+func References(doc model.Doc) Summary {
+	return refsvalidator.Validate(doc)
+}
+
+// Requirements validates the Terraform and provider requirements listed in the `requirements`
+// blocks of doc against the requirements in defs.
+func Requirements(doc model.Doc, defs model.Definitions) Summary {
+	return requirementsvalidator.Validate(doc, defs)
+}
+
+// TypesMatch reports whether two type definitions are compatible.
+func TypesMatch(a, b model.Type) bool {
+	return validators.TypesMatch(&a, &b)
+}
@@ -0,0 +1,58 @@
+package validate_test
+
+import (
+	"testing"
+
+	"github.com/mineiros-io/terradoc/model"
+	"github.com/mineiros-io/terradoc/test"
+	"github.com/mineiros-io/terradoc/validate"
+)
+
+func TestVariables(t *testing.T) {
+	doc := model.Doc{
+		Sections: []model.Section{
+			{
+				Variables: []model.Variable{
+					{Name: "name", Type: model.Type{TFType: model.TerraformString}},
+					{Name: "age", Type: model.Type{TFType: model.TerraformString}},
+				},
+			},
+		},
+	}
+
+	defs := model.Definitions{
+		Variables: model.VariableCollection{
+			{Name: "age", Type: model.Type{TFType: model.TerraformNumber}},
+			{Name: "birth", Type: model.Type{TFType: model.TerraformBool}},
+		},
+	}
+
+	got := validate.Variables(doc, defs)
+
+	if got.Success() {
+		t.Fatal("Expected validation to fail")
+	}
+
+	test.AssertHasStrings(t, []string{"name"}, got.MissingDefinition)
+	test.AssertHasStrings(t, []string{"birth"}, got.MissingDocumentation)
+	test.AssertHasTypeMismatches(t, []validate.TypeMismatchResult{
+		{Name: "age", DefinedType: "number", DocumentedType: "string"},
+	}, got.TypeMismatch)
+}
+
+func TestOutputs(t *testing.T) {
+	doc := model.Doc{
+		Sections: []model.Section{
+			{Outputs: []model.Output{{Name: "id"}}},
+		},
+	}
+
+	defs := model.Definitions{
+		Outputs: model.OutputCollection{{Name: "id"}, {Name: "arn"}},
+	}
+
+	got := validate.Outputs(doc, defs)
+
+	test.AssertHasStrings(t, []string{"arn"}, got.MissingDocumentation)
+	test.AssertHasStrings(t, []string{}, got.MissingDefinition)
+}